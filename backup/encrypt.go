@@ -0,0 +1,138 @@
+package backup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrPlaintextKeys Returned by stores asked to persist a snapshot holding unencrypted
+// private keys.
+var ErrPlaintextKeys = errors.New("snapshot holds plaintext private keys, seal them with an Encrypter")
+
+// ErrSealedKeys Returned by Restore for snapshots whose private keys are still sealed.
+var ErrSealedKeys = errors.New("snapshot private keys are sealed, open them with OpenKeys")
+
+// Encrypter Encrypts and authenticates secrets stored in snapshots, such as DNSSEC
+// private keys.
+type Encrypter interface {
+	Encrypt(plaintext []byte) ([]byte, error)
+	Decrypt(ciphertext []byte) ([]byte, error)
+}
+
+// AESGCM Encrypter using AES in GCM mode, with a random nonce prefixed to each ciphertext.
+type AESGCM struct {
+	aead cipher.AEAD
+}
+
+// NewAESGCM Returns an AES-GCM encrypter for a 16, 24 or 32 byte key; 32 bytes selects
+// AES-256.
+func NewAESGCM(key []byte) (*AESGCM, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid encryption key: %w", err)
+	}
+
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AESGCM{aead: aead}, nil
+}
+
+// Encrypt Returns the nonce followed by the sealed plaintext.
+func (encrypter *AESGCM) Encrypt(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, encrypter.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return encrypter.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// Decrypt Returns the plaintext of ciphertext, failing when it was tampered with or
+// encrypted with another key.
+func (encrypter *AESGCM) Decrypt(ciphertext []byte) ([]byte, error) {
+	size := encrypter.aead.NonceSize()
+	if len(ciphertext) < size {
+		return nil, errors.New("Invalid ciphertext: too short")
+	}
+
+	plaintext, err := encrypter.aead.Open(nil, ciphertext[:size], ciphertext[size:], nil)
+	if err != nil {
+		return nil, fmt.Errorf("Error decrypting: %w", err)
+	}
+
+	return plaintext, nil
+}
+
+// SealKeys Encrypts the private keys of the snapshot cryptokeys with encrypter into
+// SealedPrivateKeys and removes them from the keys.
+func (snapshot *Snapshot) SealKeys(encrypter Encrypter) error {
+	if !snapshot.HasPrivateKeys() {
+		return nil
+	}
+
+	privateKeys := make([]string, len(snapshot.Cryptokeys))
+	for i, key := range snapshot.Cryptokeys {
+		privateKeys[i] = key.PrivateKey
+	}
+
+	plaintext, err := json.Marshal(privateKeys)
+	if err != nil {
+		return err
+	}
+
+	sealed, err := encrypter.Encrypt(plaintext)
+	if err != nil {
+		return fmt.Errorf("Error sealing private keys of snapshot: %s of zone: %s, %w", snapshot.ID(), snapshot.Zone, err)
+	}
+
+	snapshot.SealedPrivateKeys = sealed
+	for i := range snapshot.Cryptokeys {
+		snapshot.Cryptokeys[i].PrivateKey = ""
+	}
+
+	return nil
+}
+
+// OpenKeys Decrypts SealedPrivateKeys with encrypter back into the snapshot cryptokeys.
+func (snapshot *Snapshot) OpenKeys(encrypter Encrypter) error {
+	if len(snapshot.SealedPrivateKeys) == 0 {
+		return nil
+	}
+
+	plaintext, err := encrypter.Decrypt(snapshot.SealedPrivateKeys)
+	if err != nil {
+		return fmt.Errorf("Error opening private keys of snapshot: %s of zone: %s, %w", snapshot.ID(), snapshot.Zone, err)
+	}
+
+	var privateKeys []string
+	if err = json.Unmarshal(plaintext, &privateKeys); err != nil {
+		return err
+	}
+	if len(privateKeys) != len(snapshot.Cryptokeys) {
+		return fmt.Errorf("Error opening private keys of snapshot: %s of zone: %s, %d keys sealed for %d cryptokeys",
+			snapshot.ID(), snapshot.Zone, len(privateKeys), len(snapshot.Cryptokeys))
+	}
+
+	for i, privateKey := range privateKeys {
+		snapshot.Cryptokeys[i].PrivateKey = privateKey
+	}
+	snapshot.SealedPrivateKeys = nil
+
+	return nil
+}
+
+// Refuses snapshots that would put private keys in plaintext into a store
+func checkSealed(snapshot *Snapshot) error {
+	if snapshot.HasPrivateKeys() {
+		return fmt.Errorf("Error storing snapshot: %s of zone: %s, %w", snapshot.ID(), snapshot.Zone, ErrPlaintextKeys)
+	}
+
+	return nil
+}
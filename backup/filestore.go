@@ -0,0 +1,89 @@
+package backup
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// FileStore SnapshotStore keeping one JSON file per snapshot under Dir/<zone>/.
+type FileStore struct {
+	Dir string
+}
+
+// NewFileStore Returns a FileStore rooted at dir.
+func NewFileStore(dir string) *FileStore {
+	return &FileStore{Dir: dir}
+}
+
+// Put Writes the snapshot atomically, refusing plaintext private keys with ErrPlaintextKeys.
+func (store *FileStore) Put(snapshot *Snapshot) error {
+	if err := checkSealed(snapshot); err != nil {
+		return err
+	}
+
+	dir := filepath.Join(store.Dir, snapshot.Zone)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(dir, ".snapshot-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filepath.Join(dir, snapshot.ID()+".json"))
+}
+
+// Get Reads a snapshot of zone.
+func (store *FileStore) Get(zone string, id string) (*Snapshot, error) {
+	data, err := os.ReadFile(filepath.Join(store.Dir, zone, id+".json"))
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := new(Snapshot)
+	if err = json.Unmarshal(data, snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// List Returns the snapshot IDs of zone, oldest first.
+func (store *FileStore) List(zone string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(store.Dir, zone))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() && strings.HasSuffix(name, ".json") && !strings.HasPrefix(name, ".") {
+			ids = append(ids, strings.TrimSuffix(name, ".json"))
+		}
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
@@ -0,0 +1,244 @@
+package backup
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+// S3Store SnapshotStore backed by an S3 compatible object store (AWS S3, MinIO, Ceph...),
+// using path-style requests signed with AWS signature version 4.
+type S3Store struct {
+	Endpoint  string // e.g. "https://s3.eu-west-1.amazonaws.com" or "http://minio:9000"
+	Region    string
+	Bucket    string
+	Prefix    string // optional key prefix, e.g. "pdns-snapshots"
+	AccessKey string
+	SecretKey string
+	HTTP      *http.Client
+}
+
+type listBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+// Put Uploads the snapshot as a JSON object, refusing plaintext private keys with ErrPlaintextKeys.
+func (store *S3Store) Put(snapshot *Snapshot) error {
+	if err := checkSealed(snapshot); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	resp, err := store.request("PUT", store.key(snapshot.Zone, snapshot.ID()), nil, data)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return fmt.Errorf("Error storing snapshot: %s of zone: %s, status: %d", snapshot.ID(), snapshot.Zone, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// Get Downloads a snapshot of zone.
+func (store *S3Store) Get(zone string, id string) (*Snapshot, error) {
+	resp, err := store.request("GET", store.key(zone, id), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Error reading snapshot: %s of zone: %s, status: %d", id, zone, resp.StatusCode)
+	}
+
+	snapshot := new(Snapshot)
+	if err = json.NewDecoder(resp.Body).Decode(snapshot); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// List Returns the snapshot IDs of zone, oldest first.
+func (store *S3Store) List(zone string) ([]string, error) {
+	prefix := store.key(zone, "")
+	prefix = strings.TrimSuffix(prefix, ".json")
+
+	var ids []string
+	token := ""
+	for {
+		query := url.Values{"list-type": {"2"}, "prefix": {prefix}}
+		if token != "" {
+			query.Set("continuation-token", token)
+		}
+
+		resp, err := store.request("GET", "", query, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		result := new(listBucketResult)
+		if resp.StatusCode != 200 {
+			resp.Body.Close()
+			return nil, fmt.Errorf("Error listing snapshots of zone: %s, status: %d", zone, resp.StatusCode)
+		}
+		err = xml.NewDecoder(resp.Body).Decode(result)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, object := range result.Contents {
+			id := strings.TrimSuffix(strings.TrimPrefix(object.Key, prefix), ".json")
+			if id != "" && !strings.Contains(id, "/") {
+				ids = append(ids, id)
+			}
+		}
+
+		if !result.IsTruncated {
+			break
+		}
+		token = result.NextContinuationToken
+	}
+	sort.Strings(ids)
+
+	return ids, nil
+}
+
+func (store *S3Store) key(zone string, id string) string {
+	return path.Join(store.Prefix, zone) + "/" + id + ".json"
+}
+
+func (store *S3Store) request(method string, key string, query url.Values, body []byte) (*http.Response, error) {
+	endpoint, err := url.Parse(store.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.Path = "/" + store.Bucket
+	if key != "" {
+		endpoint.Path += "/" + key
+	}
+	endpoint.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequest(method, endpoint.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	store.sign(req, body, time.Now().UTC())
+
+	httpClient := store.HTTP
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	return httpClient.Do(req)
+}
+
+// Signs the request with AWS signature version 4
+func (store *S3Store) sign(req *http.Request, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsEscape(req.URL.Path, false),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host + "\n" +
+			"x-amz-content-sha256:" + payloadHash + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		"host;x-amz-content-sha256;x-amz-date",
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + store.Region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+store.SecretKey), date)
+	key = hmacSHA256(key, store.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=host;x-amz-content-sha256;x-amz-date, Signature=%s",
+		store.AccessKey, scope, signature))
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range query[key] {
+			parts = append(parts, awsEscape(key, true)+"="+awsEscape(value, true))
+		}
+	}
+
+	return strings.Join(parts, "&")
+}
+
+// Escapes s the way SigV4 expects: every byte except unreserved characters is
+// percent-encoded, and "/" is kept in paths
+func awsEscape(s string, encodeSlash bool) string {
+	var buf strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			buf.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			buf.WriteByte(c)
+		default:
+			fmt.Fprintf(&buf, "%%%02X", c)
+		}
+	}
+
+	return buf.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	io.WriteString(mac, data)
+	return mac.Sum(nil)
+}
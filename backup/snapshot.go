@@ -0,0 +1,215 @@
+// Package backup takes point-in-time snapshots of zones, stores them through a
+// SnapshotStore and restores them, turning the client into a small DNS backup agent.
+package backup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// snapshotIDLayout time layout of snapshot identifiers, sortable as strings.
+const snapshotIDLayout = "20060102T150405Z"
+
+// Snapshot Point-in-time copy of the record sets, metadata and DNSSEC keys of a zone.
+type Snapshot struct {
+	Zone       string                    `json:"zone"`
+	TakenAt    time.Time                 `json:"taken_at"`
+	RecordSets []types.ResourceRecordSet `json:"rrsets"`
+	// Metadata is nil in snapshots taken before metadata was included.
+	Metadata []types.Metadata `json:"metadata"`
+	// Cryptokeys carry their private keys only when taken with TakeOptions.PrivateKeys.
+	Cryptokeys []types.Cryptokey `json:"cryptokeys,omitempty"`
+	// SealedPrivateKeys holds the private keys of Cryptokeys once sealed with SealKeys.
+	SealedPrivateKeys []byte `json:"sealed_private_keys,omitempty"`
+}
+
+// TakeOptions Controls what TakeWithOptions includes besides record sets and metadata.
+type TakeOptions struct {
+	// PrivateKeys includes the cryptokeys of the zone with their private keys, so a
+	// restore reproduces its DNSSEC state. Stores refuse snapshots holding plaintext
+	// private keys, so Encrypter must be set for snapshots that are stored.
+	PrivateKeys bool
+	// Encrypter, when set, seals the private keys of the snapshot with SealKeys.
+	Encrypter Encrypter
+}
+
+// HasPrivateKeys Checks if the snapshot holds DNSSEC private keys.
+func (snapshot *Snapshot) HasPrivateKeys() bool {
+	for _, key := range snapshot.Cryptokeys {
+		if key.PrivateKey != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ID Returns the identifier of the snapshot within its zone.
+func (snapshot *Snapshot) ID() string {
+	return snapshot.TakenAt.UTC().Format(snapshotIDLayout)
+}
+
+// SnapshotStore Persists snapshots, keyed by zone and snapshot ID.
+type SnapshotStore interface {
+	Put(snapshot *Snapshot) error
+	Get(zone string, id string) (*Snapshot, error)
+	// List returns the snapshot IDs of zone, oldest first.
+	List(zone string) ([]string, error)
+}
+
+// Take Returns a snapshot of the current record sets and metadata of zone.
+func Take(c client.Provider, zone string) (*Snapshot, error) {
+	return TakeWithOptions(c, zone, TakeOptions{})
+}
+
+// TakeWithOptions Returns a snapshot of the current record sets and metadata of zone,
+// and of its cryptokeys when requested by opts.
+func TakeWithOptions(c client.Provider, zone string, opts TakeOptions) (*Snapshot, error) {
+	rrSets, err := c.ListRecordsAsRRSet(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := c.ListMetadata(zone)
+	if err != nil {
+		return nil, err
+	}
+	if metadata == nil {
+		metadata = []types.Metadata{}
+	}
+
+	snapshot := &Snapshot{
+		Zone:       zone,
+		TakenAt:    time.Now().UTC(),
+		RecordSets: rrSets,
+		Metadata:   metadata,
+	}
+
+	if opts.PrivateKeys {
+		if snapshot.Cryptokeys, err = takeCryptokeys(c, zone, true); err != nil {
+			return nil, err
+		}
+	}
+
+	if opts.Encrypter != nil {
+		if err = snapshot.SealKeys(opts.Encrypter); err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshot, nil
+}
+
+// Restore Replaces the record sets of the snapshot zone with the snapshot content in a
+// single PATCH, deleting record sets created after the snapshot was taken. The SOA is left
+// alone, so the serial keeps increasing through SOA-EDIT-API instead of going back to the
+// snapshot serial, which secondaries would ignore. The metadata of the snapshot replaces
+// that of the zone: SOA-EDIT-API and PRESIGNED are restored through the zone, other kinds
+// the API cannot set are left alone, and the metadata is checked before any record set
+// is written. When the snapshot holds private keys the cryptokeys of the zone are made
+// to match it. Sealed private keys must be opened with OpenKeys first.
+func Restore(c client.Provider, snapshot *Snapshot) error {
+	if len(snapshot.SealedPrivateKeys) > 0 {
+		return fmt.Errorf("Error restoring snapshot: %s of zone: %s, %w", snapshot.ID(), snapshot.Zone, ErrSealedKeys)
+	}
+
+	var metadata *metadataPlan
+	if snapshot.Metadata != nil {
+		var err error
+		if metadata, err = planMetadata(c, snapshot.Zone, snapshot.Metadata); err != nil {
+			return err
+		}
+	}
+
+	if err := restoreRecordSets(c, snapshot); err != nil {
+		return err
+	}
+
+	if metadata != nil {
+		if err := metadata.apply(c, snapshot.Zone); err != nil {
+			return err
+		}
+	}
+
+	if snapshot.HasPrivateKeys() {
+		return restoreCryptokeys(c, snapshot.Zone, snapshot.Cryptokeys)
+	}
+
+	return nil
+}
+
+func restoreRecordSets(c client.Provider, snapshot *Snapshot) error {
+	live, err := c.ListRecordsAsRRSet(snapshot.Zone)
+	if err != nil {
+		return err
+	}
+
+	wanted := make(map[string]bool, len(snapshot.RecordSets))
+	changes := make([]types.ResourceRecordSet, 0, len(snapshot.RecordSets))
+	for _, rrSet := range snapshot.RecordSets {
+		if rrSet.Type == types.TypeSOA || (rrSet.Empty() && len(rrSet.Comments) == 0) {
+			continue
+		}
+
+		wanted[rrSet.ID()] = true
+		rrSet.ChangeType = types.ChangeTypeReplace
+		changes = append(changes, rrSet)
+	}
+
+	for _, rrSet := range live {
+		if rrSet.Type != types.TypeSOA && !wanted[rrSet.ID()] {
+			changes = append(changes, types.ResourceRecordSet{
+				Name:       rrSet.Name,
+				Type:       rrSet.Type,
+				ChangeType: types.ChangeTypeDelete,
+			})
+		}
+	}
+
+	return c.PatchRecordSets(snapshot.Zone, changes)
+}
+
+// Scheduler Snapshots a set of zones into a store on a fixed interval.
+type Scheduler struct {
+	Client   client.Provider
+	Store    SnapshotStore
+	Zones    []string
+	Interval time.Duration
+	// Options control what each snapshot includes.
+	Options TakeOptions
+	// OnError, when set, is called for every zone that failed to be snapshotted.
+	OnError func(zone string, err error)
+}
+
+// Run Snapshots every zone immediately and then on each interval until ctx is done.
+func (scheduler *Scheduler) Run(ctx context.Context) error {
+	ticker := time.NewTicker(scheduler.Interval)
+	defer ticker.Stop()
+
+	for {
+		scheduler.snapshotAll()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (scheduler *Scheduler) snapshotAll() {
+	for _, zone := range scheduler.Zones {
+		snapshot, err := TakeWithOptions(scheduler.Client, zone, scheduler.Options)
+		if err == nil {
+			err = scheduler.Store.Put(snapshot)
+		}
+
+		if err != nil && scheduler.OnError != nil {
+			scheduler.OnError(zone, err)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package backup
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dmportella/powerdns/memory"
+	"github.com/dmportella/powerdns/types"
+)
+
+const zone = "example.com."
+
+func newZone(t *testing.T) *memory.Provider {
+	t.Helper()
+
+	provider := memory.New()
+	if _, err := provider.CreateZone(types.ZoneInfo{
+		Name:        zone,
+		Nameservers: []string{"ns1.example.com.", "ns2.example.com."},
+		SOAEditAPI:  "DEFAULT",
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return provider
+}
+
+func replace(t *testing.T, provider *memory.Provider, name string, tpe types.RecordType, contents ...string) {
+	t.Helper()
+
+	rrSet := types.ResourceRecordSet{Name: name, Type: tpe, TTL: 300}
+	for _, content := range contents {
+		rrSet.Records = append(rrSet.Records, types.Record{Content: content})
+	}
+	if _, err := provider.ReplaceRecordSet(zone, rrSet); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRestoreRecordSetsAndMetadata(t *testing.T) {
+	provider := newZone(t)
+	replace(t, provider, "www.example.com.", types.TypeA, "192.0.2.1", "192.0.2.2")
+	replace(t, provider, "mail.example.com.", types.TypeMX, "10 mx.example.com.")
+	if err := provider.SetMetadata(zone, "ALLOW-AXFR-FROM", []string{"AUTO-NS"}); err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := Take(provider, zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	replace(t, provider, "www.example.com.", types.TypeA, "198.51.100.1")
+	replace(t, provider, "new.example.com.", types.TypeTXT, `"added after the snapshot"`)
+	if err = provider.DeleteRecordSet(zone, "mail.example.com.", "MX"); err != nil {
+		t.Fatal(err)
+	}
+	if err = provider.SetMetadata(zone, "ALSO-NOTIFY", []string{"192.0.2.53"}); err != nil {
+		t.Fatal(err)
+	}
+	if err = provider.DeleteMetadata(zone, "ALLOW-AXFR-FROM"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = Restore(provider, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := provider.ListRecordsAsRRSet(zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(restored, snapshot.RecordSets) {
+		t.Errorf("record sets after restore = %+v, want %+v", restored, snapshot.RecordSets)
+	}
+
+	metadata, err := provider.ListMetadata(zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(metadata, snapshot.Metadata) {
+		t.Errorf("metadata after restore = %+v, want %+v", metadata, snapshot.Metadata)
+	}
+}
+
+func TestRestoreProtectedMetadata(t *testing.T) {
+	provider := newZone(t)
+
+	snapshot, err := Take(provider, zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot.Metadata = append(snapshot.Metadata, types.Metadata{Kind: "NSEC3PARAM", Metadata: []string{"1 0 0 -"}})
+
+	if err = provider.SetSOAEditAPI(zone, "INCREASE"); err != nil {
+		t.Fatal(err)
+	}
+	if err = provider.SetPresigned(zone, true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = Restore(provider, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	live, err := provider.GetZone(zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if live.SOAEditAPI != "DEFAULT" || live.Presigned {
+		t.Errorf("after restore SOA-EDIT-API = %q, presigned = %v; want DEFAULT, false", live.SOAEditAPI, live.Presigned)
+	}
+}
+
+func TestRestoreInvalidMetadataWritesNothing(t *testing.T) {
+	provider := newZone(t)
+	replace(t, provider, "www.example.com.", types.TypeA, "192.0.2.1")
+
+	snapshot, err := Take(provider, zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+	snapshot.Metadata = append(snapshot.Metadata, types.Metadata{Metadata: []string{"no kind"}})
+
+	replace(t, provider, "www.example.com.", types.TypeA, "198.51.100.1")
+
+	if err = Restore(provider, snapshot); err == nil {
+		t.Fatal("Restore() of metadata without kind succeeded")
+	}
+
+	live, err := provider.GetRecordSet(zone, "www.example.com.", "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if live.Records[0].Content != "198.51.100.1" {
+		t.Errorf("www A after failed restore = %+v, want it untouched", live.Records)
+	}
+}
+
+func TestRestoreLeavesSOAAlone(t *testing.T) {
+	provider := newZone(t)
+
+	snapshot, err := Take(provider, zone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	soa, err := provider.GetRecordSet(zone, zone, "SOA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	soa.Records[0].Content = "ns1.example.com. hostmaster.example.com. 2024010100 10800 3600 604800 3600"
+	if _, err = provider.ReplaceRecordSet(zone, *soa); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = Restore(provider, snapshot); err != nil {
+		t.Fatal(err)
+	}
+
+	live, err := provider.GetRecordSet(zone, zone, "SOA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if live == nil || live.Records[0].Content != soa.Records[0].Content {
+		t.Errorf("SOA after restore = %+v, want %+v", live, soa)
+	}
+}
+
+func TestTakePrivateKeysSealed(t *testing.T) {
+	provider := newZone(t)
+	if _, err := provider.CreateCryptokey(zone, types.Cryptokey{KeyType: "csk", Active: true, PrivateKey: "Private-key-format: v1.2"}); err != nil {
+		t.Fatal(err)
+	}
+
+	encrypter, err := NewAESGCM(make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	snapshot, err := TakeWithOptions(provider, zone, TakeOptions{PrivateKeys: true, Encrypter: encrypter})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.HasPrivateKeys() || len(snapshot.SealedPrivateKeys) == 0 {
+		t.Fatalf("private keys were not sealed: %+v", snapshot)
+	}
+	if err = checkSealed(snapshot); err != nil {
+		t.Errorf("checkSealed() of sealed snapshot: %s", err)
+	}
+	if err = Restore(provider, snapshot); !errors.Is(err, ErrSealedKeys) {
+		t.Errorf("Restore() of sealed snapshot = %v, want ErrSealedKeys", err)
+	}
+
+	if err = snapshot.OpenKeys(encrypter); err != nil {
+		t.Fatal(err)
+	}
+	if len(snapshot.Cryptokeys) != 1 || snapshot.Cryptokeys[0].PrivateKey != "Private-key-format: v1.2" {
+		t.Errorf("opened cryptokeys = %+v", snapshot.Cryptokeys)
+	}
+	if err = checkSealed(snapshot); !errors.Is(err, ErrPlaintextKeys) {
+		t.Errorf("checkSealed() of opened snapshot = %v, want ErrPlaintextKeys", err)
+	}
+}
@@ -0,0 +1,168 @@
+package backup
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// Returns the cryptokeys of zone, with their private keys when privateKeys is set
+func takeCryptokeys(c client.DNSSECAPI, zone string, privateKeys bool) ([]types.Cryptokey, error) {
+	keys, err := c.ListCryptokeys(zone)
+	if err != nil || !privateKeys {
+		return keys, err
+	}
+
+	for i, key := range keys {
+		full, err := c.GetCryptokey(zone, key.ID)
+		if err != nil {
+			return nil, err
+		}
+		keys[i].PrivateKey = full.PrivateKey
+	}
+
+	return keys, nil
+}
+
+// Metadata changes of a restore, worked out before anything is written
+type metadataPlan struct {
+	set    []types.Metadata
+	delete []string
+	// SOA-EDIT-API and PRESIGNED are protected and changed through the zone instead
+	soaEditAPI *string
+	presigned  *bool
+}
+
+// Works out the changes turning the live metadata of zone into metadata. Protected kinds
+// are skipped, except SOA-EDIT-API and PRESIGNED, which are planned as zone updates
+func planMetadata(c client.MetadataAPI, zone string, metadata []types.Metadata) (*metadataPlan, error) {
+	live, err := c.ListMetadata(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	liveValues := make(map[string][]string, len(live))
+	for _, entry := range live {
+		liveValues[strings.ToUpper(entry.Kind)] = entry.Metadata
+	}
+
+	plan := &metadataPlan{}
+	wanted := make(map[string][]string, len(metadata))
+	for _, entry := range metadata {
+		kind := strings.ToUpper(entry.Kind)
+		if kind == "" {
+			return nil, fmt.Errorf("Error restoring metadata of zone: %s, metadata without kind", zone)
+		}
+		if _, ok := wanted[kind]; ok {
+			return nil, fmt.Errorf("Error restoring metadata of zone: %s, kind: %s listed more than once", zone, entry.Kind)
+		}
+		wanted[kind] = entry.Metadata
+
+		if !client.IsProtectedMetadata(kind) {
+			plan.set = append(plan.set, entry)
+		}
+	}
+
+	for _, entry := range live {
+		kind := strings.ToUpper(entry.Kind)
+		if _, ok := wanted[kind]; !ok && !client.IsProtectedMetadata(kind) {
+			plan.delete = append(plan.delete, entry.Kind)
+		}
+	}
+
+	if soaEditAPI := firstValue(wanted[client.MetadataSOAEditAPI]); soaEditAPI != firstValue(liveValues[client.MetadataSOAEditAPI]) {
+		plan.soaEditAPI = &soaEditAPI
+	}
+	if presigned := firstValue(wanted[client.MetadataPresigned]) == "1"; presigned != (firstValue(liveValues[client.MetadataPresigned]) == "1") {
+		plan.presigned = &presigned
+	}
+
+	return plan, nil
+}
+
+func firstValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// Sets the planned metadata and deletes the other kinds
+func (plan *metadataPlan) apply(c client.Provider, zone string) error {
+	for _, entry := range plan.set {
+		if err := c.SetMetadata(zone, entry.Kind, entry.Metadata); err != nil {
+			return err
+		}
+	}
+
+	for _, kind := range plan.delete {
+		if err := c.DeleteMetadata(zone, kind); err != nil {
+			return err
+		}
+	}
+
+	if plan.soaEditAPI != nil {
+		if err := c.SetSOAEditAPI(zone, *plan.soaEditAPI); err != nil {
+			return err
+		}
+	}
+
+	if plan.presigned != nil {
+		return c.SetPresigned(zone, *plan.presigned)
+	}
+
+	return nil
+}
+
+// Imports the keys of the snapshot missing from zone, restores their active state and
+// then deletes keys the snapshot does not have, so the zone stays signed throughout.
+// Keys are matched by their DNSKEY record
+func restoreCryptokeys(c client.DNSSECAPI, zone string, keys []types.Cryptokey) error {
+	live, err := c.ListCryptokeys(zone)
+	if err != nil {
+		return err
+	}
+
+	liveByDNSKey := make(map[string]types.Cryptokey, len(live))
+	for _, key := range live {
+		liveByDNSKey[key.DNSKey] = key
+	}
+
+	wanted := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		wanted[key.DNSKey] = true
+
+		current, ok := liveByDNSKey[key.DNSKey]
+		if !ok {
+			_, err = c.CreateCryptokey(zone, types.Cryptokey{
+				KeyType:    key.KeyType,
+				Active:     key.Active,
+				Published:  key.Published,
+				PrivateKey: key.PrivateKey,
+			})
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if current.Active != key.Active {
+			if err = c.SetCryptokeyActive(zone, current.ID, key.Active); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, key := range live {
+		if !wanted[key.DNSKey] {
+			if err = c.DeleteCryptokey(zone, key.ID); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
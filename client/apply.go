@@ -0,0 +1,37 @@
+package client
+
+import (
+	"github.com/dmportella/powerdns/types"
+)
+
+// ZoneResult Outcome of the changes applied to one zone by ApplyChanges.
+type ZoneResult struct {
+	Zone       string
+	RecordSets int
+	Err        error
+}
+
+// ApplyChanges Groups changes by zone and applies each group with PatchRecordSets, one
+// PATCH per zone. A failing zone does not stop the others; the result of every zone is
+// returned in the order the zones first appear in changes.
+func (client *Client) ApplyChanges(changes []types.ZonedChange) []ZoneResult {
+	var zones []string
+	grouped := make(map[string][]types.ResourceRecordSet)
+	for _, change := range changes {
+		if _, ok := grouped[change.Zone]; !ok {
+			zones = append(zones, change.Zone)
+		}
+		grouped[change.Zone] = append(grouped[change.Zone], change.RecordSet)
+	}
+
+	results := make([]ZoneResult, 0, len(zones))
+	for _, zone := range zones {
+		results = append(results, ZoneResult{
+			Zone:       zone,
+			RecordSets: len(grouped[zone]),
+			Err:        client.PatchRecordSets(zone, grouped[zone]),
+		})
+	}
+
+	return results
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"net/http"
+)
+
+// APIKeyHeaderName Header PowerDNS reads the API key from.
+const APIKeyHeaderName = "X-API-Key"
+
+// AuthStrategy Adds the credentials of the client, its API key, to every request. The
+// default is APIKeyHeader; deployments behind an authenticating proxy that strips
+// X-API-Key can send the key as a bearer token, or in any form, instead.
+type AuthStrategy interface {
+	Authenticate(req *http.Request, apiKey string)
+}
+
+// APIKeyHeader Sends the API key in a header, X-API-Key unless Header is set.
+type APIKeyHeader struct {
+	Header string
+}
+
+// Authenticate Sets the API key header of req.
+func (strategy APIKeyHeader) Authenticate(req *http.Request, apiKey string) {
+	header := strategy.Header
+	if header == "" {
+		header = APIKeyHeaderName
+	}
+
+	req.Header.Set(header, apiKey)
+}
+
+// BearerToken Sends the API key as "Authorization: Bearer <key>".
+type BearerToken struct{}
+
+// Authenticate Sets the Authorization header of req.
+func (BearerToken) Authenticate(req *http.Request, apiKey string) {
+	req.Header.Set("Authorization", "Bearer "+apiKey)
+}
+
+// Custom Adds credentials with a function, e.g. to sign requests or to send both headers.
+type Custom func(req *http.Request, apiKey string)
+
+// Authenticate Calls the function with req.
+func (custom Custom) Authenticate(req *http.Request, apiKey string) {
+	custom(req, apiKey)
+}
+
+// WithAuth selects how the API key is sent, e.g. WithAuth(BearerToken{}).
+func WithAuth(strategy AuthStrategy) Option {
+	return func(client *Client) {
+		client.auth = strategy
+	}
+}
+
+// Adds the credentials to req with the strategy of the client
+func (client *Client) authenticate(req *http.Request) {
+	if client.auth == nil {
+		APIKeyHeader{}.Authenticate(req, client.apiKey)
+		return
+	}
+
+	client.auth.Authenticate(req, client.apiKey)
+}
@@ -0,0 +1,125 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen Returned, wrapped, for requests short-circuited by an open circuit breaker.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// maxUnavailableBackoff caps the exponential backoff between 503 retries.
+const maxUnavailableBackoff = 30 * time.Second
+
+// WithUnavailableRetry retries requests answered with 503 Service Unavailable at most
+// maxRetries times, waiting baseDelay, then twice as long on every further attempt. A
+// longer Retry-After sent by the server is honoured.
+func WithUnavailableRetry(maxRetries int, baseDelay time.Duration) Option {
+	return func(client *Client) {
+		client.unavailableRetries = maxRetries
+		client.unavailableDelay = baseDelay
+	}
+}
+
+// WithCircuitBreaker opens the circuit after threshold consecutive failed requests
+// (transport errors and 5xx responses). While open, requests fail immediately with
+// ErrCircuitOpen; after cooldown a single request is let through to probe the server
+// and closes the circuit again when it succeeds.
+func WithCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(client *Client) {
+		client.breaker = &circuitBreaker{
+			threshold: threshold,
+			cooldown:  cooldown,
+		}
+	}
+}
+
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// Returns ErrCircuitOpen when the request must not be sent
+func (breaker *circuitBreaker) allow() error {
+	if breaker == nil {
+		return nil
+	}
+
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	if breaker.failures < breaker.threshold {
+		return nil
+	}
+	if breaker.probing || time.Now().Before(breaker.openUntil) {
+		return ErrCircuitOpen
+	}
+
+	breaker.probing = true
+	return nil
+}
+
+// Records the outcome of a request that was allowed through
+func (breaker *circuitBreaker) record(success bool) {
+	if breaker == nil {
+		return
+	}
+
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	breaker.probing = false
+	if success {
+		breaker.failures = 0
+		return
+	}
+
+	breaker.failures++
+	if breaker.failures >= breaker.threshold {
+		breaker.openUntil = time.Now().Add(breaker.cooldown)
+	}
+}
+
+// Lets another probe through after a request that was allowed but never sent, without
+// counting it as a success or failure
+func (breaker *circuitBreaker) release() {
+	if breaker == nil {
+		return
+	}
+
+	breaker.mu.Lock()
+	defer breaker.mu.Unlock()
+
+	breaker.probing = false
+}
+
+// Returns how long to wait before retrying response, and whether to retry at all
+func (client *Client) retryDelay(response *Response, attempt int) (time.Duration, bool) {
+	switch response.StatusCode {
+	case http.StatusTooManyRequests:
+		return response.RetryAfter(), attempt < client.rateLimitRetries
+	case http.StatusServiceUnavailable:
+		if attempt >= client.unavailableRetries {
+			return 0, false
+		}
+
+		wait := client.unavailableDelay << uint(attempt)
+		if wait <= 0 || wait > maxUnavailableBackoff {
+			wait = maxUnavailableBackoff
+		}
+		if response.Header.Get("Retry-After") != "" {
+			if retryAfter := response.RetryAfter(); retryAfter > wait {
+				wait = retryAfter
+			}
+		}
+		return wait, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,103 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// Capabilities What the client can do with its API key, as found by ProbeCapabilities.
+type Capabilities struct {
+	// Read is true when the key can read server information.
+	Read bool
+	// Write is true when zones can be modified. It is only certain when WriteProbed is
+	// true, otherwise it is derived from the configuration.
+	Write       bool
+	WriteProbed bool
+	// ServerReadOnly is true when the server runs with api-readonly.
+	ServerReadOnly bool
+	// ClientReadOnly is true when the client was created WithReadOnly.
+	ClientReadOnly bool
+}
+
+// ConfigSetting Data representing a server configuration setting.
+type ConfigSetting struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// GetConfig Returns the configuration settings of the server.
+func (client *Client) GetConfig() ([]ConfigSetting, error) {
+	req, err := client.newRequest("GET", "/servers/localhost/config", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, newAPIError(resp, "reading server configuration")
+	}
+
+	var settings []ConfigSetting
+	if err = json.NewDecoder(resp.Body).Decode(&settings); err != nil {
+		return nil, err
+	}
+
+	return settings, nil
+}
+
+// ProbeCapabilities Checks whether the API key can read and modify zones, so tools can
+// fail fast instead of failing on the first PATCH. When zone is not empty, the write
+// check is an empty PATCH of zone, which modifies nothing; otherwise it relies on the
+// api-readonly setting of the server.
+func (client *Client) ProbeCapabilities(zone string) (*Capabilities, error) {
+	capabilities := &Capabilities{ClientReadOnly: client.readOnly}
+
+	if _, err := client.GetServer(); err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) && (apiErr.StatusCode == 401 || apiErr.StatusCode == 403) {
+			return capabilities, nil
+		}
+		return nil, err
+	}
+	capabilities.Read = true
+
+	// The config endpoint may be restricted; a failure only means the setting is unknown
+	if settings, err := client.GetConfig(); err == nil {
+		for _, setting := range settings {
+			if setting.Name == "api-readonly" && setting.Value == "yes" {
+				capabilities.ServerReadOnly = true
+			}
+		}
+	}
+
+	if capabilities.ClientReadOnly || capabilities.ServerReadOnly {
+		return capabilities, nil
+	}
+
+	if zone == "" {
+		capabilities.Write = true
+		return capabilities, nil
+	}
+
+	err := client.sendPatch(zone, []types.ResourceRecordSet{}, fmt.Sprintf("probing write access to zone: %s", zone))
+	capabilities.WriteProbed = true
+	if err == nil {
+		capabilities.Write = true
+		return capabilities, nil
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == 401 || apiErr.StatusCode == 403) {
+		return capabilities, nil
+	}
+
+	return nil, err
+}
@@ -0,0 +1,267 @@
+// Package client implements the HTTP client for the PowerDNS API.
+package client
+
+// Covered by original license.
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"time"
+
+	"github.com/dmportella/powerdns/types"
+	"github.com/hashicorp/go-cleanhttp"
+	"golang.org/x/sync/singleflight"
+)
+
+// Client Powerdns API client.
+type Client struct {
+	serverURL        string
+	apiKey           string
+	apiVersion       int
+	basePath         string
+	http             *http.Client
+	responseHooks    []ResponseHook
+	rateLimitRetries int
+	gzipRequests     bool
+	patchBatchSize   int
+	preserveOrder    bool
+	autoCreateZone   *types.ZoneInfo
+	version          *serverVersion
+	readOnly         bool
+	relativeNames    bool
+	zoneCheck        bool
+
+	unavailableRetries int
+	unavailableDelay   time.Duration
+	breaker            *circuitBreaker
+	reads              *singleflight.Group
+	doer               Doer
+	soaEditAPI         string
+	defaultTTLFromSOA  bool
+	etags              *etagCache
+	lint               types.LintLevel
+	rewriteRules       []RewriteRule
+	requestIDs         bool
+	ctx                context.Context
+	auth               AuthStrategy
+	slowCallThreshold  time.Duration
+	slowCallHooks      []SlowCallHook
+	dispatcher         *dispatcher
+	priority           Priority
+	changeGuard        *changeGuard
+	rawTargets         bool
+}
+
+// Option configures optional behaviour of the Client.
+type Option func(*Client)
+
+// NewClient returns a new PowerDNS client. It validates the server URL and API key and
+// detects the API version, returning an error instead of failing later on bad configuration.
+func NewClient(serverURL string, apiKey string, opts ...Option) (*Client, error) {
+	url, err := url.Parse(serverURL)
+
+	if err != nil {
+		return nil, fmt.Errorf("Invalid server URL: %w", err)
+	}
+
+	if url.Scheme != "http" && url.Scheme != "https" {
+		return nil, fmt.Errorf("Invalid server URL scheme: %q, expected http or https", url.Scheme)
+	}
+
+	if url.Host == "" {
+		return nil, fmt.Errorf("Invalid server URL: %q, missing host", serverURL)
+	}
+
+	if apiKey == "" {
+		return nil, fmt.Errorf("Missing API key")
+	}
+
+	url.Path = apiPrefix(url.Path)
+
+	client := Client{
+		serverURL: url.String(),
+		apiKey:    apiKey,
+		http:      cleanhttp.DefaultPooledClient(),
+		version:   new(serverVersion),
+	}
+	for _, opt := range opts {
+		opt(&client)
+	}
+	client.apiVersion, err = client.detectapiVersion()
+	if err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
+
+// Detects the API version in use on the server
+// Uses int to represent the API version: 0 is the legacy AKA version 3.4 API
+// Any other integer correlates with the same API version
+// The versions advertised by the discovery endpoint are preferred, falling back to probing
+func (client *Client) detectapiVersion() (int, error) {
+	prefix := client.pathPrefix()
+
+	if versions, err := client.Discover(); err == nil && len(versions) > 0 {
+		latest := versions[0]
+		for _, version := range versions {
+			if version.Version > latest.Version {
+				latest = version
+			}
+		}
+
+		client.basePath = path.Join(prefix, latest.URL)
+		return latest.Version, nil
+	}
+
+	req, err := client.newRequest("GET", "/api/v1/servers", nil)
+
+	if err != nil {
+		return -1, err
+	}
+
+	resp, err := client.do(req)
+
+	if err != nil {
+		return -1, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode == 200 {
+		client.basePath = path.Join(prefix, "/api/v1")
+		return 1, nil
+	}
+
+	return 0, nil
+}
+
+// Creates a new request with necessary headers
+func (client *Client) newRequest(method string, endpoint string, body []byte) (*http.Request, error) {
+	if client.readOnly && method != "GET" && method != "HEAD" {
+		return nil, fmt.Errorf("Error during %s %s: %w", method, endpoint, ErrReadOnly)
+	}
+
+	url, err := url.Parse(client.serverURL)
+
+	if client.basePath != "" {
+		url.Path = path.Join(client.basePath, endpoint)
+	} else {
+		url.Path = path.Join(url.Path, endpoint)
+	}
+
+	compressed := false
+	if body != nil && client.gzipRequests {
+		if body, err = gzipBody(body); err != nil {
+			return nil, fmt.Errorf("Error during compression of request: %w", err)
+		}
+		compressed = true
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		bodyReader = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequest(method, url.String(), bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("Error during creation of request: %w", err)
+	}
+
+	if client.ctx != nil {
+		req = req.WithContext(client.ctx)
+	}
+
+	client.authenticate(req)
+	req.Header.Add("Accept", "application/json")
+
+	if id := client.requestID(); id != "" {
+		req.Header.Set(RequestIDHeader, id)
+	}
+
+	if method != "GET" {
+		req.Header.Add("Content-Type", "application/json")
+	}
+
+	if compressed {
+		req.Header.Add("Content-Encoding", "gzip")
+	}
+
+	return req, nil
+}
+
+// Sends the request, notifying response hooks and retrying on 429 and 503 when enabled
+func (client *Client) do(req *http.Request) (*http.Response, error) {
+	operation := fmt.Sprintf("Error during %s request", req.Method)
+	if id := req.Header.Get(RequestIDHeader); id != "" {
+		operation = fmt.Sprintf("%s, request id: %s", operation, id)
+	}
+
+	if err := client.breaker.allow(); err != nil {
+		return nil, client.wrapError(operation, err)
+	}
+
+	var doer Doer = client.http
+	if client.doer != nil {
+		doer = client.doer
+	}
+
+	client.etags.prepare(req)
+
+	for attempt := 0; ; attempt++ {
+		if err := client.dispatcher.acquire(req.Context(), client.priority); err != nil {
+			client.breaker.release()
+			return nil, client.wrapError(operation, err)
+		}
+
+		start := time.Now()
+		resp, err := doer.Do(req)
+		client.dispatcher.release()
+		if err != nil {
+			client.breaker.record(false)
+			return nil, client.wrapError(operation, err)
+		}
+		client.checkSlowCall(req, resp.StatusCode, time.Since(start))
+
+		response := newResponse(resp)
+		response.RequestID = req.Header.Get(RequestIDHeader)
+		for _, hook := range client.responseHooks {
+			hook(response)
+		}
+
+		wait, retry := client.retryDelay(response, attempt)
+		if !retry {
+			client.breaker.record(resp.StatusCode < 500)
+			return client.etags.handle(req, resp)
+		}
+
+		resp.Body.Close()
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			client.breaker.record(resp.StatusCode < 500)
+			return nil, client.wrapError(operation, req.Context().Err())
+		}
+
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				client.breaker.record(false)
+				return nil, err
+			}
+		}
+	}
+}
+
+type zonePatchRequest struct {
+	RecordSets []types.ResourceRecordSet `json:"rrsets"`
+}
+
+type errorResponse struct {
+	ErrorMsg string `json:"error"`
+}
@@ -0,0 +1,137 @@
+package client
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// Minimal PowerDNS API serving zones from memory and recording PATCH bodies
+type testServer struct {
+	mu      sync.Mutex
+	zones   map[string]*types.ZoneInfo
+	patches []string
+	puts    []string
+	paths   []string
+}
+
+func newTestServer(t testing.TB, zones ...types.ZoneInfo) (*Client, *testServer) {
+	t.Helper()
+
+	server := &testServer{zones: make(map[string]*types.ZoneInfo)}
+	for i := range zones {
+		server.zones[zones[i].Name] = &zones[i]
+	}
+
+	httpServer := httptest.NewServer(server)
+	t.Cleanup(httpServer.Close)
+
+	client, err := NewClient(httpServer.URL, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return client, server
+}
+
+func (server *testServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	server.paths = append(server.paths, req.Method+" "+req.URL.EscapedPath())
+
+	if req.URL.Path == "/api" {
+		json.NewEncoder(w).Encode([]types.APIVersion{{URL: "/api/v1", Version: 1}})
+		return
+	}
+
+	const zonesPath = "/api/v1/servers/localhost/zones/"
+	if !strings.HasPrefix(req.URL.Path, zonesPath) {
+		http.NotFound(w, req)
+		return
+	}
+
+	zone, ok := server.zones[strings.TrimPrefix(req.URL.Path, zonesPath)]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		json.NewEncoder(w).Encode(errorResponse{ErrorMsg: "Could not find domain"})
+		return
+	}
+
+	switch req.Method {
+	case "GET":
+		json.NewEncoder(w).Encode(zone)
+	case "PATCH":
+		body, _ := io.ReadAll(req.Body)
+		server.patches = append(server.patches, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	case "PUT":
+		body, _ := io.ReadAll(req.Body)
+		server.puts = append(server.puts, string(body))
+		w.WriteHeader(http.StatusNoContent)
+	case "DELETE":
+		delete(server.zones, zone.Name)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (server *testServer) lastPatch(t testing.TB) string {
+	t.Helper()
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if len(server.patches) == 0 {
+		t.Fatal("no PATCH request was sent")
+	}
+
+	return server.patches[len(server.patches)-1]
+}
+
+func (server *testServer) lastPut(t testing.TB) string {
+	t.Helper()
+
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	if len(server.puts) == 0 {
+		t.Fatal("no PUT request was sent")
+	}
+
+	return server.puts[len(server.puts)-1]
+}
+
+// Returns want as compact JSON, so expected bodies can be written readably
+func compactJSON(t testing.TB, want string) string {
+	t.Helper()
+
+	var value interface{}
+	if err := json.Unmarshal([]byte(want), &value); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+func TestNewClientDiscoversBasePath(t *testing.T) {
+	client, server := newTestServer(t)
+
+	if got := client.BasePath(); got != "/api/v1" {
+		t.Errorf("BasePath() = %q, want /api/v1", got)
+	}
+	if got := server.paths[0]; got != "GET /api" {
+		t.Errorf("first request = %q, want GET /api", got)
+	}
+}
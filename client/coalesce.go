@@ -0,0 +1,14 @@
+package client
+
+import "golang.org/x/sync/singleflight"
+
+// WithReadCoalescing collapses concurrent identical zone reads, including the record
+// set lookups built on them, into a single API call whose result is shared by all
+// callers. This relieves the PowerDNS webserver when many workers poll the same zones.
+// A read joining a call that is already in flight may not observe a write made while
+// that call was running.
+func WithReadCoalescing() Option {
+	return func(client *Client) {
+		client.reads = new(singleflight.Group)
+	}
+}
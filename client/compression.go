@@ -0,0 +1,56 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// WithGzipRequests compresses request bodies with gzip and sets Content-Encoding,
+// which pays off for PATCH calls carrying thousands of rrsets. The server, or a proxy
+// in front of it, must accept gzip encoded requests.
+func WithGzipRequests() Option {
+	return func(client *Client) {
+		client.gzipRequests = true
+	}
+}
+
+// WithPatchBatchSize splits PatchRecordSets calls into PATCH requests of at most size
+// rrsets each. A size of zero, the default, sends all changes in one request.
+func WithPatchBatchSize(size int) Option {
+	return func(client *Client) {
+		client.patchBatchSize = size
+	}
+}
+
+func gzipBody(body []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func splitBatches(rrSets []types.ResourceRecordSet, size int) [][]types.ResourceRecordSet {
+	if size <= 0 || len(rrSets) <= size {
+		return [][]types.ResourceRecordSet{rrSets}
+	}
+
+	var batches [][]types.ResourceRecordSet
+	for start := 0; start < len(rrSets); start += size {
+		end := start + size
+		if end > len(rrSets) {
+			end = len(rrSets)
+		}
+		batches = append(batches, rrSets[start:end])
+	}
+
+	return batches
+}
@@ -0,0 +1,132 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// ListCryptokeys Returns all DNSSEC keys of Zone, including their DNSKEY and DS records
+func (client *Client) ListCryptokeys(zone string) ([]types.Cryptokey, error) {
+	req, err := client.newRequest("GET", fmt.Sprintf("/servers/localhost/zones/%s/cryptokeys", client.rewriteName(zone, true)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, newAPIError(resp, fmt.Sprintf("listing cryptokeys of zone: %s", zone))
+	}
+
+	var keys []types.Cryptokey
+	err = json.NewDecoder(resp.Body).Decode(&keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+// GetCryptokey Returns a DNSSEC key of Zone including its private key
+func (client *Client) GetCryptokey(zone string, id int) (*types.Cryptokey, error) {
+	req, err := client.newRequest("GET", fmt.Sprintf("/servers/localhost/zones/%s/cryptokeys/%d", client.rewriteName(zone, true), id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, newAPIError(resp, fmt.Sprintf("reading cryptokey: %d of zone: %s", id, zone))
+	}
+
+	key := new(types.Cryptokey)
+	err = json.NewDecoder(resp.Body).Decode(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
+// CreateCryptokey Creates (or imports, when PrivateKey is set) a DNSSEC key in Zone
+func (client *Client) CreateCryptokey(zone string, key types.Cryptokey) (*types.Cryptokey, error) {
+	reqBody, _ := json.Marshal(key)
+
+	req, err := client.newRequest("POST", fmt.Sprintf("/servers/localhost/zones/%s/cryptokeys", client.rewriteName(zone, true)), reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 201 && resp.StatusCode != 200 {
+		return nil, newAPIError(resp, fmt.Sprintf("creating %s cryptokey in zone: %s", key.KeyType, zone))
+	}
+
+	created := new(types.Cryptokey)
+	err = json.NewDecoder(resp.Body).Decode(created)
+	if err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+// SetCryptokeyActive Activates or deactivates a DNSSEC key of Zone
+func (client *Client) SetCryptokeyActive(zone string, id int, active bool) error {
+	reqBody, _ := json.Marshal(struct {
+		Active bool `json:"active"`
+	}{active})
+
+	req, err := client.newRequest("PUT", fmt.Sprintf("/servers/localhost/zones/%s/cryptokeys/%d", client.rewriteName(zone, true), id), reqBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 204 {
+		return newAPIError(resp, fmt.Sprintf("updating cryptokey: %d of zone: %s", id, zone))
+	}
+
+	return nil
+}
+
+// DeleteCryptokey Deletes a DNSSEC key from Zone
+func (client *Client) DeleteCryptokey(zone string, id int) error {
+	req, err := client.newRequest("DELETE", fmt.Sprintf("/servers/localhost/zones/%s/cryptokeys/%d", client.rewriteName(zone, true), id), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 204 {
+		return newAPIError(resp, fmt.Sprintf("deleting cryptokey: %d of zone: %s", id, zone))
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// FallbackDelay Time given to a connection attempt before the next address is dialed in
+// parallel, as in RFC 8305 (happy eyeballs).
+var FallbackDelay = 300 * time.Millisecond
+
+// WithEndpointAddresses makes the client connect to the given IPv4 and IPv6 address
+// literals of the server instead of resolving its host name, e.g. when the API host is
+// missing from DNS or one address family is broken. Addresses are tried alternating
+// between families, starting with the family of the first one, each FallbackDelay after
+// the previous one or as soon as it fails; the first connection established is used.
+// TLS still verifies the certificate against the host name of the server URL. It changes
+// a copy of the transport and HTTP client, leaving one passed to WithHTTPClient untouched,
+// and has no effect when the transport is not an *http.Transport or with WithDoer.
+func WithEndpointAddresses(addresses ...string) Option {
+	return func(client *Client) {
+		ips := make([]net.IP, 0, len(addresses))
+		var invalid []string
+		for _, address := range addresses {
+			if ip := net.ParseIP(address); ip != nil {
+				ips = append(ips, ip)
+			} else {
+				invalid = append(invalid, address)
+			}
+		}
+
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		setDialContext(client, func(ctx context.Context, network string, address string) (net.Conn, error) {
+			if len(invalid) > 0 || len(ips) == 0 {
+				return nil, fmt.Errorf("Error connecting to %s: invalid endpoint addresses: %q", address, invalid)
+			}
+
+			_, port, err := net.SplitHostPort(address)
+			if err != nil {
+				return nil, err
+			}
+
+			return raceDial(ctx, dialer, network, interleaveFamilies(ips), port)
+		})
+	}
+}
+
+// WithResolver makes the client resolve the server host name with resolver, e.g. one
+// querying a specific DNS server. Connections to hosts with IPv4 and IPv6 addresses fall
+// back from one family to the other after FallbackDelay. Like WithEndpointAddresses, it
+// changes a copy of an *http.Transport and of the HTTP client.
+func WithResolver(resolver *net.Resolver) Option {
+	return func(client *Client) {
+		dialer := &net.Dialer{
+			Timeout:       30 * time.Second,
+			KeepAlive:     30 * time.Second,
+			Resolver:      resolver,
+			FallbackDelay: FallbackDelay,
+		}
+		setDialContext(client, dialer.DialContext)
+	}
+}
+
+// Replaces the dial function of the transport of the client, when it is an *http.Transport
+func setDialContext(client *Client, dial func(ctx context.Context, network string, address string) (net.Conn, error)) {
+	updateTransport(client, func(transport *http.Transport) {
+		transport.DialContext = dial
+	})
+}
+
+// Orders ips alternating between families, keeping the relative order within a family
+func interleaveFamilies(ips []net.IP) []net.IP {
+	var first, second []net.IP
+	firstIsV4 := ips[0].To4() != nil
+	for _, ip := range ips {
+		if (ip.To4() != nil) == firstIsV4 {
+			first = append(first, ip)
+		} else {
+			second = append(second, ip)
+		}
+	}
+
+	ordered := make([]net.IP, 0, len(ips))
+	for len(first) > 0 || len(second) > 0 {
+		if len(first) > 0 {
+			ordered = append(ordered, first[0])
+			first = first[1:]
+		}
+		if len(second) > 0 {
+			ordered = append(ordered, second[0])
+			second = second[1:]
+		}
+	}
+
+	return ordered
+}
+
+// Dials ips one after the other, starting the next attempt after FallbackDelay or when
+// the previous one fails, and returns the first connection established
+func raceDial(ctx context.Context, dialer *net.Dialer, network string, ips []net.IP, port string) (net.Conn, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	results := make(chan result, len(ips))
+
+	next, pending := 0, 0
+	start := func() {
+		address := net.JoinHostPort(ips[next].String(), port)
+		next++
+		pending++
+		go func() {
+			conn, err := dialer.DialContext(ctx, network, address)
+			results <- result{conn, err}
+		}()
+	}
+
+	timer := time.NewTimer(FallbackDelay)
+	defer timer.Stop()
+
+	start()
+	var firstErr error
+	for pending > 0 {
+		select {
+		case r := <-results:
+			pending--
+			if r.err == nil {
+				// close connections of attempts completing after the winner
+				go func(pending int) {
+					for ; pending > 0; pending-- {
+						if late := <-results; late.conn != nil {
+							late.conn.Close()
+						}
+					}
+				}(pending)
+				return r.conn, nil
+			}
+			if firstErr == nil {
+				firstErr = r.err
+			}
+			if next < len(ips) {
+				start()
+				timer.Reset(FallbackDelay)
+			}
+		case <-timer.C:
+			if next < len(ips) {
+				start()
+				timer.Reset(FallbackDelay)
+			}
+		}
+	}
+
+	return nil, firstErr
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"encoding/json"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// Discover Returns the API versions advertised by the server on the /api endpoint.
+// The client already uses it to select its base path when it is created.
+func (client *Client) Discover() ([]types.APIVersion, error) {
+	req, err := client.newRequest("GET", "/api", nil)
+	if err != nil {
+		return nil, err
+	}
+	// The versions live under the server prefix, not under the selected base path
+	req.URL.Path = path.Join(client.pathPrefix(), "/api")
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, newAPIError(resp, "discovering API versions")
+	}
+
+	var versions []types.APIVersion
+	err = json.NewDecoder(resp.Body).Decode(&versions)
+	if err != nil {
+		return nil, err
+	}
+
+	return versions, nil
+}
+
+// ServerURL Returns the URL of the server the client talks to, without API path.
+func (client *Client) ServerURL() string {
+	return client.serverURL
+}
+
+// BasePath Returns the path prefix all API endpoints are resolved against.
+func (client *Client) BasePath() string {
+	return client.basePath
+}
+
+// Returns the part of the server URL path in front of the API, e.g. a proxy prefix
+func (client *Client) pathPrefix() string {
+	serverURL, err := url.Parse(client.serverURL)
+	if err != nil {
+		return ""
+	}
+
+	return serverURL.Path
+}
+
+// Strips any API path from a configured server URL path, keeping proxy prefixes:
+// "/pdns/api/v1" becomes "/pdns", "/api/v1" becomes ""
+func apiPrefix(urlPath string) string {
+	if i := strings.Index(urlPath+"/", "/api/"); i >= 0 {
+		urlPath = urlPath[:i]
+	}
+
+	return strings.TrimRight(urlPath, "/")
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+)
+
+// Patterns of PowerDNS error messages naming the rrset a PATCH was rejected for
+var rrSetErrorPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`RRset (\S+) IN ([A-Za-z0-9]+)`),
+	regexp.MustCompile(`Record (\S+)/([A-Za-z0-9]+) '`),
+	regexp.MustCompile(`Name '(\S+)' is not in zone`),
+}
+
+// APIError Error reported by the PowerDNS API for an unsuccessful call. Use errors.As
+// to inspect it from errors returned by the client.
+// For rejected PATCH calls RRSetName and RRSetType identify the offending rrset when
+// PowerDNS names it in its message.
+type APIError struct {
+	StatusCode int
+	Message    string
+	Operation  string
+	RRSetName  string
+	RRSetType  string
+	// RequestID is the X-Request-ID of the failed request, if any.
+	RequestID string
+}
+
+func (err *APIError) Error() string {
+	operation := err.Operation
+	if err.RRSetName != "" {
+		operation = fmt.Sprintf("%s, record set: %s %s", operation, err.RRSetName, err.RRSetType)
+	}
+
+	if err.RequestID != "" {
+		operation = fmt.Sprintf("%s, request id: %s", operation, err.RequestID)
+	}
+
+	if err.Message == "" {
+		return fmt.Sprintf("Error %s, status: %d", operation, err.StatusCode)
+	}
+
+	return fmt.Sprintf("Error %s, reason: %q", operation, err.Message)
+}
+
+// Sets RRSetName and RRSetType from the error message when it names an rrset
+func (err *APIError) annotateRRSet() {
+	for _, pattern := range rrSetErrorPatterns {
+		match := pattern.FindStringSubmatch(err.Message)
+		if match == nil {
+			continue
+		}
+
+		err.RRSetName = match[1]
+		if len(match) > 2 {
+			err.RRSetType = match[2]
+		}
+		return
+	}
+}
+
+// Builds an APIError from an unsuccessful response, decoding the error message if any
+func newAPIError(resp *http.Response, operation string) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Operation:  operation,
+		RequestID:  resp.Header.Get(RequestIDHeader),
+	}
+	if resp.Request != nil && resp.Request.Header.Get(RequestIDHeader) != "" {
+		apiErr.RequestID = resp.Request.Header.Get(RequestIDHeader)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return apiErr
+	}
+
+	errorResp := new(errorResponse)
+	if err = json.Unmarshal(body, errorResp); err == nil {
+		apiErr.Message = errorResp.ErrorMsg
+	}
+
+	return apiErr
+}
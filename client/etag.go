@@ -0,0 +1,95 @@
+package client
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"sync"
+)
+
+// maxETagEntries bounds the number of responses kept by the ETag cache.
+const maxETagEntries = 256
+
+// WithETagCache makes the client remember the ETag of GET responses and revalidate them
+// with If-None-Match, serving the cached body when the server answers 304 Not Modified.
+// This cuts bandwidth when zones are polled often. Servers and proxies that send no
+// ETag are unaffected.
+func WithETagCache() Option {
+	return func(client *Client) {
+		client.etags = &etagCache{entries: make(map[string]*etagEntry)}
+	}
+}
+
+type etagEntry struct {
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+type etagCache struct {
+	sync.Mutex
+	entries map[string]*etagEntry
+}
+
+// Adds If-None-Match to GET requests whose response is cached
+func (cache *etagCache) prepare(req *http.Request) {
+	if cache == nil || req.Method != "GET" {
+		return
+	}
+
+	cache.Lock()
+	defer cache.Unlock()
+
+	if entry, ok := cache.entries[req.URL.String()]; ok {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+}
+
+// Stores responses carrying an ETag and turns 304 responses into the cached response
+func (cache *etagCache) handle(req *http.Request, resp *http.Response) (*http.Response, error) {
+	if cache == nil || req.Method != "GET" {
+		return resp, nil
+	}
+
+	key := req.URL.String()
+
+	if resp.StatusCode == http.StatusNotModified {
+		cache.Lock()
+		entry, ok := cache.entries[key]
+		cache.Unlock()
+		if !ok {
+			return resp, nil
+		}
+
+		resp.Body.Close()
+		resp.StatusCode = http.StatusOK
+		resp.Status = "200 OK"
+		resp.Header = entry.header.Clone()
+		resp.Body = io.NopCloser(bytes.NewReader(entry.body))
+		return resp, nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	cache.Lock()
+	defer cache.Unlock()
+	if _, ok := cache.entries[key]; !ok && len(cache.entries) >= maxETagEntries {
+		for evicted := range cache.entries {
+			delete(cache.entries, evicted)
+			break
+		}
+	}
+	cache.entries[key] = &etagEntry{etag: etag, header: resp.Header.Clone(), body: body}
+
+	return resp, nil
+}
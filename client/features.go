@@ -0,0 +1,114 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// ErrUnsupportedFeature is returned, wrapped in an UnsupportedFeatureError, when the
+// server is too old for the requested feature.
+var ErrUnsupportedFeature = errors.New("Unsupported feature")
+
+// Feature API feature only available from a given server version.
+type Feature struct {
+	Name       string
+	MinVersion types.Version
+}
+
+// Features gated by server version.
+var (
+	FeaturePrimarySecondaryKinds = Feature{"primary/secondary zone kinds", types.Version{Major: 4, Minor: 5}}
+	FeatureCatalogZones          = Feature{"catalog zones", types.Version{Major: 4, Minor: 7}}
+	FeatureRRSetFilter           = Feature{"rrset filters", types.Version{Major: 4, Minor: 8}}
+	FeatureCryptokeyPublished    = Feature{"cryptokey published flag", types.Version{Major: 4, Minor: 3}}
+	FeatureZoneVariants          = Feature{"zone variants", types.Version{Major: 5}}
+)
+
+// UnsupportedFeatureError Reports a feature the server version does not support.
+type UnsupportedFeatureError struct {
+	Feature Feature
+	Version types.Version
+}
+
+func (err *UnsupportedFeatureError) Error() string {
+	return fmt.Sprintf("Unsupported feature: %s requires PowerDNS %s or newer, server runs %s",
+		err.Feature.Name, err.Feature.MinVersion, err.Version)
+}
+
+// Is makes errors.Is(err, ErrUnsupportedFeature) match.
+func (err *UnsupportedFeatureError) Is(target error) bool {
+	return target == ErrUnsupportedFeature
+}
+
+type serverVersion struct {
+	sync.Mutex
+	version *types.Version
+}
+
+// GetServer Returns information about the server, including its version
+func (client *Client) GetServer() (*types.ServerInfo, error) {
+	req, err := client.newRequest("GET", "/servers/localhost", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, newAPIError(resp, "reading server information")
+	}
+
+	server := new(types.ServerInfo)
+	err = json.NewDecoder(resp.Body).Decode(server)
+	if err != nil {
+		return nil, err
+	}
+
+	return server, nil
+}
+
+// ServerVersion Returns the parsed server version, fetched once and then cached
+func (client *Client) ServerVersion() (types.Version, error) {
+	client.version.Lock()
+	defer client.version.Unlock()
+
+	if client.version.version != nil {
+		return *client.version.version, nil
+	}
+
+	server, err := client.GetServer()
+	if err != nil {
+		return types.Version{}, err
+	}
+
+	version, err := types.ParseVersion(server.Version)
+	if err != nil {
+		return types.Version{}, err
+	}
+
+	client.version.version = &version
+	return version, nil
+}
+
+// Supports Returns nil when the server supports feature, or an UnsupportedFeatureError
+// naming the minimum version required
+func (client *Client) Supports(feature Feature) error {
+	version, err := client.ServerVersion()
+	if err != nil {
+		return err
+	}
+
+	if !version.AtLeast(feature.MinVersion) {
+		return &UnsupportedFeatureError{Feature: feature, Version: version}
+	}
+
+	return nil
+}
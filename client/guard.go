@@ -0,0 +1,83 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// ErrChangeGuard is returned when a write would change a larger share of the rrsets of a
+// zone than allowed by WithChangeGuard.
+var ErrChangeGuard = errors.New("change exceeds the rate of change allowed for the zone")
+
+// WithChangeGuard rejects writes that would replace or delete more than maxPercent of
+// the existing rrsets of a zone in one call, protecting production zones from callers
+// that would wipe them by mistake. Calls changing fewer than minChanges existing rrsets
+// are always allowed, so small zones stay writable. Intended mass changes are sent
+// through a copy of the client returned by WithForce. The guard reads the zone before
+// every write.
+func WithChangeGuard(maxPercent float64, minChanges int) Option {
+	return func(client *Client) {
+		client.changeGuard = &changeGuard{maxPercent: maxPercent, minChanges: minChanges}
+	}
+}
+
+// WithForce Returns a copy of the client whose writes bypass the change guard. The copy
+// shares the configuration and state of the client.
+func (client *Client) WithForce() *Client {
+	forced := *client
+	forced.changeGuard = nil
+	return &forced
+}
+
+type changeGuard struct {
+	maxPercent float64
+	minChanges int
+}
+
+// Checks rrSets against the change guard, counting the existing rrsets they replace or delete
+func (client *Client) guardChanges(zone string, rrSets []types.ResourceRecordSet) error {
+	if client.changeGuard == nil {
+		return nil
+	}
+
+	rrSets, err := client.qualifyRecordSets(zone, rrSets)
+	if err != nil {
+		return err
+	}
+
+	live, err := client.ListRecordsAsRRSet(zone)
+	if err != nil {
+		if isZoneNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	existing := make(map[string]bool, len(live))
+	for _, rrSet := range live {
+		existing[strings.ToLower(rrSet.Name)+" "+string(rrSet.Type)] = true
+	}
+
+	changed := make(map[string]bool, len(rrSets))
+	for _, rrSet := range rrSets {
+		key := strings.ToLower(rrSet.Name) + " " + string(rrSet.Type)
+		if existing[key] {
+			changed[key] = true
+		}
+	}
+
+	if len(changed) < client.changeGuard.minChanges || len(changed) == 0 {
+		return nil
+	}
+
+	percent := float64(len(changed)) * 100 / float64(len(live))
+	if percent > client.changeGuard.maxPercent {
+		return fmt.Errorf("Error patching record sets in zone: %s, %d of %d record sets (%.1f%%) changed, limit: %.1f%%: %w",
+			zone, len(changed), len(live), percent, client.changeGuard.maxPercent, ErrChangeGuard)
+	}
+
+	return nil
+}
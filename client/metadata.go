@@ -0,0 +1,133 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// MetadataPresigned metadata kind marking a zone as signed by an external signer. The
+// API refuses to write it; use SetPresigned.
+const MetadataPresigned = "PRESIGNED"
+
+// MetadataSOAEditAPI metadata kind holding the SOA-EDIT-API strategy of a zone. The API
+// refuses to write it; use SetSOAEditAPI.
+const MetadataSOAEditAPI = "SOA-EDIT-API"
+
+// Metadata kinds the API refuses to set or delete
+var protectedMetadata = map[string]bool{
+	"API-RECTIFY":      true,
+	"AXFR-MASTER-TSIG": true,
+	"LUA-AXFR-SCRIPT":  true,
+	"NSEC3NARROW":      true,
+	"NSEC3PARAM":       true,
+	MetadataPresigned:  true,
+	MetadataSOAEditAPI: true,
+	"TSIG-ALLOW-AXFR":  true,
+}
+
+// IsProtectedMetadata Returns true for the metadata kinds SetMetadata and DeleteMetadata
+// fail on with a 422. Some of them are changed through the zone instead, e.g. with
+// SetPresigned and SetSOAEditAPI.
+func IsProtectedMetadata(kind string) bool {
+	return protectedMetadata[strings.ToUpper(kind)]
+}
+
+// ListMetadata Returns all metadata of Zone
+func (client *Client) ListMetadata(zone string) ([]types.Metadata, error) {
+	req, err := client.newRequest("GET", fmt.Sprintf("/servers/localhost/zones/%s/metadata", client.rewriteName(zone, true)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, newAPIError(resp, fmt.Sprintf("listing metadata of zone: %s", zone))
+	}
+
+	var metadata []types.Metadata
+	err = json.NewDecoder(resp.Body).Decode(&metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata, nil
+}
+
+// GetMetadata Returns the values of a metadata kind of Zone
+func (client *Client) GetMetadata(zone string, kind string) ([]string, error) {
+	req, err := client.newRequest("GET", fmt.Sprintf("/servers/localhost/zones/%s/metadata/%s", client.rewriteName(zone, true), kind), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, newAPIError(resp, fmt.Sprintf("reading metadata: %s of zone: %s", kind, zone))
+	}
+
+	metadata := new(types.Metadata)
+	err = json.NewDecoder(resp.Body).Decode(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	return metadata.Metadata, nil
+}
+
+// SetMetadata Replaces the values of a metadata kind of Zone
+func (client *Client) SetMetadata(zone string, kind string, values []string) error {
+	reqBody, _ := json.Marshal(types.Metadata{
+		Kind:     kind,
+		Metadata: values,
+	})
+
+	req, err := client.newRequest("PUT", fmt.Sprintf("/servers/localhost/zones/%s/metadata/%s", client.rewriteName(zone, true), kind), reqBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 204 {
+		return newAPIError(resp, fmt.Sprintf("setting metadata: %s of zone: %s", kind, zone))
+	}
+
+	return nil
+}
+
+// DeleteMetadata Deletes a metadata kind from Zone
+func (client *Client) DeleteMetadata(zone string, kind string) error {
+	req, err := client.newRequest("DELETE", fmt.Sprintf("/servers/localhost/zones/%s/metadata/%s", client.rewriteName(zone, true), kind), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 204 {
+		return newAPIError(resp, fmt.Sprintf("deleting metadata: %s of zone: %s", kind, zone))
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// ErrOutOfZone is returned when a record name does not belong to the zone it is written to.
+var ErrOutOfZone = errors.New("Record name is outside of the zone")
+
+// WithRelativeNames lets record names be given relative to the zone: "www" becomes
+// "www.<zone>" and "@" or an empty name the zone apex. Fully qualified names (ending
+// with a dot) are used as is, but must be inside the zone or ErrOutOfZone is returned.
+func WithRelativeNames() Option {
+	return func(client *Client) {
+		client.relativeNames = true
+	}
+}
+
+// WithZoneCheck verifies before every write that each record name is inside the zone
+// it is written to, returning ErrOutOfZone instead of relying on the server, which
+// accepts out-of-zone owner names in some configurations.
+func WithZoneCheck() Option {
+	return func(client *Client) {
+		client.zoneCheck = true
+	}
+}
+
+// Returns the fully qualified form of name when relative names are enabled
+func (client *Client) qualifyName(zone string, name string) (string, error) {
+	if !client.relativeNames {
+		return name, nil
+	}
+
+	if name == "" || name == "@" {
+		return zone, nil
+	}
+
+	if !strings.HasSuffix(name, ".") {
+		return name + "." + zone, nil
+	}
+
+	if !types.IsSubdomainOf(name, zone) {
+		return "", fmt.Errorf("%s is not in zone %s: %w", name, zone, ErrOutOfZone)
+	}
+
+	return name, nil
+}
+
+// Returns a copy of rrSets with every name qualified against Zone
+func (client *Client) qualifyRecordSets(zone string, rrSets []types.ResourceRecordSet) ([]types.ResourceRecordSet, error) {
+	if !client.relativeNames {
+		return rrSets, nil
+	}
+
+	qualified := make([]types.ResourceRecordSet, len(rrSets))
+	for i, rrSet := range rrSets {
+		name, err := client.qualifyName(zone, rrSet.Name)
+		if err != nil {
+			return nil, err
+		}
+		rrSet.Name = name
+		qualified[i] = rrSet
+	}
+
+	return qualified, nil
+}
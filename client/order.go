@@ -0,0 +1,9 @@
+package client
+
+// WithPreserveOrder returns records and rrsets in the order the API sent them instead
+// of sorting them canonically by name, type and content.
+func WithPreserveOrder() Option {
+	return func(client *Client) {
+		client.preserveOrder = true
+	}
+}
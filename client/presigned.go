@@ -0,0 +1,43 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// SetPresigned Marks Zone as presigned, so PowerDNS serves the RRSIG and DNSKEY
+// records it holds instead of signing the zone itself
+func (client *Client) SetPresigned(zone string, presigned bool) error {
+	reqBody, _ := json.Marshal(map[string]bool{"presigned": presigned})
+
+	req, err := client.newRequest("PUT", fmt.Sprintf("/servers/localhost/zones/%s", client.rewriteName(zone, true)), reqBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 && resp.StatusCode != 200 {
+		return newAPIError(resp, fmt.Sprintf("setting presigned of zone: %s", zone))
+	}
+
+	return nil
+}
+
+// ImportSignedRecordSets Replaces the given record sets in a presigned Zone, including the
+// RRSIG, DNSKEY and NSEC/NSEC3 sets produced by an external signer, in a single PATCH
+func (client *Client) ImportSignedRecordSets(zone string, rrSets []types.ResourceRecordSet) error {
+	changes := make([]types.ResourceRecordSet, len(rrSets))
+	for i, rrSet := range rrSets {
+		rrSet.ChangeType = types.ChangeTypeReplace
+		changes[i] = rrSet
+	}
+
+	return client.PatchRecordSets(zone, changes)
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+func TestSetPresignedUpdatesZone(t *testing.T) {
+	for presigned, want := range map[bool]string{true: `{"presigned":true}`, false: `{"presigned":false}`} {
+		client, server := newTestServer(t, types.ZoneInfo{Name: "example.com."})
+
+		if err := client.SetPresigned("example.com.", presigned); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := server.paths[len(server.paths)-1]; got != "PUT /api/v1/servers/localhost/zones/example.com." {
+			t.Errorf("SetPresigned(%v) sent %q, want a PUT of the zone", presigned, got)
+		}
+		if got := server.lastPut(t); got != want {
+			t.Errorf("SetPresigned(%v) body = %s, want %s", presigned, got, want)
+		}
+	}
+}
+
+func TestIsProtectedMetadata(t *testing.T) {
+	for kind, want := range map[string]bool{
+		"PRESIGNED":       true,
+		"soa-edit-api":    true,
+		"NSEC3PARAM":      true,
+		"ALLOW-AXFR-FROM": false,
+		"X-CUSTOM":        false,
+	} {
+		if got := IsProtectedMetadata(kind); got != want {
+			t.Errorf("IsProtectedMetadata(%s) = %v, want %v", kind, got, want)
+		}
+	}
+}
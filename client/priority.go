@@ -0,0 +1,150 @@
+package client
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+)
+
+// Priority Dispatch priority of the requests of a client, see WithPriorityQueue.
+type Priority int
+
+const (
+	// PriorityBulk Background traffic such as imports, served after every other request.
+	PriorityBulk Priority = -1
+	// PriorityNormal Default priority.
+	PriorityNormal Priority = 0
+	// PriorityCritical Control-plane traffic such as health failover PATCHes.
+	PriorityCritical Priority = 1
+)
+
+// WithPriorityQueue limits the client to concurrency requests in flight and dispatches
+// waiting requests by priority, oldest first within a priority, so critical calls are
+// not starved behind a bulk import sharing the client. Priorities are set per call with
+// Client.WithPriority.
+func WithPriorityQueue(concurrency int) Option {
+	return func(client *Client) {
+		if concurrency < 1 {
+			concurrency = 1
+		}
+		client.dispatcher = &dispatcher{free: concurrency}
+	}
+}
+
+// WithPriority Returns a copy of the client sending its requests with priority, e.g.
+// c.WithPriority(PriorityBulk) for an import. The copy shares the configuration, the
+// state and the queue of the client.
+func (client *Client) WithPriority(priority Priority) *Client {
+	prioritized := *client
+	prioritized.priority = priority
+	return &prioritized
+}
+
+// Request waiting for a slot
+type waiter struct {
+	priority Priority
+	seq      uint64
+	index    int
+	ready    chan struct{}
+}
+
+// Heap of waiters, highest priority and then lowest sequence number first
+type waiters []*waiter
+
+func (queue waiters) Len() int { return len(queue) }
+
+func (queue waiters) Less(i, j int) bool {
+	if queue[i].priority != queue[j].priority {
+		return queue[i].priority > queue[j].priority
+	}
+	return queue[i].seq < queue[j].seq
+}
+
+func (queue waiters) Swap(i, j int) {
+	queue[i], queue[j] = queue[j], queue[i]
+	queue[i].index = i
+	queue[j].index = j
+}
+
+func (queue *waiters) Push(x interface{}) {
+	w := x.(*waiter)
+	w.index = len(*queue)
+	*queue = append(*queue, w)
+}
+
+func (queue *waiters) Pop() interface{} {
+	old := *queue
+	w := old[len(old)-1]
+	old[len(old)-1] = nil
+	w.index = -1
+	*queue = old[:len(old)-1]
+	return w
+}
+
+// Bounds the requests in flight, handing free slots out by priority
+type dispatcher struct {
+	mutex   sync.Mutex
+	free    int
+	seq     uint64
+	waiting waiters
+}
+
+// Waits for a slot, or until ctx is done. A nil dispatcher never waits
+func (d *dispatcher) acquire(ctx context.Context, priority Priority) error {
+	if d == nil {
+		return nil
+	}
+
+	d.mutex.Lock()
+	if d.free > 0 && len(d.waiting) == 0 {
+		d.free--
+		d.mutex.Unlock()
+		return nil
+	}
+
+	d.seq++
+	w := &waiter{priority: priority, seq: d.seq, ready: make(chan struct{})}
+	heap.Push(&d.waiting, w)
+	d.mutex.Unlock()
+
+	select {
+	case <-w.ready:
+		return nil
+	case <-ctx.Done():
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if w.index >= 0 {
+		heap.Remove(&d.waiting, w.index)
+		return ctx.Err()
+	}
+
+	// the slot was handed over concurrently with the cancellation: pass it on
+	d.handOver()
+	return ctx.Err()
+}
+
+// Returns a slot taken with acquire
+func (d *dispatcher) release() {
+	if d == nil {
+		return
+	}
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.handOver()
+}
+
+// Gives a slot to the first waiter, or frees it; the mutex is held by the caller
+func (d *dispatcher) handOver() {
+	if len(d.waiting) == 0 {
+		d.free++
+		return
+	}
+
+	w := heap.Pop(&d.waiting).(*waiter)
+	close(w.ready)
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// ZoneAPI Zone operations of the PowerDNS API.
+type ZoneAPI interface {
+	ListZones() ([]types.ZoneInfo, error)
+	GetZone(zone string) (*types.ZoneInfo, error)
+	ZoneExists(zone string) (bool, error)
+	CreateZone(zone types.ZoneInfo, soa *types.SOATemplate) (*types.ZoneInfo, error)
+	DeleteZone(zone string, opts DeleteZoneOptions) (*DeletedZone, error)
+	SetPresigned(zone string, presigned bool) error
+	SetSOAEditAPI(zone string, value string) error
+}
+
+// RecordAPI Record and record set operations of the PowerDNS API.
+type RecordAPI interface {
+	ListRecords(zone string) ([]types.Record, error)
+	ListRecordsAsRRSet(zone string) ([]types.ResourceRecordSet, error)
+	GetRecordSet(zone string, name string, tpe string) (*types.ResourceRecordSet, error)
+	ListRecordsByNameAndType(zone string, name string, tpe string) ([]types.Record, error)
+	ListRecordsByID(zone string, recID string) ([]types.Record, error)
+	GetRecordsByName(zone string, name string) ([]types.ResourceRecordSet, error)
+	GetRecordsByType(zone string, tpe string) ([]types.ResourceRecordSet, error)
+	RecordExists(zone string, name string, tpe string) (bool, error)
+	RecordExistsByID(zone string, recID string) (bool, error)
+	CreateRecord(zone string, record types.Record) (string, error)
+	ReplaceRecordSet(zone string, rrSet types.ResourceRecordSet) (string, error)
+	DeleteRecordSet(zone string, name string, tpe string) error
+	DeleteRecordSetByID(zone string, recID string) error
+	RenameRecord(zone string, oldName string, newName string, tpe string) error
+	PatchRecordSets(zone string, rrSets []types.ResourceRecordSet) error
+}
+
+// MetadataAPI Zone metadata operations of the PowerDNS API.
+type MetadataAPI interface {
+	ListMetadata(zone string) ([]types.Metadata, error)
+	GetMetadata(zone string, kind string) ([]string, error)
+	SetMetadata(zone string, kind string, values []string) error
+	DeleteMetadata(zone string, kind string) error
+}
+
+// DNSSECAPI DNSSEC key operations of the PowerDNS API.
+type DNSSECAPI interface {
+	ListCryptokeys(zone string) ([]types.Cryptokey, error)
+	GetCryptokey(zone string, id int) (*types.Cryptokey, error)
+	CreateCryptokey(zone string, key types.Cryptokey) (*types.Cryptokey, error)
+	SetCryptokeyActive(zone string, id int, active bool) error
+	DeleteCryptokey(zone string, id int) error
+}
+
+// Provider Zone, record, metadata and DNSSEC operations of the PowerDNS API. It is
+// implemented by *Client and by the in-memory backend of package memory. Code that only
+// needs part of the API should accept the narrower ZoneAPI, RecordAPI, MetadataAPI or
+// DNSSECAPI, which are easier to mock and to decorate, e.g. with caching or metrics.
+type Provider interface {
+	ZoneAPI
+	RecordAPI
+	MetadataAPI
+	DNSSECAPI
+}
+
+var _ Provider = (*Client)(nil)
+
+// RecordAPIWithContext Returns api bound to ctx when it is a *Client, so its requests are
+// cancelled with ctx. Other implementations are returned as they are.
+func RecordAPIWithContext(ctx context.Context, api RecordAPI) RecordAPI {
+	if client, ok := api.(*Client); ok {
+		return client.WithContext(ctx)
+	}
+
+	return api
+}
@@ -0,0 +1,21 @@
+package client
+
+import (
+	"errors"
+)
+
+// ErrReadOnly is returned for every mutating call made through a read-only client.
+var ErrReadOnly = errors.New("Client is read-only")
+
+// WithReadOnly makes the client reject every mutating call locally with ErrReadOnly,
+// even when its API key would allow changes. Useful for dashboards and audit tooling.
+func WithReadOnly() Option {
+	return func(client *Client) {
+		client.readOnly = true
+	}
+}
+
+// ReadOnly Returns true when the client rejects mutating calls
+func (client *Client) ReadOnly() bool {
+	return client.readOnly
+}
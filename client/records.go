@@ -0,0 +1,361 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// ListRecords Returns all records in Zone
+func (client *Client) ListRecords(zone string) ([]types.Record, error) {
+	zoneInfo, err := client.GetZone(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	records := zoneInfo.Records
+	// Convert the API v1 response to v0 record structure
+	for _, rrs := range zoneInfo.ResourceRecordSets {
+		records = append(records, rrs.Flatten()...)
+	}
+
+	if !client.preserveOrder {
+		types.SortRecords(records)
+	}
+
+	return records, nil
+}
+
+// ListRecordsAsRRSet Returns only records of specified name and type
+func (client *Client) ListRecordsAsRRSet(zone string) ([]types.ResourceRecordSet, error) {
+	zoneInfo, err := client.GetZone(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(zoneInfo.ResourceRecordSets) == 0 {
+		return nil, nil
+	}
+
+	if !client.preserveOrder {
+		types.SortRecordSets(zoneInfo.ResourceRecordSets)
+	}
+
+	return zoneInfo.ResourceRecordSets, nil
+}
+
+// GetRecordSet Returns the record set of specified name and type, or nil when it does not exist.
+// The record set may have no records when only comments are set on it.
+func (client *Client) GetRecordSet(zone string, name string, tpe string) (*types.ResourceRecordSet, error) {
+	name, err := client.qualifyName(zone, name)
+	if err != nil {
+		return nil, err
+	}
+
+	rrSets, err := client.ListRecordsAsRRSet(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rrSet := range rrSets {
+		if types.EqualNames(rrSet.Name, name) && rrSet.Type == types.RecordType(tpe) {
+			return &rrSet, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// ListRecordsByNameAndType Returns only records of specified name and type
+func (client *Client) ListRecordsByNameAndType(zone string, name string, tpe string) ([]types.Record, error) {
+	name, err := client.qualifyName(zone, name)
+	if err != nil {
+		return nil, err
+	}
+
+	allRecords, err := client.ListRecords(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]types.Record, 0, 10)
+	for _, r := range allRecords {
+		if types.EqualNames(r.Name, name) && r.Type == types.RecordType(tpe) {
+			records = append(records, r)
+		}
+	}
+
+	return records, nil
+}
+
+// ListRecordsByID returns only records that match the specified record IDentifier.
+func (client *Client) ListRecordsByID(zone string, recID string) ([]types.Record, error) {
+	name, tpe, err := types.ParseID(recID)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return client.ListRecordsByNameAndType(zone, name, tpe)
+}
+
+// GetRecordsByName Returns the record sets of name in zone. The server filters them when
+// it supports rrset filters, otherwise the whole zone is fetched and filtered locally.
+func (client *Client) GetRecordsByName(zone string, name string) ([]types.ResourceRecordSet, error) {
+	name, err := client.qualifyName(zone, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return client.filterRecordSets(zone, name, "")
+}
+
+// GetRecordsByType Returns the record sets of type tpe in zone. The API cannot filter by
+// type alone, so the zone is fetched and filtered locally.
+func (client *Client) GetRecordsByType(zone string, tpe string) ([]types.ResourceRecordSet, error) {
+	return client.filterRecordSets(zone, "", tpe)
+}
+
+// Returns the record sets of zone matching name and type, either being optional
+func (client *Client) filterRecordSets(zone string, name string, tpe string) ([]types.ResourceRecordSet, error) {
+	var rrSets []types.ResourceRecordSet
+	if name != "" && client.Supports(FeatureRRSetFilter) == nil {
+		query := url.Values{"rrset_name": {name}}
+		if tpe != "" {
+			query.Set("rrset_type", tpe)
+		}
+
+		zoneInfo, err := client.getZone(zone, query)
+		if err != nil {
+			return nil, err
+		}
+		rrSets = zoneInfo.ResourceRecordSets
+
+		if !client.preserveOrder {
+			types.SortRecordSets(rrSets)
+		}
+	} else {
+		var err error
+		if rrSets, err = client.ListRecordsAsRRSet(zone); err != nil {
+			return nil, err
+		}
+	}
+
+	filtered := make([]types.ResourceRecordSet, 0, len(rrSets))
+	for _, rrSet := range rrSets {
+		if (name == "" || types.EqualNames(rrSet.Name, name)) && (tpe == "" || rrSet.Type == types.RecordType(tpe)) {
+			filtered = append(filtered, rrSet)
+		}
+	}
+
+	return filtered, nil
+}
+
+// RecordExists Checks if requested record exists in Zone
+func (client *Client) RecordExists(zone string, name string, tpe string) (bool, error) {
+	name, err := client.qualifyName(zone, name)
+	if err != nil {
+		return false, err
+	}
+
+	allRecords, err := client.ListRecords(zone)
+	if err != nil {
+		return false, err
+	}
+
+	for _, record := range allRecords {
+		if types.EqualNames(record.Name, name) && record.Type == types.RecordType(tpe) {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// RecordExistsByID Checks if requested record exists in Zone by it's ID
+func (client *Client) RecordExistsByID(zone string, recID string) (bool, error) {
+	name, tpe, err := types.ParseID(recID)
+
+	if err != nil {
+		return false, err
+	}
+
+	return client.RecordExists(zone, name, tpe)
+}
+
+// CreateRecord Creates new record with single content entry
+func (client *Client) CreateRecord(zone string, record types.Record) (string, error) {
+	err := client.patch(zone, []types.ResourceRecordSet{
+		{
+			Name:       record.Name,
+			Type:       record.Type,
+			ChangeType: types.ChangeTypeReplace,
+			TTL:        record.TTL,
+			Records:    []types.Record{record},
+		},
+	}, fmt.Sprintf("creating record: %s", record.ID()))
+	if err != nil {
+		return "", err
+	}
+
+	return record.ID(), nil
+}
+
+// ReplaceRecordSet Creates new record set in Zone
+func (client *Client) ReplaceRecordSet(zone string, rrSet types.ResourceRecordSet) (string, error) {
+	rrSet.ChangeType = types.ChangeTypeReplace
+
+	err := client.patch(zone, []types.ResourceRecordSet{rrSet}, fmt.Sprintf("creating record set: %s", rrSet.ID()))
+	if err != nil {
+		return "", err
+	}
+
+	return rrSet.ID(), nil
+}
+
+// ReplaceRecordContents Creates or replaces the record set of name and type with contents,
+// which can be created disabled, e.g. to stage a cutover enabled later with one PATCH.
+func (client *Client) ReplaceRecordContents(zone string, name string, tpe string, ttl int, contents []types.RecordContent) (string, error) {
+	return client.ReplaceRecordSet(zone, types.NewRecordSet(name, types.RecordType(tpe), ttl, contents))
+}
+
+// DeleteRecordSet Deletes record set from Zone
+func (client *Client) DeleteRecordSet(zone string, name string, tpe string) error {
+	return client.patch(zone, []types.ResourceRecordSet{
+		{
+			Name:       name,
+			Type:       types.RecordType(tpe),
+			ChangeType: types.ChangeTypeDelete,
+		},
+	}, fmt.Sprintf("deleting record: %s %s", name, tpe))
+}
+
+// DeleteRecordSetByID Deletes record from Zone by it's ID
+func (client *Client) DeleteRecordSetByID(zone string, recID string) error {
+	name, tpe, err := types.ParseID(recID)
+	if err != nil {
+		return err
+	}
+
+	return client.DeleteRecordSet(zone, name, tpe)
+}
+
+// RenameRecord Moves the record set of oldName and type to newName in a single PATCH,
+// preserving its records, TTL and comments. It fails when newName already has a record
+// set of that type.
+func (client *Client) RenameRecord(zone string, oldName string, newName string, tpe string) error {
+	operation := fmt.Sprintf("renaming record: %s %s to %s", oldName, tpe, newName)
+
+	rrSet, err := client.GetRecordSet(zone, oldName, tpe)
+	if err != nil {
+		return err
+	}
+	if rrSet == nil {
+		return fmt.Errorf("Error %s, record set does not exist", operation)
+	}
+
+	existing, err := client.GetRecordSet(zone, newName, tpe)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("Error %s, target record set already exists", operation)
+	}
+
+	renamed := *rrSet
+	renamed.Name = newName
+	renamed.ChangeType = types.ChangeTypeReplace
+
+	return client.patch(zone, []types.ResourceRecordSet{
+		renamed,
+		{
+			Name:       rrSet.Name,
+			Type:       rrSet.Type,
+			ChangeType: types.ChangeTypeDelete,
+		},
+	}, operation)
+}
+
+// PatchRecordSets Applies the given record set changes to Zone. The changes are sent in a
+// single PATCH unless a batch size is configured with WithPatchBatchSize, in which case
+// they are split into several PATCH calls and are no longer applied atomically.
+func (client *Client) PatchRecordSets(zone string, rrSets []types.ResourceRecordSet) error {
+	if err := client.guardChanges(zone, rrSets); err != nil {
+		return err
+	}
+
+	// the change guard covers all batches at once
+	batchClient := client.WithForce()
+	for _, batch := range splitBatches(rrSets, client.patchBatchSize) {
+		if err := batchClient.patch(zone, batch, fmt.Sprintf("patching record sets in zone: %s", zone)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Validates and sends record set changes to Zone, creating the zone first when it does
+// not exist and auto-creation is enabled
+func (client *Client) patch(zone string, rrSets []types.ResourceRecordSet, operation string) error {
+	if err := client.guardChanges(zone, rrSets); err != nil {
+		return err
+	}
+
+	rrSets, err := client.qualifyRecordSets(zone, rrSets)
+	if err != nil {
+		return fmt.Errorf("Error %s, %w", operation, err)
+	}
+	rrSets = client.normalizeTargets(rrSets)
+
+	if rrSets, err = client.fillDefaultTTL(zone, rrSets); err != nil {
+		return fmt.Errorf("Error %s, %w", operation, err)
+	}
+
+	if err = client.validateRecordSets(zone, rrSets); err != nil {
+		return fmt.Errorf("Error %s, %w", operation, err)
+	}
+
+	err = client.sendPatch(zone, rrSets, operation)
+	if err == nil || client.autoCreateZone == nil || !isZoneNotFound(err) {
+		return err
+	}
+
+	if exists, existsErr := client.ZoneExists(zone); existsErr != nil || exists {
+		return err
+	}
+
+	if err = client.createZoneFromTemplate(zone); err != nil {
+		return err
+	}
+
+	return client.sendPatch(zone, rrSets, operation)
+}
+
+func (client *Client) sendPatch(zone string, rrSets []types.ResourceRecordSet, operation string) error {
+	reqBody, _ := json.Marshal(zonePatchRequest{
+		RecordSets: client.rewriteRecordSets(rrSets, true),
+	})
+
+	req, err := client.newRequest("PATCH", fmt.Sprintf("/servers/localhost/zones/%s", client.rewriteName(zone, true)), reqBody)
+	if err != nil {
+		return err
+	}
+	req = withRecordSetCount(req, len(rrSets))
+
+	resp, err := client.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 204 {
+		apiErr := newAPIError(resp, operation)
+		apiErr.annotateRRSet()
+		return apiErr
+	}
+
+	return nil
+}
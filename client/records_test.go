@@ -0,0 +1,216 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+func TestListRecordsFlattensRecordSets(t *testing.T) {
+	cases := []struct {
+		name  string
+		rrSet types.ResourceRecordSet
+		want  []types.Record
+	}{
+		{
+			name: "single record",
+			rrSet: types.ResourceRecordSet{Name: "www.example.com.", Type: types.TypeA, TTL: 300,
+				Records: []types.Record{{Content: "192.0.2.1"}}},
+			want: []types.Record{
+				{Name: "www.example.com.", Type: types.TypeA, Content: "192.0.2.1", TTL: 300},
+			},
+		},
+		{
+			name: "multiple records",
+			rrSet: types.ResourceRecordSet{Name: "www.example.com.", Type: types.TypeA, TTL: 300,
+				Records: []types.Record{{Content: "192.0.2.2"}, {Content: "192.0.2.1"}, {Content: "192.0.2.3"}}},
+			want: []types.Record{
+				{Name: "www.example.com.", Type: types.TypeA, Content: "192.0.2.1", TTL: 300},
+				{Name: "www.example.com.", Type: types.TypeA, Content: "192.0.2.2", TTL: 300},
+				{Name: "www.example.com.", Type: types.TypeA, Content: "192.0.2.3", TTL: 300},
+			},
+		},
+		{
+			name: "disabled records",
+			rrSet: types.ResourceRecordSet{Name: "mail.example.com.", Type: types.TypeMX, TTL: 3600,
+				Records: []types.Record{{Content: "10 mx1.example.com."}, {Content: "20 mx2.example.com.", Disabled: true}}},
+			want: []types.Record{
+				{Name: "mail.example.com.", Type: types.TypeMX, Content: "10 mx1.example.com.", TTL: 3600},
+				{Name: "mail.example.com.", Type: types.TypeMX, Content: "20 mx2.example.com.", TTL: 3600, Disabled: true},
+			},
+		},
+		{
+			name: "comments only",
+			rrSet: types.ResourceRecordSet{Name: "note.example.com.", Type: types.TypeTXT, TTL: 3600,
+				Comments: []types.Comment{{Content: "reserved"}}},
+			want: []types.Record{},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, _ := newTestServer(t, types.ZoneInfo{
+				Name:               "example.com.",
+				ResourceRecordSets: []types.ResourceRecordSet{tc.rrSet},
+			})
+
+			got, err := client.ListRecords("example.com.")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) == 0 && len(tc.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ListRecords() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPatchBodies(t *testing.T) {
+	cases := []struct {
+		name  string
+		patch func(client *Client) error
+		want  string
+	}{
+		{
+			name: "multiple records",
+			patch: func(client *Client) error {
+				_, err := client.ReplaceRecordContents("example.com.", "www.example.com.", "A", 300, []types.RecordContent{
+					{Content: "192.0.2.1"},
+					{Content: "192.0.2.2"},
+				})
+				return err
+			},
+			want: `{"rrsets": [{"name": "www.example.com.", "type": "A", "changetype": "REPLACE", "ttl": 300, "records": [
+				{"name": "", "type": "", "content": "192.0.2.1", "ttl": 0, "disabled": false},
+				{"name": "", "type": "", "content": "192.0.2.2", "ttl": 0, "disabled": false}]}]}`,
+		},
+		{
+			name: "disabled records",
+			patch: func(client *Client) error {
+				_, err := client.ReplaceRecordContents("example.com.", "www.example.com.", "A", 300, []types.RecordContent{
+					{Content: "192.0.2.1"},
+					{Content: "192.0.2.2", Disabled: true},
+				})
+				return err
+			},
+			want: `{"rrsets": [{"name": "www.example.com.", "type": "A", "changetype": "REPLACE", "ttl": 300, "records": [
+				{"name": "", "type": "", "content": "192.0.2.1", "ttl": 0, "disabled": false},
+				{"name": "", "type": "", "content": "192.0.2.2", "ttl": 0, "disabled": true}]}]}`,
+		},
+		{
+			name: "all records disabled",
+			patch: func(client *Client) error {
+				_, err := client.ReplaceRecordSet("example.com.", types.ResourceRecordSet{
+					Name:    "mail.example.com.",
+					Type:    types.TypeMX,
+					TTL:     3600,
+					Records: []types.Record{{Content: "10 mx1.example.com.", Disabled: true}, {Content: "20 mx2.example.com.", Disabled: true}},
+				})
+				return err
+			},
+			want: `{"rrsets": [{"name": "mail.example.com.", "type": "MX", "changetype": "REPLACE", "ttl": 3600, "records": [
+				{"name": "", "type": "", "content": "10 mx1.example.com.", "ttl": 0, "disabled": true},
+				{"name": "", "type": "", "content": "20 mx2.example.com.", "ttl": 0, "disabled": true}]}]}`,
+		},
+		{
+			name: "delete",
+			patch: func(client *Client) error {
+				return client.DeleteRecordSet("example.com.", "www.example.com.", "A")
+			},
+			want: `{"rrsets": [{"name": "www.example.com.", "type": "A", "changetype": "DELETE", "ttl": 0}]}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			client, server := newTestServer(t, types.ZoneInfo{Name: "example.com."})
+
+			if err := tc.patch(client); err != nil {
+				t.Fatal(err)
+			}
+
+			if got, want := server.lastPatch(t), compactJSON(t, tc.want); compactJSON(t, got) != want {
+				t.Errorf("PATCH body = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestPatchBodyRoundTripsFlatten(t *testing.T) {
+	rrSet := types.NewRecordSet("www.example.com.", types.TypeAAAA, 60, []types.RecordContent{
+		{Content: "2001:db8::1"},
+		{Content: "2001:db8::2", Disabled: true},
+	})
+
+	client, server := newTestServer(t, types.ZoneInfo{Name: "example.com."})
+	if _, err := client.ReplaceRecordSet("example.com.", rrSet); err != nil {
+		t.Fatal(err)
+	}
+
+	var sent zonePatchRequest
+	if err := json.Unmarshal([]byte(server.lastPatch(t)), &sent); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent.RecordSets) != 1 {
+		t.Fatalf("sent %d record sets, want 1", len(sent.RecordSets))
+	}
+
+	if got, want := sent.RecordSets[0].Flatten(), rrSet.Flatten(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Flatten() of sent record set = %+v, want %+v", got, want)
+	}
+}
+
+func BenchmarkListRecords(b *testing.B) {
+	zone := types.ZoneInfo{Name: "example.com."}
+	for i := 0; i < 10000; i++ {
+		zone.ResourceRecordSets = append(zone.ResourceRecordSets, types.ResourceRecordSet{
+			Name:    fmt.Sprintf("host%06d.example.com.", i),
+			Type:    types.TypeA,
+			TTL:     300,
+			Records: []types.Record{{Content: fmt.Sprintf("10.0.%d.%d", i>>8&0xff, i&0xff)}},
+		})
+	}
+	client, _ := newTestServer(b, zone)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.ListRecords("example.com."); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestLookupsIgnoreNameCase(t *testing.T) {
+	client, _ := newTestServer(t, types.ZoneInfo{
+		Name: "example.com.",
+		ResourceRecordSets: []types.ResourceRecordSet{{Name: "www.example.com.", Type: types.TypeA, TTL: 300,
+			Records: []types.Record{{Content: "192.0.2.1"}}}},
+	})
+
+	rrSet, err := client.GetRecordSet("example.com.", "WWW.Example.COM.", "A")
+	if err != nil || rrSet == nil {
+		t.Errorf("GetRecordSet() = %v, %v; want the www rrset", rrSet, err)
+	}
+
+	records, err := client.ListRecordsByNameAndType("example.com.", "WWW.Example.COM.", "A")
+	if err != nil || len(records) != 1 {
+		t.Errorf("ListRecordsByNameAndType() = %v, %v; want 1 record", records, err)
+	}
+
+	exists, err := client.RecordExists("example.com.", "WWW.Example.COM.", "A")
+	if err != nil || !exists {
+		t.Errorf("RecordExists() = %v, %v; want true", exists, err)
+	}
+
+	rrSets, err := client.GetRecordsByName("example.com.", "WWW.Example.COM.")
+	if err != nil || len(rrSets) != 1 {
+		t.Errorf("GetRecordsByName() = %v, %v; want 1 rrset", rrSets, err)
+	}
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"strings"
+)
+
+// Redacted replaces credentials in redacted output.
+const Redacted = "[REDACTED]"
+
+// String Describes the client without revealing its API key.
+func (client *Client) String() string {
+	return fmt.Sprintf("powerdns.Client{ServerURL: %q, APIVersion: %d, APIKey: %q}", client.serverURL, client.apiVersion, Redacted)
+}
+
+// GoString Describes the client for %#v without revealing its API key.
+func (client *Client) GoString() string {
+	return client.String()
+}
+
+// Redact Returns s with every occurrence of the API key replaced, for log lines and
+// error messages built from data the client handled.
+func (client *Client) Redact(s string) string {
+	if client.apiKey == "" {
+		return s
+	}
+
+	return strings.Replace(s, client.apiKey, Redacted, -1)
+}
+
+// HasAPIKey Compares key with the API key of the client in constant time.
+func (client *Client) HasAPIKey(key string) bool {
+	return subtle.ConstantTimeCompare([]byte(key), []byte(client.apiKey)) == 1
+}
+
+// redactedError Error whose message has the API key removed, still wrapping the original
+type redactedError struct {
+	message string
+	err     error
+}
+
+func (err *redactedError) Error() string {
+	return err.message
+}
+
+func (err *redactedError) Unwrap() error {
+	return err.err
+}
+
+// Wraps err into an error prefixed with context whose message never contains the API key
+func (client *Client) wrapError(context string, err error) error {
+	return &redactedError{
+		message: client.Redact(context + ": " + err.Error()),
+		err:     err,
+	}
+}
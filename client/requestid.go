@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// RequestIDHeader Header carrying the ID correlating a call across client logs, errors
+// and proxies in front of PowerDNS.
+const RequestIDHeader = "X-Request-ID"
+
+type requestIDKey struct{}
+
+// ContextWithRequestID Returns a copy of ctx carrying id, sent as X-Request-ID by
+// clients bound to the context with WithContext.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext Returns the request ID carried by ctx, if any.
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// WithRequestIDs makes the client send a random X-Request-ID with every request that
+// has none from its context. The ID is reported in responses passed to response hooks
+// and in errors.
+func WithRequestIDs() Option {
+	return func(client *Client) {
+		client.requestIDs = true
+	}
+}
+
+// WithContext Returns a copy of the client sending its requests with ctx, so they are
+// cancelled with it and carry its request ID. The copy shares the configuration and
+// state, such as caches and the circuit breaker, of the client.
+func (client *Client) WithContext(ctx context.Context) *Client {
+	bound := *client
+	bound.ctx = ctx
+	return &bound
+}
+
+// Returns the request ID to send: the one of the client context, or a new one when
+// request IDs are enabled
+func (client *Client) requestID() string {
+	if client.ctx != nil {
+		if id := RequestIDFromContext(client.ctx); id != "" {
+			return id
+		}
+	}
+
+	if !client.requestIDs {
+		return ""
+	}
+
+	return NewRequestID()
+}
+
+// NewRequestID Returns a random request ID, for callers that propagate their own.
+func NewRequestID() string {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return ""
+	}
+
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,102 @@
+package client
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultRetryAfter is used when a 429 response carries no usable Retry-After header.
+const defaultRetryAfter = time.Second
+
+// maxRetryAfter caps how long the client sleeps on a single 429 response.
+const maxRetryAfter = time.Minute
+
+// Response HTTP response metadata of an API call.
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	// RequestID is the X-Request-ID sent with the request, if any.
+	RequestID string
+}
+
+// ResponseHook is invoked with the metadata of every HTTP response received by the client.
+type ResponseHook func(*Response)
+
+// WithResponseHook registers a hook called for every HTTP response, e.g. to record
+// server version or rate-limit headers added by an API gateway.
+func WithResponseHook(hook ResponseHook) Option {
+	return func(client *Client) {
+		client.responseHooks = append(client.responseHooks, hook)
+	}
+}
+
+// WithRateLimitRetry makes the client sleep for the duration given by Retry-After and
+// retry when the server (or a proxy in front of it) answers 429, at most maxRetries times.
+func WithRateLimitRetry(maxRetries int) Option {
+	return func(client *Client) {
+		client.rateLimitRetries = maxRetries
+	}
+}
+
+func newResponse(resp *http.Response) *Response {
+	return &Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+	}
+}
+
+// ServerVersion Returns the PowerDNS version advertised by the server, if any.
+func (resp *Response) ServerVersion() string {
+	if version := resp.Header.Get("X-PDNS-Version"); version != "" {
+		return version
+	}
+
+	server := resp.Header.Get("Server")
+	if strings.HasPrefix(server, "PowerDNS/") {
+		return strings.TrimPrefix(server, "PowerDNS/")
+	}
+
+	return ""
+}
+
+// RateLimit Returns the request limit and remaining requests reported by rate-limit
+// headers. ok is false when the response carries no such headers.
+func (resp *Response) RateLimit() (limit int, remaining int, ok bool) {
+	for _, prefix := range []string{"X-RateLimit-", "RateLimit-"} {
+		l, errL := strconv.Atoi(resp.Header.Get(prefix + "Limit"))
+		r, errR := strconv.Atoi(resp.Header.Get(prefix + "Remaining"))
+		if errL == nil && errR == nil {
+			return l, r, true
+		}
+	}
+
+	return 0, 0, false
+}
+
+// RetryAfter Returns how long to wait before retrying, as advertised by Retry-After.
+func (resp *Response) RetryAfter() time.Duration {
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return defaultRetryAfter
+	}
+
+	var wait time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		wait = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		wait = time.Until(date)
+	} else {
+		return defaultRetryAfter
+	}
+
+	if wait < 0 {
+		return 0
+	}
+	if wait > maxRetryAfter {
+		return maxRetryAfter
+	}
+
+	return wait
+}
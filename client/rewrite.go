@@ -0,0 +1,119 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// RewriteRule Maps names at or below Match, as the caller uses them, to the same names
+// below Target on the server, e.g. "svc.internal." to "example.com.".
+type RewriteRule struct {
+	Match  string
+	Target string
+}
+
+// Record types whose content is a domain name, or ends with one
+var nameContentTypes = map[types.RecordType]bool{
+	types.TypeCNAME: true, types.TypeDNAME: true, types.TypeNS: true, types.TypePTR: true,
+	types.TypeALIAS: true, types.TypeMX: true, types.TypeSRV: true,
+}
+
+// WithRewriteRules rewrites zone and record names, and the names in CNAME, DNAME, NS,
+// PTR, ALIAS, MX and SRV contents, with the first matching rule on write and back on
+// read. The same automation code can then publish internal and external views with
+// different suffixes through two clients. Rewriting covers zone reads, zone listing,
+// creation and deletion, metadata, cryptokeys and record set writes.
+func WithRewriteRules(rules ...RewriteRule) Option {
+	return func(client *Client) {
+		client.rewriteRules = append(client.rewriteRules, rules...)
+	}
+}
+
+// Returns name rewritten by the first matching rule, towards the server or back
+func (client *Client) rewriteName(name string, toServer bool) string {
+	for _, rule := range client.rewriteRules {
+		from, to := rule.Match, rule.Target
+		if !toServer {
+			from, to = to, from
+		}
+
+		relative, ok := types.SplitRelative(name, from)
+		if !ok || strings.Trim(from, ".") == "" {
+			continue
+		}
+
+		to = strings.TrimSuffix(to, ".")
+		if strings.HasSuffix(name, ".") {
+			to += "."
+		}
+		if relative == "" {
+			return to
+		}
+		return relative + "." + to
+	}
+
+	return name
+}
+
+// Returns a copy of rrSets with names and name contents rewritten
+func (client *Client) rewriteRecordSets(rrSets []types.ResourceRecordSet, toServer bool) []types.ResourceRecordSet {
+	if len(client.rewriteRules) == 0 {
+		return rrSets
+	}
+
+	rewritten := make([]types.ResourceRecordSet, len(rrSets))
+	for i, rrSet := range rrSets {
+		rrSet.Name = client.rewriteName(rrSet.Name, toServer)
+		rrSet.Records = client.rewriteRecords(rrSet.Records, rrSet.Type, toServer)
+		rewritten[i] = rrSet
+	}
+
+	return rewritten
+}
+
+// Returns a copy of records with names and name contents rewritten; tpe is used for
+// records without a type of their own
+func (client *Client) rewriteRecords(records []types.Record, tpe types.RecordType, toServer bool) []types.Record {
+	if len(client.rewriteRules) == 0 || records == nil {
+		return records
+	}
+
+	rewritten := make([]types.Record, len(records))
+	for i, record := range records {
+		if record.Name != "" {
+			record.Name = client.rewriteName(record.Name, toServer)
+		}
+
+		recordType := record.Type
+		if recordType == "" {
+			recordType = tpe
+		}
+		if nameContentTypes[recordType] {
+			// the name is the last field: MX and SRV contents start with numbers
+			fields := strings.Fields(record.Content)
+			if len(fields) > 0 {
+				fields[len(fields)-1] = client.rewriteName(fields[len(fields)-1], toServer)
+				record.Content = strings.Join(fields, " ")
+			}
+		}
+		rewritten[i] = record
+	}
+
+	return rewritten
+}
+
+// Rewrites the names of a zone read from the server
+func (client *Client) rewriteZoneInfo(zoneInfo *types.ZoneInfo) {
+	if len(client.rewriteRules) == 0 {
+		return
+	}
+
+	name := client.rewriteName(zoneInfo.Name, false)
+	if zoneInfo.ID == zoneInfo.Name {
+		zoneInfo.ID = name
+	}
+	zoneInfo.Name = name
+	zoneInfo.Records = client.rewriteRecords(zoneInfo.Records, "", false)
+	zoneInfo.ResourceRecordSets = client.rewriteRecordSets(zoneInfo.ResourceRecordSets, false)
+}
@@ -0,0 +1,34 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+func TestRewriteRulesZonePaths(t *testing.T) {
+	client, server := newTestServer(t, types.ZoneInfo{Name: "example.com."})
+	WithRewriteRules(RewriteRule{Match: "svc.internal.", Target: "example.com."})(client)
+
+	if _, err := client.DeleteZone("svc.internal.", DeleteZoneOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	client.GetMetadata("svc.internal.", "ALLOW-AXFR-FROM")
+	client.ListCryptokeys("svc.internal.")
+
+	want := map[string]bool{
+		"GET /api/v1/servers/localhost/zones/example.com.":                          true,
+		"DELETE /api/v1/servers/localhost/zones/example.com.":                       true,
+		"GET /api/v1/servers/localhost/zones/example.com./metadata/ALLOW-AXFR-FROM": true,
+		"GET /api/v1/servers/localhost/zones/example.com./cryptokeys":               true,
+	}
+	for _, path := range server.paths[1:] {
+		if !want[path] {
+			t.Errorf("unexpected request %s", path)
+		}
+		delete(want, path)
+	}
+	for path := range want {
+		t.Errorf("missing request %s", path)
+	}
+}
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// SearchPageSize Number of results SearchAll asks for first.
+var SearchPageSize = 100
+
+// MaxSearchResults Largest result count SearchAll asks for before giving up with
+// ErrSearchTruncated.
+var MaxSearchResults = 100000
+
+// ErrSearchTruncated is returned by SearchAll when a search matches more than
+// MaxSearchResults results.
+var ErrSearchTruncated = errors.New("search results truncated")
+
+// Search Returns at most max zones, records and comments matching query, where "*" and
+// "?" are wildcards. objectType is one of the types.SearchObject constants, all when
+// empty. The server silently truncates the results to max.
+func (client *Client) Search(query string, max int, objectType string) ([]types.SearchResult, error) {
+	params := url.Values{"q": {query}, "max": {strconv.Itoa(max)}}
+	if objectType != "" {
+		params.Set("object_type", objectType)
+	}
+
+	req, err := client.newRequest("GET", "/servers/localhost/search-data", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = params.Encode()
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, newAPIError(resp, fmt.Sprintf("searching for: %q", query))
+	}
+
+	var results []types.SearchResult
+	err = json.NewDecoder(resp.Body).Decode(&results)
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// SearchAll Calls fn with every result matching query, so large searches are not
+// silently truncated. search-data has no offsets, so when a page comes back full the
+// search is repeated with twice the limit and only the results not seen before are
+// passed to fn. It stops with ErrSearchTruncated beyond MaxSearchResults, and with the
+// error of fn when fn fails.
+func (client *Client) SearchAll(query string, objectType string, fn func(types.SearchResult) error) error {
+	seen := make(map[types.SearchResult]bool)
+	for max := SearchPageSize; ; max *= 2 {
+		if max > MaxSearchResults {
+			max = MaxSearchResults
+		}
+
+		results, err := client.Search(query, max, objectType)
+		if err != nil {
+			return err
+		}
+
+		for _, result := range results {
+			if seen[result] {
+				continue
+			}
+			seen[result] = true
+
+			if err = fn(result); err != nil {
+				return err
+			}
+		}
+
+		if len(results) < max {
+			return nil
+		}
+		if max == MaxSearchResults {
+			return fmt.Errorf("Error searching for: %q, more than %d results: %w", query, max, ErrSearchTruncated)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package client
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SlowCall API call that took longer than the slow call threshold of the client.
+type SlowCall struct {
+	// Operation is the method and resource path without names, e.g. "PATCH zones" or
+	// "GET zones/metadata", so it can be used as a metric label.
+	Operation string
+	Zone      string
+	// RecordSets is the number of rrsets sent with a PATCH, zero for other calls.
+	RecordSets int
+	StatusCode int
+	Duration   time.Duration
+	RequestID  string
+}
+
+// SlowCallHook is invoked for every call exceeding the slow call threshold.
+type SlowCallHook func(SlowCall)
+
+// WithSlowCalls calls hooks for every API call whose response took longer than
+// threshold, to spot oversized PATCHes or an overloaded PowerDNS backend. Each attempt
+// of a retried call is timed on its own.
+func WithSlowCalls(threshold time.Duration, hooks ...SlowCallHook) Option {
+	return func(client *Client) {
+		client.slowCallThreshold = threshold
+		client.slowCallHooks = append(client.slowCallHooks, hooks...)
+	}
+}
+
+// LogSlowCalls Returns a hook writing a warning per slow call to logger.
+func LogSlowCalls(logger *log.Logger) SlowCallHook {
+	return func(call SlowCall) {
+		logger.Printf("warning: slow PowerDNS call: %s zone=%q rrsets=%d status=%d duration=%s request_id=%q",
+			call.Operation, call.Zone, call.RecordSets, call.StatusCode, call.Duration, call.RequestID)
+	}
+}
+
+// NewSlowCallCounter Returns the counter CountSlowCalls increments, labelled by operation.
+// It must be registered by the caller, e.g. with prometheus.MustRegister.
+func NewSlowCallCounter() *prometheus.CounterVec {
+	return prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "powerdns",
+		Name:      "client_slow_calls_total",
+		Help:      "Number of API calls exceeding the slow call threshold.",
+	}, []string{"operation"})
+}
+
+// CountSlowCalls Returns a hook incrementing counter per slow call.
+func CountSlowCalls(counter *prometheus.CounterVec) SlowCallHook {
+	return func(call SlowCall) {
+		counter.WithLabelValues(call.Operation).Inc()
+	}
+}
+
+type recordSetCountKey struct{}
+
+// Returns req carrying the number of rrsets it sends, reported for slow calls
+func withRecordSetCount(req *http.Request, count int) *http.Request {
+	return req.WithContext(context.WithValue(req.Context(), recordSetCountKey{}, count))
+}
+
+// Notifies the slow call hooks when the call of req took longer than the threshold
+func (client *Client) checkSlowCall(req *http.Request, statusCode int, duration time.Duration) {
+	if len(client.slowCallHooks) == 0 || duration <= client.slowCallThreshold {
+		return
+	}
+
+	operation, zone := describeEndpoint(req.URL.Path)
+	count, _ := req.Context().Value(recordSetCountKey{}).(int)
+
+	call := SlowCall{
+		Operation:  req.Method + " " + operation,
+		Zone:       zone,
+		RecordSets: count,
+		StatusCode: statusCode,
+		Duration:   duration,
+		RequestID:  req.Header.Get(RequestIDHeader),
+	}
+	for _, hook := range client.slowCallHooks {
+		hook(call)
+	}
+}
+
+// Returns the resource path of an endpoint with names left out, e.g. "zones/metadata"
+// for /api/v1/servers/localhost/zones/example.com./metadata/ALLOW-AXFR-FROM, and the zone
+func describeEndpoint(endpoint string) (string, string) {
+	segments := strings.Split(strings.Trim(endpoint, "/"), "/")
+	for i, segment := range segments {
+		if segment == "servers" {
+			segments = segments[i:]
+			break
+		}
+	}
+	if len(segments) < 3 || segments[0] != "servers" {
+		return strings.Join(segments, "/"), ""
+	}
+
+	// resource names alternate with the names of their items
+	segments = segments[2:]
+	var resources []string
+	for i := 0; i < len(segments); i += 2 {
+		resources = append(resources, segments[i])
+	}
+
+	zone := ""
+	if segments[0] == "zones" && len(segments) > 1 {
+		zone = segments[1]
+	}
+
+	return strings.Join(resources, "/"), zone
+}
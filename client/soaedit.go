@@ -0,0 +1,47 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// SOA-EDIT-API strategies deciding how PowerDNS bumps the SOA serial after API changes,
+// which in turn triggers NOTIFY to secondaries.
+const (
+	SOAEditAPIDefault         = "DEFAULT"
+	SOAEditAPIIncrease        = "INCREASE"
+	SOAEditAPIEpoch           = "EPOCH"
+	SOAEditAPISOAEdit         = "SOA-EDIT"
+	SOAEditAPISOAEditIncrease = "SOA-EDIT-INCREASE"
+)
+
+// WithSOAEditAPI sets the SOA-EDIT-API strategy of zones created by the client that do
+// not set one themselves.
+func WithSOAEditAPI(value string) Option {
+	return func(client *Client) {
+		client.soaEditAPI = value
+	}
+}
+
+// SetSOAEditAPI Changes the SOA-EDIT-API strategy of an existing zone. An empty value
+// disables serial bumping on API changes.
+func (client *Client) SetSOAEditAPI(zone string, value string) error {
+	reqBody, _ := json.Marshal(map[string]string{"soa_edit_api": value})
+
+	req, err := client.newRequest("PUT", fmt.Sprintf("/servers/localhost/zones/%s", client.rewriteName(zone, true)), reqBody)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 && resp.StatusCode != 200 {
+		return newAPIError(resp, fmt.Sprintf("setting SOA-EDIT-API of zone: %s", zone))
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"strings"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// Number of fields of the contents whose last field is a target host name
+var targetFields = map[types.RecordType]int{
+	types.TypeCNAME: 1, types.TypeDNAME: 1, types.TypeNS: 1, types.TypePTR: 1, types.TypeALIAS: 1,
+	types.TypeMX: 2, types.TypeSRV: 4,
+}
+
+// WithoutTargetNormalization sends CNAME, DNAME, NS, PTR, ALIAS, MX and SRV targets as
+// given. By default the client appends the trailing dot missing from a target, e.g.
+// "mail.example.com" in "10 mail.example.com", since PowerDNS rejects or misinterprets
+// relative targets. Targets are made absolute as is, even with WithRelativeNames.
+func WithoutTargetNormalization() Option {
+	return func(client *Client) {
+		client.rawTargets = true
+	}
+}
+
+// Returns a copy of rrSets with a trailing dot added to targets missing one
+func (client *Client) normalizeTargets(rrSets []types.ResourceRecordSet) []types.ResourceRecordSet {
+	if client.rawTargets {
+		return rrSets
+	}
+
+	normalized := make([]types.ResourceRecordSet, len(rrSets))
+	for i, rrSet := range rrSets {
+		count, ok := targetFields[rrSet.Type]
+		if ok && rrSet.Records != nil {
+			records := make([]types.Record, len(rrSet.Records))
+			for j, record := range rrSet.Records {
+				record.Content = normalizeTarget(record.Content, count)
+				records[j] = record
+			}
+			rrSet.Records = records
+		}
+		normalized[i] = rrSet
+	}
+
+	return normalized
+}
+
+// Appends a dot to the last field of content when it has count fields and the target
+// is relative
+func normalizeTarget(content string, count int) string {
+	fields := strings.Fields(content)
+	if len(fields) != count {
+		return content
+	}
+
+	target := fields[count-1]
+	if strings.HasSuffix(target, ".") || target == "@" {
+		return content
+	}
+	fields[count-1] = target + "."
+
+	return strings.Join(fields, " ")
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+// TransportConfig Connection tuning of the HTTP transport used by the client.
+// Zero values and nil pointers keep the settings of the current transport.
+type TransportConfig struct {
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	ForceAttemptHTTP2   *bool
+	DisableKeepAlives   *bool
+}
+
+// Doer Sends HTTP requests. *http.Client implements it, as do retrying clients such as
+// the standard client of hashicorp/go-retryablehttp, heimdall or a custom HTTP stack.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// WithDoer makes the client send every request through doer, leaving retries and
+// transport behaviour to it. The client itself only retries when WithRateLimitRetry or
+// WithUnavailableRetry are set, and options tuning the HTTP transport have no effect.
+func WithDoer(doer Doer) Option {
+	return func(client *Client) {
+		client.doer = doer
+	}
+}
+
+// WithHTTPClient makes the client send every request through httpClient instead of
+// the pooled client created by NewClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(client *Client) {
+		client.http = httpClient
+	}
+}
+
+// WithTransport replaces the round tripper of the client, e.g. to add tracing or to
+// share a transport and its connection pool between several clients.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(client *Client) {
+		client.http = &http.Client{
+			Transport: transport,
+			Timeout:   client.http.Timeout,
+		}
+	}
+}
+
+// WithTransportConfig tunes connection reuse of the client transport, so bulk operations
+// keep TCP/TLS connections alive between calls instead of paying setup cost each time.
+// The transport and HTTP client are copied, so one passed to WithHTTPClient is left
+// untouched.
+func WithTransportConfig(config TransportConfig) Option {
+	return func(client *Client) {
+		updateTransport(client, func(transport *http.Transport) {
+			if config.MaxIdleConnsPerHost > 0 {
+				transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+			}
+			if config.IdleConnTimeout > 0 {
+				transport.IdleConnTimeout = config.IdleConnTimeout
+			}
+			if config.ForceAttemptHTTP2 != nil {
+				transport.ForceAttemptHTTP2 = *config.ForceAttemptHTTP2
+			}
+			if config.DisableKeepAlives != nil {
+				transport.DisableKeepAlives = *config.DisableKeepAlives
+			}
+		})
+	}
+}
+
+// Applies update to a clone of the transport of the client, when it is an *http.Transport,
+// and installs it on a copy of the HTTP client so callers' clients are never modified
+func updateTransport(client *Client, update func(transport *http.Transport)) {
+	transport, ok := client.http.Transport.(*http.Transport)
+	if !ok {
+		return
+	}
+
+	transport = transport.Clone()
+	update(transport)
+
+	httpClient := *client.http
+	httpClient.Transport = transport
+	client.http = &httpClient
+}
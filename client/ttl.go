@@ -0,0 +1,67 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// WithDefaultTTLFromSOA makes writes of record sets with a zero TTL use the default TTL
+// of the zone, as returned by DefaultTTL, instead of sending a zero TTL.
+func WithDefaultTTLFromSOA() Option {
+	return func(client *Client) {
+		client.defaultTTLFromSOA = true
+	}
+}
+
+// DefaultTTL Returns the default TTL of zone: the minimum field of its SOA record, which
+// PowerDNS also uses as the negative caching TTL.
+func (client *Client) DefaultTTL(zone string) (int, error) {
+	rrSets, err := client.ListRecordsAsRRSet(zone)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, rrSet := range rrSets {
+		if rrSet.Type != types.TypeSOA || len(rrSet.Records) == 0 {
+			continue
+		}
+
+		soa, err := types.ParseSOA(rrSet.Records[0].Content)
+		if err != nil {
+			return 0, err
+		}
+		return soa.Minimum, nil
+	}
+
+	return 0, fmt.Errorf("Error reading default TTL of zone: %s, no SOA record", zone)
+}
+
+// Returns a copy of rrSets where replaced record sets without a TTL get the default TTL
+// of Zone. A zone about to be auto-created gets the default of the SOA template.
+func (client *Client) fillDefaultTTL(zone string, rrSets []types.ResourceRecordSet) ([]types.ResourceRecordSet, error) {
+	if !client.defaultTTLFromSOA {
+		return rrSets, nil
+	}
+
+	filled := make([]types.ResourceRecordSet, len(rrSets))
+	ttl := 0
+	for i, rrSet := range rrSets {
+		if rrSet.TTL == 0 && rrSet.ChangeType == types.ChangeTypeReplace {
+			if ttl == 0 {
+				var err error
+				ttl, err = client.DefaultTTL(zone)
+				if err != nil && client.autoCreateZone != nil && isZoneNotFound(err) {
+					ttl, err = types.DefaultSOAMinimum, nil
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+			rrSet.TTL = ttl
+		}
+		filled[i] = rrSet
+	}
+
+	return filled, nil
+}
@@ -0,0 +1,48 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// ErrCNAMEAtApex is returned when a write would put a CNAME at the zone apex, which DNS
+// forbids next to the SOA and NS records. Use an ALIAS record instead.
+var ErrCNAMEAtApex = errors.New("CNAME records are not allowed at the zone apex, use ALIAS")
+
+// WithLint checks every written record set against the DNS limits of level, see
+// types.Lint, returning an error wrapping types.ErrLint instead of sending it.
+func WithLint(level types.LintLevel) Option {
+	return func(client *Client) {
+		client.lint = level
+	}
+}
+
+// Validates record set changes locally before they are sent to Zone
+func (client *Client) validateRecordSets(zone string, rrSets []types.ResourceRecordSet) error {
+	for _, rrSet := range rrSets {
+		if err := rrSet.ChangeType.Validate(); err != nil {
+			return fmt.Errorf("record set: %s, %w", rrSet.ID(), err)
+		}
+		if err := rrSet.Type.Validate(); err != nil {
+			return fmt.Errorf("record set: %s, %w", rrSet.ID(), err)
+		}
+		if err := types.ValidateTTL(rrSet.TTL); err != nil {
+			return fmt.Errorf("record set: %s, %w", rrSet.ID(), err)
+		}
+		if client.zoneCheck && !types.IsSubdomainOf(rrSet.Name, zone) {
+			return fmt.Errorf("record set: %s, %s is not in zone %s: %w", rrSet.ID(), rrSet.Name, zone, ErrOutOfZone)
+		}
+		if rrSet.Type == types.TypeCNAME && rrSet.ChangeType != types.ChangeTypeDelete && !rrSet.Empty() && types.EqualNames(rrSet.Name, zone) {
+			return fmt.Errorf("record set: %s, %w", rrSet.ID(), ErrCNAMEAtApex)
+		}
+		if rrSet.ChangeType != types.ChangeTypeDelete {
+			if err := types.Lint(&rrSet, client.lint); err != nil {
+				return fmt.Errorf("record set: %s, %w", rrSet.ID(), err)
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,302 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// ListZones Returns all Zones of server, without records
+func (client *Client) ListZones() ([]types.ZoneInfo, error) {
+
+	req, err := client.newRequest("GET", "/servers/localhost/zones", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, newAPIError(resp, "listing zones")
+	}
+
+	var zoneInfos []types.ZoneInfo
+
+	err = json.NewDecoder(resp.Body).Decode(&zoneInfos)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range zoneInfos {
+		client.rewriteZoneInfo(&zoneInfos[i])
+	}
+
+	return zoneInfos, nil
+}
+
+// GetZone Returns Zone including its record sets
+func (client *Client) GetZone(zone string) (*types.ZoneInfo, error) {
+	if client.reads == nil {
+		return client.getZone(zone, nil)
+	}
+
+	result, err, _ := client.reads.Do("zone:"+zone, func() (interface{}, error) {
+		return client.getZone(zone, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result.(*types.ZoneInfo).Clone(), nil
+}
+
+func (client *Client) getZone(zone string, query url.Values) (*types.ZoneInfo, error) {
+	if name := query.Get("rrset_name"); name != "" {
+		query.Set("rrset_name", client.rewriteName(name, true))
+	}
+
+	req, err := client.newRequest("GET", fmt.Sprintf("/servers/localhost/zones/%s", client.rewriteName(zone, true)), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.URL.RawQuery = query.Encode()
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, newAPIError(resp, fmt.Sprintf("reading zone: %s", zone))
+	}
+
+	zoneInfo := new(types.ZoneInfo)
+	err = json.NewDecoder(resp.Body).Decode(zoneInfo)
+	if err != nil {
+		return nil, err
+	}
+	client.rewriteZoneInfo(zoneInfo)
+
+	return zoneInfo, nil
+}
+
+// ZoneExists Checks if Zone exists on the server
+func (client *Client) ZoneExists(zone string) (bool, error) {
+	_, err := client.GetZone(zone)
+	if err == nil {
+		return true, nil
+	}
+
+	if isZoneNotFound(err) {
+		return false, nil
+	}
+
+	return false, err
+}
+
+// CreateZone Creates a new zone in a single POST. Either set zone.Nameservers and let
+// PowerDNS create the SOA and NS records, or provide the initial rrsets in
+// zone.ResourceRecordSets. When soa is not nil an SOA rrset built from it is added.
+func (client *Client) CreateZone(zone types.ZoneInfo, soa *types.SOATemplate) (*types.ZoneInfo, error) {
+	if zone.Kind == "" {
+		zone.Kind = types.KindNative
+	}
+
+	kind, err := client.zoneKind(zone.Kind)
+	if err != nil {
+		return nil, fmt.Errorf("Error creating zone: %s, %w", zone.Name, err)
+	}
+	zone.Kind = kind
+
+	if zone.SOAEditAPI == "" {
+		zone.SOAEditAPI = client.soaEditAPI
+	}
+
+	if zone.Kind == types.KindProducer || zone.Kind == types.KindConsumer {
+		if err := client.Supports(FeatureCatalogZones); err != nil {
+			return nil, err
+		}
+	}
+
+	if soa != nil {
+		zone.ResourceRecordSets = append(zone.ResourceRecordSets, soa.RecordSet(zone.Name))
+	}
+
+	for _, rrSet := range zone.ResourceRecordSets {
+		if err := rrSet.Type.Validate(); err != nil {
+			return nil, fmt.Errorf("Error creating zone: %s, record set: %s, %w", zone.Name, rrSet.ID(), err)
+		}
+	}
+
+	zone.Name = client.rewriteName(zone.Name, true)
+	zone.Records = client.rewriteRecords(zone.Records, "", true)
+	zone.ResourceRecordSets = client.rewriteRecordSets(zone.ResourceRecordSets, true)
+
+	reqBody, _ := json.Marshal(zone)
+
+	req, err := client.newRequest("POST", "/servers/localhost/zones", reqBody)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 201 && resp.StatusCode != 200 {
+		return nil, newAPIError(resp, fmt.Sprintf("creating zone: %s", zone.Name))
+	}
+
+	created := new(types.ZoneInfo)
+	err = json.NewDecoder(resp.Body).Decode(created)
+	if err != nil {
+		return nil, err
+	}
+	client.rewriteZoneInfo(created)
+
+	return created, nil
+}
+
+// Returns kind normalized and named the way the server expects: Primary and Secondary
+// from PowerDNS 4.5, Master and Slave before
+func (client *Client) zoneKind(kind types.ZoneKind) (types.ZoneKind, error) {
+	kind, err := types.ParseZoneKind(string(kind))
+	if err != nil {
+		return "", err
+	}
+
+	if !kind.IsPrimary() && !kind.IsSecondary() {
+		return kind, nil
+	}
+
+	err = client.Supports(FeaturePrimarySecondaryKinds)
+	var unsupported *UnsupportedFeatureError
+	switch {
+	case err == nil:
+		return kind.Normalize(), nil
+	case errors.As(err, &unsupported):
+		return kind.Legacy(), nil
+	}
+
+	return "", err
+}
+
+// ErrZoneNotEmpty is returned by DeleteZone for zones holding records other than the
+// apex SOA and NS records, unless ForceDelete is set.
+var ErrZoneNotEmpty = errors.New("zone still contains records")
+
+// DeleteZoneOptions Controls DeleteZone.
+type DeleteZoneOptions struct {
+	// ForceDelete deletes the zone even when it still contains records.
+	ForceDelete bool
+}
+
+// DeletedZone Summary of what DeleteZone destroyed.
+type DeletedZone struct {
+	Zone       string
+	RecordSets int
+	Records    int
+	// Types counts the deleted record sets of each type.
+	Types map[types.RecordType]int
+}
+
+// DeleteZone Deletes zone and all of its records, which cannot be undone. A zone holding
+// records other than the apex SOA and NS records is refused with ErrZoneNotEmpty unless
+// ForceDelete is set. It returns a summary of the deleted data.
+func (client *Client) DeleteZone(zone string, opts DeleteZoneOptions) (*DeletedZone, error) {
+	zoneInfo, err := client.GetZone(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &DeletedZone{Zone: zone, Types: make(map[types.RecordType]int)}
+	userRecords := 0
+	for _, rrSet := range zoneInfo.ResourceRecordSets {
+		summary.RecordSets++
+		summary.Records += len(rrSet.Records)
+		summary.Types[rrSet.Type]++
+
+		apex := types.EqualNames(rrSet.Name, zoneInfo.Name)
+		if !(apex && (rrSet.Type == types.TypeSOA || rrSet.Type == types.TypeNS)) {
+			userRecords++
+		}
+	}
+
+	if userRecords > 0 && !opts.ForceDelete {
+		return nil, fmt.Errorf("Error deleting zone: %s, %d record sets besides SOA and NS: %w", zone, userRecords, ErrZoneNotEmpty)
+	}
+
+	req, err := client.newRequest("DELETE", fmt.Sprintf("/servers/localhost/zones/%s", client.rewriteName(zone, true)), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 204 && resp.StatusCode != 200 {
+		return nil, newAPIError(resp, fmt.Sprintf("deleting zone: %s", zone))
+	}
+
+	return summary, nil
+}
+
+// RetrieveZone Asks the server to retrieve a secondary zone from its primaries now,
+// instead of waiting for the next refresh or NOTIFY.
+func (client *Client) RetrieveZone(zone string) error {
+	req, err := client.newRequest("PUT", fmt.Sprintf("/servers/localhost/zones/%s/axfr-retrieve", client.rewriteName(zone, true)), nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := client.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 && resp.StatusCode != 204 {
+		return newAPIError(resp, fmt.Sprintf("retrieving zone: %s", zone))
+	}
+
+	return nil
+}
+
+// WithAutoCreateZone makes record writes to a zone that does not exist create the zone
+// first, using template for its kind, nameservers and other settings.
+func WithAutoCreateZone(template types.ZoneInfo) Option {
+	return func(client *Client) {
+		client.autoCreateZone = &template
+	}
+}
+
+func (client *Client) createZoneFromTemplate(zone string) error {
+	template := *client.autoCreateZone
+	template.Name = zone
+
+	_, err := client.CreateZone(template, nil)
+	return err
+}
+
+// PowerDNS answers 404 for unknown zones, older versions 422 "Could not find domain"
+func isZoneNotFound(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+
+	return apiErr.StatusCode == 404 || apiErr.StatusCode == 422
+}
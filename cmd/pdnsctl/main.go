@@ -0,0 +1,90 @@
+// Command pdnsctl manages PowerDNS zones from declarative zone spec files.
+//
+//	pdnsctl plan  [-color] zone.yaml   show the changes, exit 2 when there are any
+//	pdnsctl apply [-color] zone.yaml   apply the changes
+//
+// The server and API key are read from PDNS_SERVER_URL and PDNS_API_KEY, or from the
+// -server and -api-key flags. Exit codes follow terraform plan -detailed-exitcode:
+// 0 when the zone matches the spec, 1 on errors and 2 when plan found changes.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/ops"
+)
+
+// Exit codes
+const (
+	exitOK      = 0
+	exitError   = 1
+	exitChanges = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+func run(args []string) int {
+	if len(args) == 0 || (args[0] != "plan" && args[0] != "apply") {
+		fmt.Fprintln(os.Stderr, "Usage: pdnsctl plan|apply [flags] <zone spec file>")
+		return exitError
+	}
+	command := args[0]
+
+	flags := flag.NewFlagSet("pdnsctl "+command, flag.ContinueOnError)
+	server := flags.String("server", os.Getenv("PDNS_SERVER_URL"), "PowerDNS API URL")
+	apiKey := flags.String("api-key", os.Getenv("PDNS_API_KEY"), "PowerDNS API key")
+	color := flags.Bool("color", false, "colorize the plan")
+	if err := flags.Parse(args[1:]); err != nil {
+		return exitError
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintf(os.Stderr, "Usage: pdnsctl %s [flags] <zone spec file>\n", command)
+		return exitError
+	}
+
+	spec, err := ops.LoadZoneSpec(flags.Arg(0))
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	c, err := client.NewClient(*server, *apiKey)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	plan, err := ops.PlanZone(c, spec)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	plan.Color = *color
+
+	if err = plan.Render(os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+
+	if command == "plan" {
+		if plan.Empty() {
+			return exitOK
+		}
+		return exitChanges
+	}
+
+	if err = plan.Apply(c); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return exitError
+	}
+	if !plan.Empty() {
+		fmt.Printf("Applied %d changes to zone %s\n", len(plan.Changes), plan.Zone)
+	}
+
+	return exitOK
+}
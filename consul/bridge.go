@@ -0,0 +1,251 @@
+// Package consul materializes services of the Consul catalog as records in a PowerDNS
+// zone, talking to the Consul HTTP API directly.
+package consul
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/ops"
+	"github.com/dmportella/powerdns/types"
+)
+
+// DefaultTTL TTL of the records created for Consul services.
+const DefaultTTL = 30
+
+// DefaultWait bounds a single Consul blocking query.
+const DefaultWait = 5 * time.Minute
+
+// Bridge Publishes the passing instances of every Consul service in Zone:
+//
+//	<service>.<zone>               A/AAAA  addresses of the instances
+//	<node>.<service>.<zone>        A/AAAA  address of the instance on node
+//	_<service>._tcp.<zone>         SRV     port and host name of every instance
+//
+// Records are tagged with OwnerID; owned records of services that disappeared are pruned.
+type Bridge struct {
+	Client  client.RecordAPI
+	Zone    string
+	OwnerID string
+	TTL     int
+
+	// ConsulAddr is the Consul HTTP API address, e.g. "http://127.0.0.1:8500".
+	ConsulAddr string
+	// Token is sent as X-Consul-Token when set.
+	Token string
+	HTTP  *http.Client
+	// Wait bounds blocking queries; changes are picked up immediately, but the bridge also
+	// resyncs at least this often.
+	Wait time.Duration
+
+	// OnError, when set, is called when a sync fails.
+	OnError func(err error)
+}
+
+type healthEntry struct {
+	Node struct {
+		Node    string `json:"Node"`
+		Address string `json:"Address"`
+	} `json:"Node"`
+	Service struct {
+		ID      string `json:"ID"`
+		Service string `json:"Service"`
+		Address string `json:"Address"`
+		Port    int    `json:"Port"`
+	} `json:"Service"`
+}
+
+// Run Syncs the zone and then again whenever the health of the catalog changes, until
+// ctx is done.
+func (bridge *Bridge) Run(ctx context.Context) error {
+	index := ""
+	for {
+		if err := bridge.Sync(ctx); err != nil && bridge.OnError != nil {
+			bridge.OnError(err)
+		}
+
+		next, err := bridge.waitForChange(ctx, index)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			if bridge.OnError != nil {
+				bridge.OnError(err)
+			}
+			// back off before retrying an unreachable agent
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(5 * time.Second):
+			}
+		}
+		index = next
+	}
+}
+
+// Sync Publishes the current state of the catalog in the zone.
+func (bridge *Bridge) Sync(ctx context.Context) error {
+	var services map[string][]string
+	if _, err := bridge.get(ctx, "/v1/catalog/services", nil, &services); err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(services))
+	for name := range services {
+		if name != "consul" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var rrSets []types.ResourceRecordSet
+	for _, name := range names {
+		var entries []healthEntry
+		if _, err := bridge.get(ctx, "/v1/health/service/"+url.PathEscape(name), url.Values{"passing": {"true"}}, &entries); err != nil {
+			return err
+		}
+		rrSets = append(rrSets, bridge.recordSets(name, entries)...)
+	}
+
+	keep := make([]ops.RecordKey, 0, len(rrSets))
+	for _, rrSet := range rrSets {
+		if _, err := ops.EnsureRecordSet(bridge.Client, bridge.Zone, rrSet, bridge.OwnerID); err != nil {
+			return err
+		}
+		keep = append(keep, ops.Key(&rrSet))
+	}
+
+	_, err := ops.PruneRecords(bridge.Client, bridge.Zone, bridge.OwnerID, keep)
+	return err
+}
+
+// Returns the rrsets publishing the instances of service
+func (bridge *Bridge) recordSets(service string, entries []healthEntry) []types.ResourceRecordSet {
+	ttl := bridge.TTL
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+
+	zone := strings.TrimSuffix(bridge.Zone, ".") + "."
+	serviceName := label(service) + "." + zone
+
+	byName := make(map[ops.RecordKey]*types.ResourceRecordSet)
+	var order []ops.RecordKey
+	add := func(name string, tpe types.RecordType, content string) {
+		key := ops.RecordKey{Name: name, Type: tpe}
+		rrSet, ok := byName[key]
+		if !ok {
+			rrSet = &types.ResourceRecordSet{Name: name, Type: tpe, TTL: ttl}
+			byName[key] = rrSet
+			order = append(order, key)
+		}
+		for _, record := range rrSet.Records {
+			if record.Content == content {
+				return
+			}
+		}
+		rrSet.Records = append(rrSet.Records, types.Record{Content: content})
+	}
+
+	for _, entry := range entries {
+		address := entry.Service.Address
+		if address == "" {
+			address = entry.Node.Address
+		}
+		ip := net.ParseIP(address)
+		if ip == nil {
+			continue
+		}
+
+		tpe := types.TypeAAAA
+		if ip.To4() != nil {
+			tpe = types.TypeA
+		}
+		host := label(entry.Node.Node) + "." + serviceName
+
+		add(serviceName, tpe, ip.String())
+		add(host, tpe, ip.String())
+		if entry.Service.Port > 0 {
+			srv := types.SRV{Priority: 1, Weight: 1, Port: entry.Service.Port, Target: host}
+			add("_"+label(service)+"._tcp."+zone, types.TypeSRV, srv.String())
+		}
+	}
+
+	rrSets := make([]types.ResourceRecordSet, 0, len(order))
+	for _, key := range order {
+		rrSets = append(rrSets, *byName[key])
+	}
+
+	return rrSets
+}
+
+// Blocks until the health state of the catalog changes past index, or Wait elapses
+func (bridge *Bridge) waitForChange(ctx context.Context, index string) (string, error) {
+	wait := bridge.Wait
+	if wait == 0 {
+		wait = DefaultWait
+	}
+
+	query := url.Values{"wait": {strconv.Itoa(int(wait.Seconds())) + "s"}}
+	if index != "" {
+		query.Set("index", index)
+	}
+
+	var checks []json.RawMessage
+	return bridge.get(ctx, "/v1/health/state/any", query, &checks)
+}
+
+// Sends a GET to the Consul API, decoding the response into out and returning X-Consul-Index
+func (bridge *Bridge) get(ctx context.Context, endpoint string, query url.Values, out interface{}) (string, error) {
+	req, err := http.NewRequest("GET", strings.TrimSuffix(bridge.ConsulAddr, "/")+endpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return "", err
+	}
+	req = req.WithContext(ctx)
+	if bridge.Token != "" {
+		req.Header.Set("X-Consul-Token", bridge.Token)
+	}
+
+	httpClient := bridge.HTTP
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("Error querying Consul: %s, %w", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("Error querying Consul: %s, status: %d", endpoint, resp.StatusCode)
+	}
+
+	if err = json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return "", fmt.Errorf("Error querying Consul: %s, %w", endpoint, err)
+	}
+
+	return resp.Header.Get("X-Consul-Index"), nil
+}
+
+// Turns a Consul service or node name into a DNS label
+func label(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', '0' <= r && r <= '9', r == '-':
+			return r
+		case 'A' <= r && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, name)
+}
@@ -0,0 +1,87 @@
+// Package controllers provides the DNSRecord custom resource and a reconciler applying
+// it to PowerDNS, to run record management as a Kubernetes operator.
+//
+// The types mirror the CustomResourceDefinition in dnsrecord-crd.yaml and marshal to the
+// JSON the Kubernetes API serves, without depending on the Kubernetes modules; wire the
+// Reconciler into controller-runtime, client-go informers or any other event source.
+package controllers
+
+import (
+	"time"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// Group, version and kind of the DNSRecord custom resource.
+const (
+	Group      = "dns.dmportella.github.io"
+	Version    = "v1alpha1"
+	Kind       = "DNSRecord"
+	APIVersion = Group + "/" + Version
+)
+
+// ObjectMeta Subset of the Kubernetes object metadata used by the reconciler.
+type ObjectMeta struct {
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace,omitempty"`
+	UID               string            `json:"uid,omitempty"`
+	Generation        int64             `json:"generation,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	DeletionTimestamp *time.Time        `json:"deletionTimestamp,omitempty"`
+	Finalizers        []string          `json:"finalizers,omitempty"`
+}
+
+// DNSRecord Custom resource describing one rrset in a PowerDNS zone.
+type DNSRecord struct {
+	APIVersion string          `json:"apiVersion"`
+	Kind       string          `json:"kind"`
+	Metadata   ObjectMeta      `json:"metadata"`
+	Spec       DNSRecordSpec   `json:"spec"`
+	Status     DNSRecordStatus `json:"status,omitempty"`
+}
+
+// DNSRecordSpec Desired rrset. Name is relative to Zone unless it ends with a dot.
+type DNSRecordSpec struct {
+	Zone     string           `json:"zone"`
+	Name     string           `json:"name"`
+	Type     types.RecordType `json:"type"`
+	TTL      int              `json:"ttl"`
+	Contents []string         `json:"contents"`
+}
+
+// DNSRecordStatus Observed state of a DNSRecord.
+type DNSRecordStatus struct {
+	Ready              bool   `json:"ready"`
+	Message            string `json:"message,omitempty"`
+	ObservedGeneration int64  `json:"observedGeneration,omitempty"`
+}
+
+// Finalizer set on DNSRecords so their rrset is deleted before the resource is.
+const Finalizer = Group + "/rrset"
+
+// RecordSet Returns the rrset described by the record, with a fully qualified name.
+func (record *DNSRecord) RecordSet() types.ResourceRecordSet {
+	zone := record.Spec.Zone
+	if zone != "" && zone[len(zone)-1] != '.' {
+		zone += "."
+	}
+
+	name := record.Spec.Name
+	switch {
+	case name == "" || name == "@":
+		name = zone
+	case name[len(name)-1] != '.':
+		name += "." + zone
+	}
+
+	rrSet := types.ResourceRecordSet{
+		Name: name,
+		Type: record.Spec.Type,
+		TTL:  record.Spec.TTL,
+	}
+	for _, content := range record.Spec.Contents {
+		rrSet.Records = append(rrSet.Records, types.Record{Content: content})
+	}
+
+	return rrSet
+}
@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"fmt"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/ops"
+)
+
+// Reconciler Applies DNSRecords to PowerDNS. Every rrset it writes is tagged with
+// OwnerID, so rrsets managed by hand or by other controllers are never modified.
+type Reconciler struct {
+	Client  client.RecordAPI
+	OwnerID string
+}
+
+// Reconcile Ensures the rrset of record exists as specified, or deletes it when the
+// record is being deleted, and updates the record status. The caller persists the status
+// and removes the Finalizer once Reconcile succeeded for a deleted record.
+func (r *Reconciler) Reconcile(record *DNSRecord) error {
+	rrSet := record.RecordSet()
+
+	var err error
+	if record.Metadata.DeletionTimestamp != nil {
+		err = r.delete(record.Spec.Zone, rrSet.Name, string(rrSet.Type))
+	} else {
+		_, err = ops.EnsureRecordSet(r.Client, record.Spec.Zone, rrSet, r.OwnerID)
+	}
+
+	record.Status.ObservedGeneration = record.Metadata.Generation
+	record.Status.Ready = err == nil
+	record.Status.Message = ""
+	if err != nil {
+		record.Status.Message = err.Error()
+		return fmt.Errorf("Error reconciling DNSRecord: %s/%s, %w", record.Metadata.Namespace, record.Metadata.Name, err)
+	}
+
+	return nil
+}
+
+// Prune Deletes the rrsets owned by the reconciler in zone that no DNSRecord describes
+// any more, e.g. after the operator missed deletions while it was down.
+func (r *Reconciler) Prune(zone string, records []DNSRecord) ([]ops.RecordKey, error) {
+	keep := make([]ops.RecordKey, 0, len(records))
+	for i := range records {
+		if records[i].Metadata.DeletionTimestamp == nil {
+			rrSet := records[i].RecordSet()
+			keep = append(keep, ops.Key(&rrSet))
+		}
+	}
+
+	return ops.PruneRecords(r.Client, zone, r.OwnerID, keep)
+}
+
+// Deletes the rrset of name and type unless it is missing or not owned by the reconciler
+func (r *Reconciler) delete(zone string, name string, tpe string) error {
+	current, err := r.Client.GetRecordSet(zone, name, tpe)
+	if err != nil || current == nil || ops.Owner(current) != r.OwnerID {
+		return err
+	}
+
+	return r.Client.DeleteRecordSet(zone, name, tpe)
+}
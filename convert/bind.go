@@ -0,0 +1,47 @@
+package convert
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/dmportella/powerdns/types"
+	"github.com/miekg/dns"
+)
+
+// ReadBIND Reads a zone file in BIND format, such as the DNS export of Cloudflare, into
+// rrsets. origin is used for relative names when the file has no $ORIGIN.
+func ReadBIND(r io.Reader, origin string) ([]types.ResourceRecordSet, error) {
+	parser := dns.NewZoneParser(r, dns.Fqdn(origin), "")
+
+	var rrSets []types.ResourceRecordSet
+	index := make(map[string]int)
+	for rr, ok := parser.Next(); ok; rr, ok = parser.Next() {
+		header := rr.Header()
+		tpe := types.RecordType(dns.TypeToString[header.Rrtype])
+		content := strings.TrimSpace(strings.TrimPrefix(rr.String(), header.String()))
+
+		key := strings.ToLower(header.Name) + types.IDSeparator + string(tpe)
+		i, ok := index[key]
+		if !ok {
+			i = len(rrSets)
+			index[key] = i
+			rrSets = append(rrSets, types.ResourceRecordSet{
+				Name: strings.ToLower(header.Name),
+				Type: tpe,
+				TTL:  int(header.Ttl),
+			})
+		}
+		// RFC 2181 requires a single TTL per rrset; keep the lowest one
+		if int(header.Ttl) < rrSets[i].TTL {
+			rrSets[i].TTL = int(header.Ttl)
+		}
+		rrSets[i].Records = append(rrSets[i].Records, types.Record{Content: content})
+	}
+
+	if err := parser.Err(); err != nil {
+		return nil, fmt.Errorf("Error reading zone file: %w", err)
+	}
+
+	return rrSets, nil
+}
@@ -0,0 +1,36 @@
+package convert
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// WriteCSV Writes one row per record with the columns name, type, ttl, content and
+// disabled, preceded by a header row, e.g. for audit spreadsheets.
+func WriteCSV(w io.Writer, rrSets []types.ResourceRecordSet) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write([]string{"name", "type", "ttl", "content", "disabled"}); err != nil {
+		return err
+	}
+
+	for _, rrSet := range rrSets {
+		for _, record := range rrSet.Flatten() {
+			err := writer.Write([]string{
+				record.Name,
+				string(record.Type),
+				strconv.Itoa(record.TTL),
+				record.Content,
+				strconv.FormatBool(record.Disabled),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
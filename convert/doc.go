@@ -0,0 +1,3 @@
+// Package convert translates zones between PowerDNS rrsets and the formats of other DNS
+// providers, to migrate onto or off PowerDNS.
+package convert
@@ -0,0 +1,26 @@
+package convert
+
+import (
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// Import Replaces the given rrsets in zone with PatchRecordSets. The SOA and apex NS
+// rrsets are skipped, since they describe the previous provider rather than PowerDNS.
+func Import(c client.RecordAPI, zone string, rrSets []types.ResourceRecordSet) error {
+	changes := make([]types.ResourceRecordSet, 0, len(rrSets))
+	for _, rrSet := range rrSets {
+		if rrSet.Type == types.TypeSOA || (rrSet.Type == types.TypeNS && types.EqualNames(rrSet.Name, zone)) {
+			continue
+		}
+
+		rrSet.ChangeType = types.ChangeTypeReplace
+		changes = append(changes, rrSet)
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return c.PatchRecordSets(zone, changes)
+}
@@ -0,0 +1,346 @@
+package convert
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dmportella/powerdns/types"
+	"gopkg.in/yaml.v3"
+)
+
+// octoDNS record as found under a name of an octoDNS zone file
+type octoRecord struct {
+	Type   string      `yaml:"type"`
+	TTL    int         `yaml:"ttl,omitempty"`
+	Value  interface{} `yaml:"value,omitempty"`
+	Values interface{} `yaml:"values,omitempty"`
+}
+
+// A name holds either a single record or a list of records
+type octoRecords []octoRecord
+
+func (records *octoRecords) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.SequenceNode {
+		return node.Decode((*[]octoRecord)(records))
+	}
+
+	var record octoRecord
+	if err := node.Decode(&record); err != nil {
+		return err
+	}
+	*records = octoRecords{record}
+	return nil
+}
+
+// Structured values of octoDNS records, fields in octoDNS order
+type octoMX struct {
+	Preference int    `yaml:"preference"`
+	Exchange   string `yaml:"exchange"`
+}
+
+type octoSRV struct {
+	Priority int    `yaml:"priority"`
+	Weight   int    `yaml:"weight"`
+	Port     int    `yaml:"port"`
+	Target   string `yaml:"target"`
+}
+
+type octoCAA struct {
+	Flags int    `yaml:"flags"`
+	Tag   string `yaml:"tag"`
+	Value string `yaml:"value"`
+}
+
+type octoSSHFP struct {
+	Algorithm       int    `yaml:"algorithm"`
+	FingerprintType int    `yaml:"fingerprint_type"`
+	Fingerprint     string `yaml:"fingerprint"`
+}
+
+type octoTLSA struct {
+	CertificateUsage           int    `yaml:"certificate_usage"`
+	Selector                   int    `yaml:"selector"`
+	MatchingType               int    `yaml:"matching_type"`
+	CertificateAssociationData string `yaml:"certificate_association_data"`
+}
+
+type octoDS struct {
+	KeyTag     int    `yaml:"key_tag"`
+	Algorithm  int    `yaml:"algorithm"`
+	DigestType int    `yaml:"digest_type"`
+	Digest     string `yaml:"digest"`
+}
+
+// DefaultOctoDNSTTL TTL octoDNS assumes for records without one.
+const DefaultOctoDNSTTL = 3600
+
+// ReadOctoDNS Reads an octoDNS YAML zone config for zone into rrsets. MX, SRV, CAA,
+// SSHFP, TLSA and DS values are read from their octoDNS structures, TXT and SPF values
+// are unescaped and quoted, other values are used as record content as is.
+func ReadOctoDNS(r io.Reader, zone string) ([]types.ResourceRecordSet, error) {
+	config := make(map[string]octoRecords)
+	if err := yaml.NewDecoder(r).Decode(&config); err != nil && err != io.EOF {
+		return nil, fmt.Errorf("Error reading octoDNS config: %w", err)
+	}
+
+	var rrSets []types.ResourceRecordSet
+	for name, records := range config {
+		for _, record := range records {
+			rrSet := types.ResourceRecordSet{
+				Name: absoluteName(name, zone),
+				Type: types.RecordType(strings.ToUpper(record.Type)),
+				TTL:  record.TTL,
+			}
+			if rrSet.TTL == 0 {
+				rrSet.TTL = DefaultOctoDNSTTL
+			}
+			if err := rrSet.Type.Validate(); err != nil {
+				return nil, fmt.Errorf("Error reading octoDNS config: %s, %w", rrSet.Name, err)
+			}
+
+			values := record.Values
+			if values == nil {
+				values = record.Value
+			}
+			list, ok := values.([]interface{})
+			if !ok {
+				list = []interface{}{values}
+			}
+
+			for _, value := range list {
+				content, err := octoContent(rrSet.Type, value)
+				if err != nil {
+					return nil, fmt.Errorf("Error reading octoDNS config: %s, %w", rrSet.ID(), err)
+				}
+				rrSet.Records = append(rrSet.Records, types.Record{Content: content})
+			}
+
+			rrSets = append(rrSets, rrSet)
+		}
+	}
+	types.SortRecordSets(rrSets)
+
+	return rrSets, nil
+}
+
+// WriteOctoDNS Writes the rrsets of zone as an octoDNS YAML zone config. The SOA rrset,
+// which octoDNS does not manage, and disabled records are skipped.
+func WriteOctoDNS(w io.Writer, zone string, rrSets []types.ResourceRecordSet) error {
+	config := make(map[string][]octoRecord)
+	for _, rrSet := range rrSets {
+		if rrSet.Type == types.TypeSOA {
+			continue
+		}
+
+		var values []interface{}
+		for _, record := range rrSet.Records {
+			if record.Disabled {
+				continue
+			}
+			value, err := octoValue(rrSet.Type, record.Content)
+			if err != nil {
+				return fmt.Errorf("Error exporting record set: %s, %w", rrSet.ID(), err)
+			}
+			values = append(values, value)
+		}
+		if len(values) == 0 {
+			continue
+		}
+
+		record := octoRecord{Type: string(rrSet.Type), TTL: rrSet.TTL}
+		if len(values) == 1 {
+			record.Value = values[0]
+		} else {
+			record.Values = values
+		}
+
+		name := relativeName(rrSet.Name, zone)
+		config[name] = append(config[name], record)
+	}
+
+	document := make(map[string]interface{}, len(config))
+	for name, records := range config {
+		if len(records) == 1 {
+			document[name] = records[0]
+		} else {
+			document[name] = records
+		}
+	}
+
+	if _, err := io.WriteString(w, "---\n"); err != nil {
+		return err
+	}
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(document); err != nil {
+		return err
+	}
+
+	return encoder.Close()
+}
+
+// Converts an octoDNS value to record content
+func octoContent(tpe types.RecordType, value interface{}) (string, error) {
+	if value == nil {
+		return "", fmt.Errorf("missing value")
+	}
+
+	var err error
+	switch tpe {
+	case types.TypeMX:
+		var mx octoMX
+		if err = remarshal(value, &mx); err == nil {
+			return fmt.Sprintf("%d %s", mx.Preference, mx.Exchange), nil
+		}
+	case types.TypeSRV:
+		var srv octoSRV
+		if err = remarshal(value, &srv); err == nil {
+			return types.SRV{Priority: srv.Priority, Weight: srv.Weight, Port: srv.Port, Target: srv.Target}.String(), nil
+		}
+	case types.TypeCAA:
+		var caa octoCAA
+		if err = remarshal(value, &caa); err == nil {
+			return types.CAA{Flag: caa.Flags, Tag: caa.Tag, Value: caa.Value}.String(), nil
+		}
+	case types.TypeSSHFP:
+		var sshfp octoSSHFP
+		if err = remarshal(value, &sshfp); err == nil {
+			return fmt.Sprintf("%d %d %s", sshfp.Algorithm, sshfp.FingerprintType, sshfp.Fingerprint), nil
+		}
+	case types.TypeTLSA:
+		var tlsa octoTLSA
+		if err = remarshal(value, &tlsa); err == nil {
+			return fmt.Sprintf("%d %d %d %s", tlsa.CertificateUsage, tlsa.Selector, tlsa.MatchingType, tlsa.CertificateAssociationData), nil
+		}
+	case types.TypeDS:
+		var ds octoDS
+		if err = remarshal(value, &ds); err == nil {
+			return fmt.Sprintf("%d %d %d %s", ds.KeyTag, ds.Algorithm, ds.DigestType, ds.Digest), nil
+		}
+	case types.TypeTXT, types.TypeSPF:
+		s, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("invalid value: %v", value)
+		}
+		return types.QuoteTXT(strings.Replace(s, `\;`, ";", -1)), nil
+	default:
+		switch v := value.(type) {
+		case string:
+			return v, nil
+		case int:
+			return strconv.Itoa(v), nil
+		}
+		return "", fmt.Errorf("invalid value: %v", value)
+	}
+
+	return "", fmt.Errorf("invalid value: %v, %w", value, err)
+}
+
+// Converts record content to an octoDNS value
+func octoValue(tpe types.RecordType, content string) (interface{}, error) {
+	fields := strings.Fields(content)
+	invalid := fmt.Errorf("invalid content: %q", content)
+
+	switch tpe {
+	case types.TypeMX:
+		if len(fields) != 2 {
+			return nil, invalid
+		}
+		preference, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, invalid
+		}
+		return octoMX{Preference: preference, Exchange: fields[1]}, nil
+	case types.TypeSRV:
+		srv, err := types.ParseSRV(content)
+		if err != nil {
+			return nil, err
+		}
+		return octoSRV{Priority: srv.Priority, Weight: srv.Weight, Port: srv.Port, Target: srv.Target}, nil
+	case types.TypeCAA:
+		caa, err := types.ParseCAA(content)
+		if err != nil {
+			return nil, err
+		}
+		return octoCAA{Flags: caa.Flag, Tag: caa.Tag, Value: caa.Value}, nil
+	case types.TypeSSHFP:
+		numbers, err := atois(fields, 2, 3)
+		if err != nil {
+			return nil, invalid
+		}
+		return octoSSHFP{Algorithm: numbers[0], FingerprintType: numbers[1], Fingerprint: fields[2]}, nil
+	case types.TypeTLSA:
+		numbers, err := atois(fields, 3, 4)
+		if err != nil {
+			return nil, invalid
+		}
+		return octoTLSA{CertificateUsage: numbers[0], Selector: numbers[1], MatchingType: numbers[2], CertificateAssociationData: fields[3]}, nil
+	case types.TypeDS:
+		numbers, err := atois(fields, 3, 4)
+		if err != nil {
+			return nil, invalid
+		}
+		return octoDS{KeyTag: numbers[0], Algorithm: numbers[1], DigestType: numbers[2], Digest: fields[3]}, nil
+	case types.TypeTXT, types.TypeSPF:
+		value, err := types.UnquoteTXT(content)
+		if err != nil {
+			return nil, err
+		}
+		return strings.Replace(value, ";", `\;`, -1), nil
+	}
+
+	return content, nil
+}
+
+// Parses the first count of fields as integers, requiring exactly total fields
+func atois(fields []string, count int, total int) ([]int, error) {
+	if len(fields) != total {
+		return nil, fmt.Errorf("expected %d fields", total)
+	}
+
+	numbers := make([]int, count)
+	for i := range numbers {
+		number, err := strconv.Atoi(fields[i])
+		if err != nil {
+			return nil, err
+		}
+		numbers[i] = number
+	}
+
+	return numbers, nil
+}
+
+// Decodes a generic YAML value into a typed structure
+func remarshal(value interface{}, out interface{}) error {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return yaml.Unmarshal(data, out)
+}
+
+// Returns the name relative to zone as used in octoDNS configs, "" for the apex
+func relativeName(name string, zone string) string {
+	if relative, ok := types.SplitRelative(name, zone); ok {
+		return relative
+	}
+
+	return strings.TrimSuffix(name, ".") + "."
+}
+
+// Returns the absolute name of a name relative to zone, "" being the apex
+func absoluteName(name string, zone string) string {
+	zone = strings.TrimSuffix(zone, ".") + "."
+
+	if name == "" {
+		return zone
+	}
+	if strings.HasSuffix(name, ".") {
+		return name
+	}
+
+	return name + "." + zone
+}
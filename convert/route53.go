@@ -0,0 +1,164 @@
+package convert
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// Route53RecordSet Resource record set in the JSON format of the Route53 API, as printed
+// by "aws route53 list-resource-record-sets".
+type Route53RecordSet struct {
+	Name            string                  `json:"Name"`
+	Type            string                  `json:"Type"`
+	TTL             int                     `json:"TTL,omitempty"`
+	ResourceRecords []Route53ResourceRecord `json:"ResourceRecords,omitempty"`
+	AliasTarget     *Route53AliasTarget     `json:"AliasTarget,omitempty"`
+}
+
+// Route53ResourceRecord Single record value of a Route53 record set.
+type Route53ResourceRecord struct {
+	Value string `json:"Value"`
+}
+
+// Route53AliasTarget Target of a Route53 alias record set.
+type Route53AliasTarget struct {
+	HostedZoneID         string `json:"HostedZoneId"`
+	DNSName              string `json:"DNSName"`
+	EvaluateTargetHealth bool   `json:"EvaluateTargetHealth"`
+}
+
+type route53List struct {
+	ResourceRecordSets []Route53RecordSet `json:"ResourceRecordSets"`
+}
+
+// DefaultAliasTTL TTL given to ALIAS rrsets converted from Route53 alias records, which
+// have no TTL of their own.
+const DefaultAliasTTL = 300
+
+// ReadRoute53 Reads a Route53 record set export into rrsets. Alias record sets become
+// PowerDNS ALIAS rrsets pointing at the alias target, which requires ALIAS expansion on
+// the server; only A and AAAA aliases can be converted this way.
+func ReadRoute53(r io.Reader) ([]types.ResourceRecordSet, error) {
+	list := new(route53List)
+	if err := json.NewDecoder(r).Decode(list); err != nil {
+		return nil, fmt.Errorf("Error reading Route53 export: %w", err)
+	}
+
+	rrSets := make([]types.ResourceRecordSet, 0, len(list.ResourceRecordSets))
+	aliases := make(map[string]bool)
+	for _, set := range list.ResourceRecordSets {
+		name := unescapeRoute53Name(set.Name)
+
+		if set.AliasTarget != nil {
+			if set.Type != "A" && set.Type != "AAAA" {
+				return nil, fmt.Errorf("Error reading Route53 export: %s %s, unsupported alias type", name, set.Type)
+			}
+			// A and AAAA aliases of the same name collapse into one ALIAS rrset
+			if aliases[name] {
+				continue
+			}
+			aliases[name] = true
+
+			rrSets = append(rrSets, types.ResourceRecordSet{
+				Name:    name,
+				Type:    types.TypeALIAS,
+				TTL:     DefaultAliasTTL,
+				Records: []types.Record{{Content: set.AliasTarget.DNSName}},
+			})
+			continue
+		}
+
+		rrSet := types.ResourceRecordSet{
+			Name: name,
+			Type: types.RecordType(set.Type),
+			TTL:  set.TTL,
+		}
+		if err := rrSet.Type.Validate(); err != nil {
+			return nil, fmt.Errorf("Error reading Route53 export: %s, %w", name, err)
+		}
+		for _, record := range set.ResourceRecords {
+			rrSet.Records = append(rrSet.Records, types.Record{Content: record.Value})
+		}
+
+		rrSets = append(rrSets, rrSet)
+	}
+
+	return rrSets, nil
+}
+
+// Route53 escapes characters such as "*" in names as \ooo octal sequences
+func unescapeRoute53Name(name string) string {
+	if !strings.Contains(name, `\`) {
+		return name
+	}
+
+	var buf strings.Builder
+	for i := 0; i < len(name); i++ {
+		if name[i] == '\\' && i+3 < len(name) {
+			if code, err := strconv.ParseUint(name[i+1:i+4], 8, 8); err == nil {
+				buf.WriteByte(byte(code))
+				i += 3
+				continue
+			}
+		}
+		buf.WriteByte(name[i])
+	}
+
+	return buf.String()
+}
+
+// Route53Change Change of a Route53 ChangeResourceRecordSets batch.
+type Route53Change struct {
+	Action            string           `json:"Action"`
+	ResourceRecordSet Route53RecordSet `json:"ResourceRecordSet"`
+}
+
+// Route53ChangeBatch Request body of "aws route53 change-resource-record-sets --change-batch".
+type Route53ChangeBatch struct {
+	Comment string          `json:"Comment,omitempty"`
+	Changes []Route53Change `json:"Changes"`
+}
+
+// WriteRoute53 Writes the rrsets of zone as a Route53 change batch upserting every rrset.
+// The SOA and apex NS rrsets are skipped since Route53 manages its own, as are disabled
+// records. ALIAS rrsets have no Route53 equivalent and are rejected.
+func WriteRoute53(w io.Writer, zone string, rrSets []types.ResourceRecordSet) error {
+	batch := Route53ChangeBatch{
+		Comment: "Exported from PowerDNS zone " + zone,
+		Changes: []Route53Change{},
+	}
+
+	for _, rrSet := range rrSets {
+		if rrSet.Type == types.TypeSOA || (rrSet.Type == types.TypeNS && types.EqualNames(rrSet.Name, zone)) {
+			continue
+		}
+		if rrSet.Type == types.TypeALIAS {
+			return fmt.Errorf("Error exporting record set: %s, ALIAS records cannot be exported to Route53", rrSet.ID())
+		}
+
+		set := Route53RecordSet{
+			Name: rrSet.Name,
+			Type: string(rrSet.Type),
+			TTL:  rrSet.TTL,
+		}
+		for _, record := range rrSet.Records {
+			if !record.Disabled {
+				set.ResourceRecords = append(set.ResourceRecords, Route53ResourceRecord{Value: record.Content})
+			}
+		}
+		if len(set.ResourceRecords) == 0 {
+			continue
+		}
+
+		batch.Changes = append(batch.Changes, Route53Change{Action: "UPSERT", ResourceRecordSet: set})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(batch)
+}
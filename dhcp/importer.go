@@ -0,0 +1,303 @@
+package dhcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/ops"
+	"github.com/dmportella/powerdns/types"
+	"github.com/miekg/dns"
+)
+
+// DefaultTTL TTL of the records published for leases.
+const DefaultTTL = 300
+
+// Lease file formats understood by Importer.Run.
+const (
+	FormatDHCPD = "dhcpd"
+	FormatKea   = "kea"
+)
+
+// Importer Publishes <hostname>.<Zone> A/AAAA records, and PTR records in ReverseZone,
+// for live leases. Records carry the lease end as an ops lease comment and are deleted
+// once it passes, so Zone should be dedicated to DHCP clients.
+type Importer struct {
+	Client client.RecordAPI
+	Zone   string
+	// ReverseZone, when set, receives PTR records for the addresses it covers.
+	ReverseZone string
+	TTL         int
+
+	// LeaseFile and Format ("dhcpd" or "kea") select the file read by Run.
+	LeaseFile string
+	Format    string
+	Interval  time.Duration
+
+	// OnError, when set, is called when an import fails.
+	OnError func(err error)
+}
+
+// Run Imports the lease file immediately and then on each interval until ctx is done.
+func (importer *Importer) Run(ctx context.Context) error {
+	ticker := time.NewTicker(importer.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := importer.ImportFile(); err != nil && importer.OnError != nil {
+			importer.OnError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ImportFile Reads LeaseFile and imports its leases.
+func (importer *Importer) ImportFile() error {
+	file, err := os.Open(importer.LeaseFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var leases []Lease
+	switch importer.Format {
+	case FormatDHCPD:
+		leases, err = ParseDHCPDLeases(file)
+	case FormatKea:
+		leases, err = ParseKeaLeases(file)
+	default:
+		return fmt.Errorf("Unknown lease file format: %q", importer.Format)
+	}
+	if err != nil {
+		return fmt.Errorf("Error reading lease file: %s, %w", importer.LeaseFile, err)
+	}
+
+	return importer.Import(leases, time.Now())
+}
+
+// Import Publishes the records of the leases live at now, deletes the records of
+// released leases and reaps the records of leases that ended.
+func (importer *Importer) Import(leases []Lease, now time.Time) error {
+	forward := make(map[string]*types.ResourceRecordSet)
+	var names []string
+	var reverse []types.ResourceRecordSet
+
+	for _, lease := range leases {
+		name := importer.name(lease.Hostname)
+		if name == "" {
+			continue
+		}
+		if !lease.Live(now) {
+			if lease.Active {
+				continue // ended, the reaper deletes its records
+			}
+			if err := importer.Release(lease); err != nil {
+				return err
+			}
+			continue
+		}
+
+		tpe := types.TypeAAAA
+		if lease.Address.To4() != nil {
+			tpe = types.TypeA
+		}
+		key := name + "/" + string(tpe)
+		rrSet, ok := forward[key]
+		if !ok {
+			rrSet = &types.ResourceRecordSet{Name: name, Type: tpe, TTL: importer.ttl(), ChangeType: types.ChangeTypeReplace}
+			forward[key] = rrSet
+			names = append(names, key)
+		}
+		rrSet.Records = append(rrSet.Records, types.Record{Content: lease.Address.String()})
+		if expiry, ok := ops.LeaseExpiry(rrSet); !ok || lease.Ends.After(expiry) {
+			ops.SetLeaseExpiry(rrSet, lease.Ends)
+		}
+
+		if ptr, ok := importer.pointer(lease, name); ok {
+			reverse = append(reverse, ptr)
+		}
+	}
+
+	rrSets := make([]types.ResourceRecordSet, 0, len(names))
+	for _, key := range names {
+		rrSets = append(rrSets, *forward[key])
+	}
+
+	if len(rrSets) > 0 {
+		if err := importer.Client.PatchRecordSets(importer.Zone, rrSets); err != nil {
+			return err
+		}
+	}
+	if len(reverse) > 0 {
+		if err := importer.Client.PatchRecordSets(importer.ReverseZone, reverse); err != nil {
+			return err
+		}
+	}
+
+	return importer.reap(now)
+}
+
+// Release Deletes the address of lease from the records of its host name, and its PTR
+// record when it still points at the host.
+func (importer *Importer) Release(lease Lease) error {
+	name := importer.name(lease.Hostname)
+	if name == "" {
+		return nil
+	}
+
+	tpe := types.TypeAAAA
+	if lease.Address.To4() != nil {
+		tpe = types.TypeA
+	}
+
+	rrSet, err := importer.Client.GetRecordSet(importer.Zone, name, string(tpe))
+	if err != nil {
+		return err
+	}
+	if rrSet != nil {
+		records := rrSet.Records[:0]
+		for _, record := range rrSet.Records {
+			if record.Content != lease.Address.String() {
+				records = append(records, record)
+			}
+		}
+		rrSet.Records = records
+
+		if len(records) == 0 {
+			err = importer.Client.DeleteRecordSet(importer.Zone, name, string(tpe))
+		} else {
+			_, err = importer.Client.ReplaceRecordSet(importer.Zone, *rrSet)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	ptr, ok := importer.pointer(lease, name)
+	if !ok {
+		return nil
+	}
+
+	current, err := importer.Client.GetRecordSet(importer.ReverseZone, ptr.Name, string(types.TypePTR))
+	if err != nil || current == nil {
+		return err
+	}
+	for _, record := range current.Records {
+		if types.EqualNames(record.Content, name) {
+			return importer.Client.DeleteRecordSet(importer.ReverseZone, ptr.Name, string(types.TypePTR))
+		}
+	}
+
+	return nil
+}
+
+// ServeHTTP Handles a lease event posted as a JSON Lease: live leases are published,
+// others released. It lets DHCP server hooks push changes instead of waiting for Run.
+func (importer *Importer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != "POST" {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var lease Lease
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, 1<<16)).Decode(&lease); err != nil || lease.Address == nil {
+		http.Error(w, "invalid lease", http.StatusBadRequest)
+		return
+	}
+
+	var err error
+	if lease.Live(time.Now()) {
+		err = importer.Import([]Lease{lease}, time.Now())
+	} else {
+		err = importer.Release(lease)
+	}
+	if err != nil {
+		if importer.OnError != nil {
+			importer.OnError(err)
+		}
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Returns the PTR rrset of lease when ReverseZone covers its address
+func (importer *Importer) pointer(lease Lease, name string) (types.ResourceRecordSet, bool) {
+	if importer.ReverseZone == "" {
+		return types.ResourceRecordSet{}, false
+	}
+
+	reverseName, err := dns.ReverseAddr(lease.Address.String())
+	if err != nil || !types.IsSubdomainOf(reverseName, importer.ReverseZone) {
+		return types.ResourceRecordSet{}, false
+	}
+
+	ptr := types.ResourceRecordSet{
+		Name:       reverseName,
+		Type:       types.TypePTR,
+		TTL:        importer.ttl(),
+		ChangeType: types.ChangeTypeReplace,
+		Records:    []types.Record{{Content: name}},
+	}
+	ops.SetLeaseExpiry(&ptr, lease.Ends)
+
+	return ptr, true
+}
+
+// Deletes the records of leases that ended before now
+func (importer *Importer) reap(now time.Time) error {
+	zones := []string{importer.Zone}
+	if importer.ReverseZone != "" {
+		zones = append(zones, importer.ReverseZone)
+	}
+
+	reaper := ops.Reaper{Client: importer.Client}
+	for _, zone := range zones {
+		if _, err := reaper.Reap(zone, now); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the record name of hostname in the zone, or an empty string when the
+// lease has no usable host name
+func (importer *Importer) name(hostname string) string {
+	label := strings.Trim(strings.Map(func(r rune) rune {
+		switch {
+		case 'a' <= r && r <= 'z', '0' <= r && r <= '9', r == '-':
+			return r
+		case 'A' <= r && r <= 'Z':
+			return r + 'a' - 'A'
+		}
+		return '-'
+	}, strings.SplitN(hostname, ".", 2)[0]), "-")
+	if len(label) > 63 {
+		label = strings.TrimRight(label[:63], "-")
+	}
+	if label == "" {
+		return ""
+	}
+
+	return label + "." + dns.Fqdn(importer.Zone)
+}
+
+func (importer *Importer) ttl() int {
+	if importer.TTL == 0 {
+		return DefaultTTL
+	}
+
+	return importer.TTL
+}
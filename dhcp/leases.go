@@ -0,0 +1,191 @@
+// Package dhcp turns PowerDNS into dynamic DNS for DHCP clients: it reads ISC dhcpd and
+// Kea lease files, or receives lease events over HTTP, and publishes A/AAAA and PTR
+// records for active leases that expire together with the leases.
+package dhcp
+
+import (
+	"bufio"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Lease Address lease handed out by a DHCP server.
+type Lease struct {
+	Address  net.IP    `json:"address"`
+	Hostname string    `json:"hostname"`
+	HWAddr   string    `json:"hwaddr,omitempty"`
+	Ends     time.Time `json:"ends"`
+	// Active is false for released, expired, declined or abandoned leases.
+	Active bool `json:"active"`
+}
+
+// Live Returns true when the lease is active and has not ended at now.
+func (lease *Lease) Live(now time.Time) bool {
+	return lease.Active && lease.Ends.After(now)
+}
+
+// Never End of leases that never end.
+var Never = time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)
+
+// ParseDHCPDLeases Parses an ISC dhcpd leases file. The file is a journal, so only the
+// last entry of each address is returned, in order of first appearance. Leases
+// without an end get Never.
+func ParseDHCPDLeases(r io.Reader) ([]Lease, error) {
+	var order []string
+	byAddress := make(map[string]*Lease)
+
+	var current *Lease
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := scanner.Text()
+		if i := strings.Index(text, "#"); i >= 0 && !strings.Contains(text, `"`) {
+			text = text[:i]
+		}
+		text = strings.TrimSpace(text)
+		if text == "" {
+			continue
+		}
+
+		fields := strings.Fields(strings.TrimSuffix(text, ";"))
+		switch {
+		case current == nil && fields[0] == "lease" && len(fields) == 3 && fields[2] == "{":
+			ip := net.ParseIP(fields[1])
+			if ip == nil {
+				return nil, fmt.Errorf("Invalid lease address at line %d: %q", line, fields[1])
+			}
+			current = &Lease{Address: ip}
+		case current == nil:
+			// other declarations, e.g. server-duid or failover state
+		case fields[0] == "}":
+			key := current.Address.String()
+			if _, ok := byAddress[key]; !ok {
+				order = append(order, key)
+			}
+			byAddress[key] = current
+			current = nil
+		case fields[0] == "ends":
+			ends, err := parseDHCPDTime(fields[1:])
+			if err != nil {
+				return nil, fmt.Errorf("Invalid lease end at line %d: %w", line, err)
+			}
+			current.Ends = ends
+		case fields[0] == "binding" && len(fields) == 3 && fields[1] == "state":
+			current.Active = fields[2] == "active"
+		case fields[0] == "hardware" && len(fields) == 3:
+			current.HWAddr = fields[2]
+		case fields[0] == "client-hostname" && len(fields) >= 2:
+			current.Hostname = strings.Trim(strings.Join(fields[1:], " "), `"`)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if current != nil {
+		return nil, fmt.Errorf("Unterminated lease: %s", current.Address)
+	}
+
+	leases := make([]Lease, 0, len(order))
+	for _, key := range order {
+		leases = append(leases, *byAddress[key])
+	}
+
+	return leases, nil
+}
+
+// Parses "<weekday> yyyy/mm/dd hh:mm:ss" (UTC), "epoch <seconds>" or "never"
+func parseDHCPDTime(fields []string) (time.Time, error) {
+	switch {
+	case len(fields) == 1 && fields[0] == "never":
+		return Never, nil
+	case len(fields) >= 2 && fields[0] == "epoch":
+		seconds, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(seconds, 0), nil
+	case len(fields) == 3:
+		return time.Parse("2006/01/02 15:04:05", fields[1]+" "+fields[2])
+	}
+
+	return time.Time{}, fmt.Errorf("unknown time format: %q", strings.Join(fields, " "))
+}
+
+// ParseKeaLeases Parses a Kea memfile lease file (CSV, DHCPv4 or DHCPv6). Like the
+// memfile itself, later rows of an address replace earlier ones, and rows with a
+// valid_lifetime of 0 mark deleted leases.
+func ParseKeaLeases(r io.Reader) ([]Lease, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("Invalid Kea lease file: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[name] = i
+	}
+	for _, name := range []string{"address", "expire", "valid_lifetime"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("Invalid Kea lease file: missing column %q", name)
+		}
+	}
+	field := func(row []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	var order []string
+	byAddress := make(map[string]*Lease)
+	for line := 2; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("Invalid Kea lease file: %w", err)
+		}
+
+		ip := net.ParseIP(field(row, "address"))
+		if ip == nil {
+			return nil, fmt.Errorf("Invalid lease address at line %d: %q", line, field(row, "address"))
+		}
+		expire, err := strconv.ParseInt(field(row, "expire"), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid lease expiry at line %d: %w", line, err)
+		}
+
+		// state 0 is the default (assigned) state; declined and expired-reclaimed
+		// leases are kept in the file but no longer in use
+		state := field(row, "state")
+		lease := &Lease{
+			Address:  ip,
+			Hostname: strings.TrimSuffix(field(row, "hostname"), "."),
+			HWAddr:   field(row, "hwaddr"),
+			Ends:     time.Unix(expire, 0),
+			Active:   (state == "" || state == "0") && field(row, "valid_lifetime") != "0",
+		}
+
+		key := ip.String()
+		if _, ok := byAddress[key]; !ok {
+			order = append(order, key)
+		}
+		byAddress[key] = lease
+	}
+
+	leases := make([]Lease, 0, len(order))
+	for _, key := range order {
+		leases = append(leases, *byAddress[key])
+	}
+
+	return leases, nil
+}
@@ -0,0 +1,310 @@
+// Package docker publishes A/AAAA records for Docker containers, following the events of
+// the Docker engine. It talks to the engine API over its unix socket directly.
+package docker
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/ops"
+	"github.com/dmportella/powerdns/types"
+)
+
+// DefaultSocket Path of the Docker engine socket.
+const DefaultSocket = "/var/run/docker.sock"
+
+// DefaultLabelPrefix Prefix of the container labels read by the Listener.
+const DefaultLabelPrefix = "powerdns."
+
+// DefaultTTL TTL of container records when neither the labels nor the Listener set one.
+const DefaultTTL = 60
+
+// Listener Creates records for containers carrying a "<prefix>zone" label when they
+// start and deletes them when they stop. Other labels:
+//
+//	<prefix>name  template of the record name, relative to the zone unless it ends with
+//	              a dot; fields .Name, .ID and .Hostname, default "{{.Name}}"
+//	<prefix>ttl   record TTL in seconds
+//
+// Records are tagged with OwnerID, so existing records are never overwritten.
+type Listener struct {
+	Client  client.RecordAPI
+	OwnerID string
+
+	Socket      string
+	LabelPrefix string
+	TTL         int
+	// Network, when set, selects the container network whose addresses are published;
+	// otherwise the addresses of every network are.
+	Network string
+
+	// OnError, when set, is called when a container could not be published or removed.
+	OnError func(containerID string, err error)
+
+	http *http.Client
+}
+
+type container struct {
+	ID     string `json:"Id"`
+	Name   string `json:"Name"`
+	Config struct {
+		Hostname string            `json:"Hostname"`
+		Labels   map[string]string `json:"Labels"`
+	} `json:"Config"`
+	NetworkSettings struct {
+		Networks map[string]struct {
+			IPAddress         string `json:"IPAddress"`
+			GlobalIPv6Address string `json:"GlobalIPv6Address"`
+		} `json:"Networks"`
+	} `json:"NetworkSettings"`
+}
+
+type event struct {
+	Type   string `json:"Type"`
+	Action string `json:"Action"`
+	Actor  struct {
+		ID         string            `json:"ID"`
+		Attributes map[string]string `json:"Attributes"`
+	} `json:"Actor"`
+}
+
+// Run Publishes the running labelled containers, then follows engine events until ctx
+// is done or the event stream fails.
+func (listener *Listener) Run(ctx context.Context) error {
+	since := strconv.FormatInt(time.Now().Unix(), 10)
+
+	var running []struct {
+		ID string `json:"Id"`
+	}
+	filters := fmt.Sprintf(`{"label":[%q]}`, listener.label("zone"))
+	if err := listener.get(ctx, "/containers/json?filters="+url.QueryEscape(filters), &running); err != nil {
+		return err
+	}
+	for _, c := range running {
+		listener.report(c.ID, listener.publish(ctx, c.ID))
+	}
+
+	filters = `{"type":["container"],"event":["start","die"]}`
+	resp, err := listener.stream(ctx, "/events?since="+since+"&filters="+url.QueryEscape(filters))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	decoder := json.NewDecoder(bufio.NewReader(resp.Body))
+	for {
+		var e event
+		if err = decoder.Decode(&e); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("Error reading Docker events: %w", err)
+		}
+		if e.Actor.Attributes[listener.label("zone")] == "" {
+			continue
+		}
+
+		switch e.Action {
+		case "start":
+			listener.report(e.Actor.ID, listener.publish(ctx, e.Actor.ID))
+		case "die":
+			listener.report(e.Actor.ID, listener.unpublish(ctx, e.Actor.ID))
+		}
+	}
+}
+
+// Creates the records of a container
+func (listener *Listener) publish(ctx context.Context, id string) error {
+	c, err := listener.inspect(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	zone, name, ttl, err := listener.recordName(c)
+	if err != nil {
+		return err
+	}
+
+	rrSets := map[types.RecordType]*types.ResourceRecordSet{
+		types.TypeA:    {Name: name, Type: types.TypeA, TTL: ttl},
+		types.TypeAAAA: {Name: name, Type: types.TypeAAAA, TTL: ttl},
+	}
+	for network, settings := range c.NetworkSettings.Networks {
+		if listener.Network != "" && network != listener.Network {
+			continue
+		}
+		if ip := net.ParseIP(settings.IPAddress); ip != nil {
+			rrSets[types.TypeA].Records = append(rrSets[types.TypeA].Records, types.Record{Content: ip.String()})
+		}
+		if ip := net.ParseIP(settings.GlobalIPv6Address); ip != nil {
+			rrSets[types.TypeAAAA].Records = append(rrSets[types.TypeAAAA].Records, types.Record{Content: ip.String()})
+		}
+	}
+
+	for _, rrSet := range rrSets {
+		if rrSet.Empty() {
+			continue
+		}
+		if _, err = ops.EnsureRecordSet(listener.Client, zone, *rrSet, listener.OwnerID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Deletes the owned records of a stopped container
+func (listener *Listener) unpublish(ctx context.Context, id string) error {
+	c, err := listener.inspect(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	zone, name, _, err := listener.recordName(c)
+	if err != nil {
+		return err
+	}
+
+	for _, tpe := range []types.RecordType{types.TypeA, types.TypeAAAA} {
+		current, err := listener.Client.GetRecordSet(zone, name, string(tpe))
+		if err != nil {
+			return err
+		}
+		if current == nil || ops.Owner(current) != listener.OwnerID {
+			continue
+		}
+		if err = listener.Client.DeleteRecordSet(zone, name, string(tpe)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Returns the zone, fully qualified record name and TTL of a container from its labels
+func (listener *Listener) recordName(c *container) (string, string, int, error) {
+	labels := c.Config.Labels
+	zone := strings.TrimSuffix(labels[listener.label("zone")], ".") + "."
+
+	nameTemplate := labels[listener.label("name")]
+	if nameTemplate == "" {
+		nameTemplate = "{{.Name}}"
+	}
+	tmpl, err := template.New("name").Parse(nameTemplate)
+	if err != nil {
+		return "", "", 0, fmt.Errorf("Invalid name template: %q, %w", nameTemplate, err)
+	}
+
+	shortID := c.ID
+	if len(shortID) > 12 {
+		shortID = shortID[:12]
+	}
+
+	var buf bytes.Buffer
+	err = tmpl.Execute(&buf, map[string]string{
+		"Name":     strings.TrimPrefix(c.Name, "/"),
+		"ID":       shortID,
+		"Hostname": c.Config.Hostname,
+	})
+	if err != nil {
+		return "", "", 0, err
+	}
+
+	name := strings.ToLower(buf.String())
+	if !strings.HasSuffix(name, ".") {
+		name += "." + zone
+	}
+	if !types.IsSubdomainOf(name, zone) {
+		return "", "", 0, fmt.Errorf("%s is not in zone %s: %w", name, zone, client.ErrOutOfZone)
+	}
+
+	ttl := listener.TTL
+	if value := labels[listener.label("ttl")]; value != "" {
+		if ttl, err = strconv.Atoi(value); err != nil {
+			return "", "", 0, fmt.Errorf("Invalid TTL label: %q", value)
+		}
+	}
+	if ttl == 0 {
+		ttl = DefaultTTL
+	}
+
+	return zone, name, ttl, nil
+}
+
+func (listener *Listener) inspect(ctx context.Context, id string) (*container, error) {
+	c := new(container)
+	if err := listener.get(ctx, "/containers/"+url.PathEscape(id)+"/json", c); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (listener *Listener) get(ctx context.Context, endpoint string, out interface{}) error {
+	resp, err := listener.stream(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// Sends a GET to the engine API and returns the successful response
+func (listener *Listener) stream(ctx context.Context, endpoint string) (*http.Response, error) {
+	if listener.http == nil {
+		socket := listener.Socket
+		if socket == "" {
+			socket = DefaultSocket
+		}
+		listener.http = &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, _ string, _ string) (net.Conn, error) {
+					return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+				},
+			},
+		}
+	}
+
+	req, err := http.NewRequest("GET", "http://docker"+endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := listener.http.Do(req.WithContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("Error querying Docker: %s, %w", endpoint, err)
+	}
+	if resp.StatusCode != 200 {
+		resp.Body.Close()
+		return nil, fmt.Errorf("Error querying Docker: %s, status: %d", endpoint, resp.StatusCode)
+	}
+
+	return resp, nil
+}
+
+func (listener *Listener) label(key string) string {
+	prefix := listener.LabelPrefix
+	if prefix == "" {
+		prefix = DefaultLabelPrefix
+	}
+
+	return prefix + key
+}
+
+func (listener *Listener) report(id string, err error) {
+	if err != nil && listener.OnError != nil {
+		listener.OnError(id, err)
+	}
+}
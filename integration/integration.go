@@ -0,0 +1,100 @@
+// Package integration starts a real PowerDNS authoritative server in Docker and runs
+// the client API surface against it. It is used by this repository's integration
+// tests and can be reused by downstream projects as a compatibility test kit.
+package integration
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/ory/dockertest/v3"
+)
+
+// Defaults used by Start when Options leave a value empty.
+const (
+	DefaultRepository = "powerdns/pdns-auth-48"
+	DefaultTag        = "latest"
+	DefaultAPIKey     = "integration"
+)
+
+// Options Container settings for Start.
+type Options struct {
+	Repository string
+	Tag        string
+	APIKey     string
+	// Env holds extra container environment, e.g. PDNS_AUTH_* settings. The official
+	// images use the bundled gsqlite3 backend unless configured otherwise.
+	Env []string
+	// StartTimeout bounds how long Start waits for the API to answer.
+	StartTimeout time.Duration
+}
+
+// Server PowerDNS container started by Start.
+type Server struct {
+	URL    string
+	APIKey string
+
+	pool     *dockertest.Pool
+	resource *dockertest.Resource
+}
+
+// Start Runs a PowerDNS authoritative container and waits until its API answers.
+func Start(opts Options) (*Server, error) {
+	if opts.Repository == "" {
+		opts.Repository = DefaultRepository
+	}
+	if opts.Tag == "" {
+		opts.Tag = DefaultTag
+	}
+	if opts.APIKey == "" {
+		opts.APIKey = DefaultAPIKey
+	}
+	if opts.StartTimeout == 0 {
+		opts.StartTimeout = 2 * time.Minute
+	}
+
+	pool, err := dockertest.NewPool("")
+	if err != nil {
+		return nil, fmt.Errorf("Error connecting to docker: %w", err)
+	}
+	pool.MaxWait = opts.StartTimeout
+
+	env := append([]string{"PDNS_AUTH_API_KEY=" + opts.APIKey}, opts.Env...)
+	resource, err := pool.Run(opts.Repository, opts.Tag, env)
+	if err != nil {
+		return nil, fmt.Errorf("Error starting %s:%s: %w", opts.Repository, opts.Tag, err)
+	}
+
+	server := &Server{
+		URL:      "http://" + resource.GetHostPort("8081/tcp"),
+		APIKey:   opts.APIKey,
+		pool:     pool,
+		resource: resource,
+	}
+
+	err = pool.Retry(func() error {
+		c, err := server.Client()
+		if err != nil {
+			return err
+		}
+		_, err = c.ListZones()
+		return err
+	})
+	if err != nil {
+		server.Close()
+		return nil, fmt.Errorf("Error waiting for PowerDNS API: %w", err)
+	}
+
+	return server, nil
+}
+
+// Client Returns a client connected to the server.
+func (server *Server) Client(opts ...client.Option) (*client.Client, error) {
+	return client.NewClient(server.URL, server.APIKey, opts...)
+}
+
+// Close Stops and removes the container.
+func (server *Server) Close() error {
+	return server.pool.Purge(server.resource)
+}
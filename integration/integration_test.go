@@ -0,0 +1,26 @@
+//go:build integration
+
+package integration
+
+import (
+	"os"
+	"testing"
+)
+
+// Runs the suite against a PowerDNS container; PDNS_IMAGE_TAG selects the image tag.
+// Run with: go test -tags integration ./integration
+func TestPowerDNS(t *testing.T) {
+	server, err := Start(Options{Tag: os.Getenv("PDNS_IMAGE_TAG")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// registered first so it runs after the zone cleanup of Run
+	t.Cleanup(func() { server.Close() })
+
+	c, err := server.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	Run(t, c, "integration.example.")
+}
@@ -0,0 +1,118 @@
+package integration
+
+import (
+	"testing"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// Run Exercises the client API surface against c, creating and deleting records in a
+// dedicated zone named zone. It fails t on the first unexpected result of each step. The
+// zone is deleted again when t finishes, even when a step failed.
+func Run(t *testing.T, c client.Provider, zone string) {
+	t.Cleanup(func() {
+		if exists, err := c.ZoneExists(zone); err != nil || !exists {
+			return
+		}
+		if _, err := c.DeleteZone(zone, client.DeleteZoneOptions{ForceDelete: true}); err != nil {
+			t.Errorf("DeleteZone: %s", err)
+		}
+	})
+
+	t.Run("CreateZone", func(t *testing.T) {
+		_, err := c.CreateZone(types.ZoneInfo{
+			Name:        zone,
+			Kind:        "Native",
+			Nameservers: []string{"ns1." + zone, "ns2." + zone},
+		}, nil)
+		if err != nil {
+			t.Fatalf("CreateZone: %s", err)
+		}
+	})
+
+	t.Run("ZoneExists", func(t *testing.T) {
+		exists, err := c.ZoneExists(zone)
+		if err != nil || !exists {
+			t.Fatalf("ZoneExists: %v, %v", exists, err)
+		}
+
+		exists, err = c.ZoneExists("missing." + zone)
+		if err != nil || exists {
+			t.Fatalf("ZoneExists on missing zone: %v, %v", exists, err)
+		}
+	})
+
+	t.Run("ListZones", func(t *testing.T) {
+		zones, err := c.ListZones()
+		if err != nil {
+			t.Fatalf("ListZones: %s", err)
+		}
+		for _, z := range zones {
+			if z.Name == zone {
+				return
+			}
+		}
+		t.Fatalf("ListZones: %s not listed", zone)
+	})
+
+	name := "www." + zone
+
+	t.Run("ReplaceRecordSet", func(t *testing.T) {
+		_, err := c.ReplaceRecordSet(zone, types.ResourceRecordSet{
+			Name: name,
+			Type: types.TypeA,
+			TTL:  300,
+			Records: []types.Record{
+				{Content: "192.0.2.1"},
+				{Content: "192.0.2.2", Disabled: true},
+			},
+		})
+		if err != nil {
+			t.Fatalf("ReplaceRecordSet: %s", err)
+		}
+	})
+
+	t.Run("ListRecordsByNameAndType", func(t *testing.T) {
+		records, err := c.ListRecordsByNameAndType(zone, name, "A")
+		if err != nil {
+			t.Fatalf("ListRecordsByNameAndType: %s", err)
+		}
+		if len(records) != 2 {
+			t.Fatalf("ListRecordsByNameAndType: expected 2 records, got %d", len(records))
+		}
+		if !records[1].Disabled {
+			t.Fatalf("ListRecordsByNameAndType: disabled flag lost")
+		}
+	})
+
+	t.Run("Metadata", func(t *testing.T) {
+		if err := c.SetMetadata(zone, "ALLOW-AXFR-FROM", []string{"AUTO-NS"}); err != nil {
+			t.Fatalf("SetMetadata: %s", err)
+		}
+		values, err := c.GetMetadata(zone, "ALLOW-AXFR-FROM")
+		if err != nil || len(values) != 1 {
+			t.Fatalf("GetMetadata: %v, %v", values, err)
+		}
+	})
+
+	t.Run("Cryptokeys", func(t *testing.T) {
+		if _, err := c.CreateCryptokey(zone, types.Cryptokey{KeyType: "csk", Active: true}); err != nil {
+			t.Fatalf("CreateCryptokey: %s", err)
+		}
+		keys, err := c.ListCryptokeys(zone)
+		if err != nil || len(keys) == 0 {
+			t.Fatalf("ListCryptokeys: %v, %v", keys, err)
+		}
+	})
+
+	t.Run("DeleteRecordSet", func(t *testing.T) {
+		if err := c.DeleteRecordSet(zone, name, "A"); err != nil {
+			t.Fatalf("DeleteRecordSet: %s", err)
+		}
+		exists, err := c.RecordExists(zone, name, "A")
+		if err != nil || exists {
+			t.Fatalf("RecordExists after delete: %v, %v", exists, err)
+		}
+	})
+}
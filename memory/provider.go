@@ -0,0 +1,272 @@
+// Package memory is an in-memory implementation of client.Provider, for wiring
+// applications against a fake PowerDNS in unit tests and demos without any HTTP.
+//
+// It mimics the server closely enough for typical automation: zones get an SOA and
+// apex NS records, PATCHes are validated and applied atomically, the serial is bumped on
+// every change, unknown zones fail with a 404 client.APIError and metadata kinds the
+// server protects fail with a 422. It does not sign zones: cryptokeys are stored, but
+// no key material is generated. Imported private keys are kept and, as by the server,
+// only returned by GetCryptokey.
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// Provider In-memory PowerDNS server. It is safe for concurrent use.
+type Provider struct {
+	mutex     sync.Mutex
+	zones     map[string]*types.ZoneInfo
+	metadata  map[string]map[string][]string
+	keys      map[string][]types.Cryptokey
+	nextKeyID int
+}
+
+var _ client.Provider = (*Provider)(nil)
+
+// New Returns an empty in-memory provider.
+func New() *Provider {
+	return &Provider{
+		zones:    make(map[string]*types.ZoneInfo),
+		metadata: make(map[string]map[string][]string),
+		keys:     make(map[string][]types.Cryptokey),
+	}
+}
+
+// Zones are keyed by their canonical name
+func zoneKey(zone string) string {
+	return strings.ToLower(strings.TrimSuffix(zone, ".")) + "."
+}
+
+func notFound(zone string, operation string) error {
+	return &client.APIError{
+		StatusCode: 404,
+		Message:    fmt.Sprintf("Could not find domain '%s'", zone),
+		Operation:  operation,
+	}
+}
+
+func unprocessable(message string, operation string) error {
+	return &client.APIError{StatusCode: 422, Message: message, Operation: operation}
+}
+
+// Returns the zone, with the mutex held by the caller
+func (provider *Provider) zone(zone string, operation string) (*types.ZoneInfo, error) {
+	zoneInfo, ok := provider.zones[zoneKey(zone)]
+	if !ok {
+		return nil, notFound(zone, operation)
+	}
+
+	return zoneInfo, nil
+}
+
+// ListZones Returns all zones, without records.
+func (provider *Provider) ListZones() ([]types.ZoneInfo, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	zones := make([]types.ZoneInfo, 0, len(provider.zones))
+	for _, zoneInfo := range provider.zones {
+		zone := *zoneInfo.Clone()
+		zone.ResourceRecordSets = nil
+		zones = append(zones, zone)
+	}
+	sort.Slice(zones, func(i, j int) bool {
+		return zones[i].Name < zones[j].Name
+	})
+
+	return zones, nil
+}
+
+// GetZone Returns a copy of zone including its record sets.
+func (provider *Provider) GetZone(zone string) (*types.ZoneInfo, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	zoneInfo, err := provider.zone(zone, fmt.Sprintf("reading zone: %s", zone))
+	if err != nil {
+		return nil, err
+	}
+
+	return zoneInfo.Clone(), nil
+}
+
+// ZoneExists Checks if zone exists.
+func (provider *Provider) ZoneExists(zone string) (bool, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	_, ok := provider.zones[zoneKey(zone)]
+	return ok, nil
+}
+
+// CreateZone Creates zone. Like the server, it adds an SOA record when none is given and
+// apex NS records for zone.Nameservers.
+func (provider *Provider) CreateZone(zone types.ZoneInfo, soa *types.SOATemplate) (*types.ZoneInfo, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	operation := fmt.Sprintf("creating zone: %s", zone.Name)
+	if !strings.HasSuffix(zone.Name, ".") {
+		return nil, unprocessable(fmt.Sprintf("DNS Name '%s' is not canonical", zone.Name), operation)
+	}
+	if _, ok := provider.zones[zoneKey(zone.Name)]; ok {
+		return nil, &client.APIError{StatusCode: 409, Message: "Conflict", Operation: operation}
+	}
+
+	if zone.Kind == "" {
+		zone.Kind = types.KindNative
+	}
+	kind, err := types.ParseZoneKind(string(zone.Kind))
+	if err != nil {
+		return nil, unprocessable(err.Error(), operation)
+	}
+
+	created := zone.Clone()
+	created.ID = zone.Name
+	created.Kind = kind.Normalize()
+	created.Serial = 1
+	created.Records = nil
+
+	if soa != nil {
+		created.ResourceRecordSets = append(created.ResourceRecordSets, soa.RecordSet(zone.Name))
+	}
+
+	hasSOA := false
+	for _, rrSet := range created.ResourceRecordSets {
+		hasSOA = hasSOA || rrSet.Type == types.TypeSOA
+	}
+	if !hasSOA {
+		primary := "a.misconfigured.dns.server.invalid."
+		if len(zone.Nameservers) > 0 {
+			primary = zone.Nameservers[0]
+		}
+		template := types.SOATemplate{PrimaryNS: primary, Hostmaster: "hostmaster." + zone.Name, Serial: 1}
+		created.ResourceRecordSets = append(created.ResourceRecordSets, template.RecordSet(zone.Name))
+	}
+
+	if len(zone.Nameservers) > 0 {
+		ns := types.ResourceRecordSet{Name: zone.Name, Type: types.TypeNS, TTL: types.DefaultSOATTL}
+		for _, nameserver := range zone.Nameservers {
+			ns.Records = append(ns.Records, types.Record{Content: nameserver})
+		}
+		created.ResourceRecordSets = append(created.ResourceRecordSets, ns)
+	}
+	created.Nameservers = nil
+
+	for _, rrSet := range created.ResourceRecordSets {
+		if err := rrSet.Type.Validate(); err != nil {
+			return nil, unprocessable(err.Error(), operation)
+		}
+	}
+	types.SortRecordSets(created.ResourceRecordSets)
+
+	provider.zones[zoneKey(zone.Name)] = created
+	provider.setPresigned(created, created.Presigned)
+	provider.setSOAEditAPI(created, created.SOAEditAPI)
+
+	return created.Clone(), nil
+}
+
+// DeleteZone Deletes zone, refusing zones with records other than the apex SOA and NS
+// records with client.ErrZoneNotEmpty unless ForceDelete is set.
+func (provider *Provider) DeleteZone(zone string, opts client.DeleteZoneOptions) (*client.DeletedZone, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	zoneInfo, err := provider.zone(zone, fmt.Sprintf("deleting zone: %s", zone))
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &client.DeletedZone{Zone: zone, Types: make(map[types.RecordType]int)}
+	userRecords := 0
+	for _, rrSet := range zoneInfo.ResourceRecordSets {
+		summary.RecordSets++
+		summary.Records += len(rrSet.Records)
+		summary.Types[rrSet.Type]++
+
+		apex := types.EqualNames(rrSet.Name, zoneInfo.Name)
+		if !(apex && (rrSet.Type == types.TypeSOA || rrSet.Type == types.TypeNS)) {
+			userRecords++
+		}
+	}
+
+	if userRecords > 0 && !opts.ForceDelete {
+		return nil, fmt.Errorf("Error deleting zone: %s, %d record sets besides SOA and NS: %w", zone, userRecords, client.ErrZoneNotEmpty)
+	}
+
+	delete(provider.zones, zoneKey(zone))
+	delete(provider.metadata, zoneKey(zone))
+	delete(provider.keys, zoneKey(zone))
+
+	return summary, nil
+}
+
+// SetPresigned Marks zone as presigned or not, kept like by the server as PRESIGNED
+// metadata.
+func (provider *Provider) SetPresigned(zone string, presigned bool) error {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	zoneInfo, err := provider.zone(zone, fmt.Sprintf("setting presigned of zone: %s", zone))
+	if err != nil {
+		return err
+	}
+	provider.setPresigned(zoneInfo, presigned)
+
+	return nil
+}
+
+// With the mutex held by the caller
+func (provider *Provider) setPresigned(zoneInfo *types.ZoneInfo, presigned bool) {
+	zoneInfo.Presigned = presigned
+
+	metadata := provider.metadata[zoneKey(zoneInfo.Name)]
+	if !presigned {
+		delete(metadata, client.MetadataPresigned)
+		return
+	}
+	if metadata == nil {
+		metadata = make(map[string][]string)
+		provider.metadata[zoneKey(zoneInfo.Name)] = metadata
+	}
+	metadata[client.MetadataPresigned] = []string{"1"}
+}
+
+// SetSOAEditAPI Changes the SOA-EDIT-API strategy of zone, kept like by the server as
+// SOA-EDIT-API metadata. An empty value removes it.
+func (provider *Provider) SetSOAEditAPI(zone string, value string) error {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	zoneInfo, err := provider.zone(zone, fmt.Sprintf("setting SOA-EDIT-API of zone: %s", zone))
+	if err != nil {
+		return err
+	}
+	provider.setSOAEditAPI(zoneInfo, value)
+
+	return nil
+}
+
+// With the mutex held by the caller
+func (provider *Provider) setSOAEditAPI(zoneInfo *types.ZoneInfo, value string) {
+	zoneInfo.SOAEditAPI = value
+
+	metadata := provider.metadata[zoneKey(zoneInfo.Name)]
+	if value == "" {
+		delete(metadata, client.MetadataSOAEditAPI)
+		return
+	}
+	if metadata == nil {
+		metadata = make(map[string][]string)
+		provider.metadata[zoneKey(zoneInfo.Name)] = metadata
+	}
+	metadata[client.MetadataSOAEditAPI] = []string{value}
+}
@@ -0,0 +1,295 @@
+package memory
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// ListRecords Returns all records of zone.
+func (provider *Provider) ListRecords(zone string) ([]types.Record, error) {
+	rrSets, err := provider.ListRecordsAsRRSet(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []types.Record
+	for _, rrSet := range rrSets {
+		records = append(records, rrSet.Flatten()...)
+	}
+	types.SortRecords(records)
+
+	return records, nil
+}
+
+// ListRecordsAsRRSet Returns a copy of the record sets of zone.
+func (provider *Provider) ListRecordsAsRRSet(zone string) ([]types.ResourceRecordSet, error) {
+	zoneInfo, err := provider.GetZone(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(zoneInfo.ResourceRecordSets) == 0 {
+		return nil, nil
+	}
+
+	return zoneInfo.ResourceRecordSets, nil
+}
+
+// GetRecordSet Returns the record set of name and type, or nil when it does not exist.
+func (provider *Provider) GetRecordSet(zone string, name string, tpe string) (*types.ResourceRecordSet, error) {
+	rrSets, err := provider.filter(zone, name, tpe)
+	if err != nil || len(rrSets) == 0 {
+		return nil, err
+	}
+
+	return &rrSets[0], nil
+}
+
+// ListRecordsByNameAndType Returns the records of name and type.
+func (provider *Provider) ListRecordsByNameAndType(zone string, name string, tpe string) ([]types.Record, error) {
+	rrSets, err := provider.filter(zone, name, tpe)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]types.Record, 0, 10)
+	for _, rrSet := range rrSets {
+		records = append(records, rrSet.Flatten()...)
+	}
+
+	return records, nil
+}
+
+// ListRecordsByID Returns the records of the record set identified by recID.
+func (provider *Provider) ListRecordsByID(zone string, recID string) ([]types.Record, error) {
+	name, tpe, err := types.ParseID(recID)
+	if err != nil {
+		return nil, err
+	}
+
+	return provider.ListRecordsByNameAndType(zone, name, tpe)
+}
+
+// GetRecordsByName Returns the record sets of name.
+func (provider *Provider) GetRecordsByName(zone string, name string) ([]types.ResourceRecordSet, error) {
+	return provider.filter(zone, name, "")
+}
+
+// GetRecordsByType Returns the record sets of type tpe.
+func (provider *Provider) GetRecordsByType(zone string, tpe string) ([]types.ResourceRecordSet, error) {
+	return provider.filter(zone, "", tpe)
+}
+
+// RecordExists Checks if the record set of name and type has records.
+func (provider *Provider) RecordExists(zone string, name string, tpe string) (bool, error) {
+	records, err := provider.ListRecordsByNameAndType(zone, name, tpe)
+	return len(records) > 0, err
+}
+
+// RecordExistsByID Checks if the record set identified by recID has records.
+func (provider *Provider) RecordExistsByID(zone string, recID string) (bool, error) {
+	name, tpe, err := types.ParseID(recID)
+	if err != nil {
+		return false, err
+	}
+
+	return provider.RecordExists(zone, name, tpe)
+}
+
+// CreateRecord Replaces the record set of the record with that single record.
+func (provider *Provider) CreateRecord(zone string, record types.Record) (string, error) {
+	err := provider.patch(zone, []types.ResourceRecordSet{
+		{
+			Name:       record.Name,
+			Type:       record.Type,
+			ChangeType: types.ChangeTypeReplace,
+			TTL:        record.TTL,
+			Records:    []types.Record{record},
+		},
+	}, fmt.Sprintf("creating record: %s", record.ID()))
+	if err != nil {
+		return "", err
+	}
+
+	return record.ID(), nil
+}
+
+// ReplaceRecordSet Creates or replaces rrSet.
+func (provider *Provider) ReplaceRecordSet(zone string, rrSet types.ResourceRecordSet) (string, error) {
+	rrSet.ChangeType = types.ChangeTypeReplace
+
+	err := provider.patch(zone, []types.ResourceRecordSet{rrSet}, fmt.Sprintf("creating record set: %s", rrSet.ID()))
+	if err != nil {
+		return "", err
+	}
+
+	return rrSet.ID(), nil
+}
+
+// DeleteRecordSet Deletes the record set of name and type.
+func (provider *Provider) DeleteRecordSet(zone string, name string, tpe string) error {
+	return provider.patch(zone, []types.ResourceRecordSet{
+		{
+			Name:       name,
+			Type:       types.RecordType(tpe),
+			ChangeType: types.ChangeTypeDelete,
+		},
+	}, fmt.Sprintf("deleting record: %s %s", name, tpe))
+}
+
+// DeleteRecordSetByID Deletes the record set identified by recID.
+func (provider *Provider) DeleteRecordSetByID(zone string, recID string) error {
+	name, tpe, err := types.ParseID(recID)
+	if err != nil {
+		return err
+	}
+
+	return provider.DeleteRecordSet(zone, name, tpe)
+}
+
+// RenameRecord Moves the record set of oldName and type to newName.
+func (provider *Provider) RenameRecord(zone string, oldName string, newName string, tpe string) error {
+	operation := fmt.Sprintf("renaming record: %s %s to %s", oldName, tpe, newName)
+
+	rrSet, err := provider.GetRecordSet(zone, oldName, tpe)
+	if err != nil {
+		return err
+	}
+	if rrSet == nil {
+		return fmt.Errorf("Error %s, record set does not exist", operation)
+	}
+
+	existing, err := provider.GetRecordSet(zone, newName, tpe)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("Error %s, target record set already exists", operation)
+	}
+
+	renamed := *rrSet
+	renamed.Name = newName
+	renamed.ChangeType = types.ChangeTypeReplace
+
+	return provider.patch(zone, []types.ResourceRecordSet{
+		renamed,
+		{
+			Name:       rrSet.Name,
+			Type:       rrSet.Type,
+			ChangeType: types.ChangeTypeDelete,
+		},
+	}, operation)
+}
+
+// PatchRecordSets Applies the record set changes to zone atomically.
+func (provider *Provider) PatchRecordSets(zone string, rrSets []types.ResourceRecordSet) error {
+	return provider.patch(zone, rrSets, fmt.Sprintf("patching record sets in zone: %s", zone))
+}
+
+// Returns copies of the record sets of zone matching name and type, either being optional
+func (provider *Provider) filter(zone string, name string, tpe string) ([]types.ResourceRecordSet, error) {
+	rrSets, err := provider.ListRecordsAsRRSet(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]types.ResourceRecordSet, 0, len(rrSets))
+	for _, rrSet := range rrSets {
+		if (name == "" || types.EqualNames(rrSet.Name, name)) && (tpe == "" || rrSet.Type == types.RecordType(tpe)) {
+			filtered = append(filtered, rrSet)
+		}
+	}
+
+	return filtered, nil
+}
+
+// Validates and applies record set changes the way the server does: all or nothing,
+// keeping the records or comments of a replaced record set when they are omitted
+func (provider *Provider) patch(zone string, changes []types.ResourceRecordSet, operation string) error {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	zoneInfo, err := provider.zone(zone, operation)
+	if err != nil {
+		return err
+	}
+
+	for _, change := range changes {
+		if err := change.ChangeType.Validate(); err != nil {
+			return unprocessable(err.Error(), operation)
+		}
+		if err := change.Type.Validate(); err != nil {
+			return unprocessable(err.Error(), operation)
+		}
+		if err := types.ValidateTTL(change.TTL); err != nil {
+			return unprocessable(err.Error(), operation)
+		}
+		if !types.IsSubdomainOf(change.Name, zoneInfo.Name) || !strings.HasSuffix(change.Name, ".") {
+			return unprocessable(fmt.Sprintf("Name '%s' is not in zone", change.Name), operation)
+		}
+	}
+
+	rrSets := make([]types.ResourceRecordSet, 0, len(zoneInfo.ResourceRecordSets)+len(changes))
+	for _, rrSet := range zoneInfo.ResourceRecordSets {
+		rrSets = append(rrSets, *rrSet.Clone())
+	}
+
+	now := time.Now().Unix()
+	for _, change := range changes {
+		var existing *types.ResourceRecordSet
+		kept := rrSets[:0]
+		for i := range rrSets {
+			if types.EqualNames(rrSets[i].Name, change.Name) && rrSets[i].Type == change.Type {
+				existing = rrSets[i].Clone()
+				continue
+			}
+			kept = append(kept, rrSets[i])
+		}
+		rrSets = kept
+
+		if change.ChangeType == types.ChangeTypeDelete {
+			continue
+		}
+
+		replaced := types.ResourceRecordSet{Name: change.Name, Type: change.Type, TTL: change.TTL}
+		for _, record := range change.Records {
+			replaced.Records = append(replaced.Records, types.Record{Content: record.Content, Disabled: record.Disabled})
+		}
+		for _, comment := range change.Comments {
+			if comment.ModifiedAt == 0 {
+				comment.ModifiedAt = now
+			}
+			replaced.Comments = append(replaced.Comments, comment)
+		}
+		if existing != nil && change.Records == nil {
+			replaced.Records = existing.Records
+		}
+		if existing != nil && change.Comments == nil {
+			replaced.Comments = existing.Comments
+		}
+
+		if !replaced.Empty() || len(replaced.Comments) > 0 {
+			rrSets = append(rrSets, replaced)
+		}
+	}
+
+	for _, rrSet := range rrSets {
+		if rrSet.Type != types.TypeCNAME || rrSet.Empty() {
+			continue
+		}
+		for _, other := range rrSets {
+			if other.Type != types.TypeCNAME && !other.Empty() && types.EqualNames(other.Name, rrSet.Name) {
+				return unprocessable(fmt.Sprintf("RRset %s IN %s: Conflicts with pre-existing RRset", other.Name, other.Type), operation)
+			}
+		}
+	}
+
+	types.SortRecordSets(rrSets)
+	zoneInfo.ResourceRecordSets = rrSets
+	zoneInfo.Serial++
+
+	return nil
+}
@@ -0,0 +1,187 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// ListMetadata Returns all metadata of zone, sorted by kind.
+func (provider *Provider) ListMetadata(zone string) ([]types.Metadata, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	if _, err := provider.zone(zone, fmt.Sprintf("listing metadata of zone: %s", zone)); err != nil {
+		return nil, err
+	}
+
+	metadata := make([]types.Metadata, 0, len(provider.metadata[zoneKey(zone)]))
+	for kind, values := range provider.metadata[zoneKey(zone)] {
+		metadata = append(metadata, types.Metadata{Kind: kind, Metadata: append([]string(nil), values...)})
+	}
+	sort.Slice(metadata, func(i, j int) bool {
+		return metadata[i].Kind < metadata[j].Kind
+	})
+
+	return metadata, nil
+}
+
+// GetMetadata Returns the values of a metadata kind of zone.
+func (provider *Provider) GetMetadata(zone string, kind string) ([]string, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	if _, err := provider.zone(zone, fmt.Sprintf("reading metadata: %s of zone: %s", kind, zone)); err != nil {
+		return nil, err
+	}
+
+	return append([]string(nil), provider.metadata[zoneKey(zone)][kind]...), nil
+}
+
+// SetMetadata Replaces the values of a metadata kind of zone. Like the server, it
+// refuses the kinds client.IsProtectedMetadata reports with a 422.
+func (provider *Provider) SetMetadata(zone string, kind string, values []string) error {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	operation := fmt.Sprintf("updating metadata: %s of zone: %s", kind, zone)
+	if _, err := provider.zone(zone, operation); err != nil {
+		return err
+	}
+	if client.IsProtectedMetadata(kind) {
+		return unprocessable(fmt.Sprintf("Unsupported metadata kind '%s'", kind), operation)
+	}
+
+	if provider.metadata[zoneKey(zone)] == nil {
+		provider.metadata[zoneKey(zone)] = make(map[string][]string)
+	}
+	provider.metadata[zoneKey(zone)][kind] = append([]string(nil), values...)
+
+	return nil
+}
+
+// DeleteMetadata Deletes a metadata kind from zone, refusing protected kinds like
+// SetMetadata.
+func (provider *Provider) DeleteMetadata(zone string, kind string) error {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	operation := fmt.Sprintf("deleting metadata: %s of zone: %s", kind, zone)
+	if _, err := provider.zone(zone, operation); err != nil {
+		return err
+	}
+	if client.IsProtectedMetadata(kind) {
+		return unprocessable(fmt.Sprintf("Unsupported metadata kind '%s'", kind), operation)
+	}
+
+	delete(provider.metadata[zoneKey(zone)], kind)
+
+	return nil
+}
+
+// ListCryptokeys Returns the DNSSEC keys of zone.
+func (provider *Provider) ListCryptokeys(zone string) ([]types.Cryptokey, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	if _, err := provider.zone(zone, fmt.Sprintf("listing cryptokeys of zone: %s", zone)); err != nil {
+		return nil, err
+	}
+
+	keys := append([]types.Cryptokey(nil), provider.keys[zoneKey(zone)]...)
+	for i := range keys {
+		keys[i].PrivateKey = ""
+	}
+
+	return keys, nil
+}
+
+// GetCryptokey Returns a DNSSEC key of zone including its private key, when one was imported.
+func (provider *Provider) GetCryptokey(zone string, id int) (*types.Cryptokey, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	operation := fmt.Sprintf("reading cryptokey: %d of zone: %s", id, zone)
+	if _, err := provider.zone(zone, operation); err != nil {
+		return nil, err
+	}
+
+	for _, key := range provider.keys[zoneKey(zone)] {
+		if key.ID == id {
+			return &key, nil
+		}
+	}
+
+	return nil, &client.APIError{StatusCode: 404, Message: "Could not find cryptokey", Operation: operation}
+}
+
+// CreateCryptokey Stores a DNSSEC key for zone under a new ID and marks the zone as
+// signed. No key material is generated; an imported private key is kept.
+func (provider *Provider) CreateCryptokey(zone string, key types.Cryptokey) (*types.Cryptokey, error) {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	zoneInfo, err := provider.zone(zone, fmt.Sprintf("creating %s cryptokey in zone: %s", key.KeyType, zone))
+	if err != nil {
+		return nil, err
+	}
+
+	provider.nextKeyID++
+	key.ID = provider.nextKeyID
+	key.Type = "Cryptokey"
+	if key.KeyType == "" {
+		key.KeyType = "csk"
+	}
+
+	provider.keys[zoneKey(zone)] = append(provider.keys[zoneKey(zone)], key)
+	zoneInfo.DNSSec = true
+
+	return &key, nil
+}
+
+// SetCryptokeyActive Activates or deactivates a DNSSEC key of zone.
+func (provider *Provider) SetCryptokeyActive(zone string, id int, active bool) error {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	operation := fmt.Sprintf("updating cryptokey: %d of zone: %s", id, zone)
+	if _, err := provider.zone(zone, operation); err != nil {
+		return err
+	}
+
+	keys := provider.keys[zoneKey(zone)]
+	for i := range keys {
+		if keys[i].ID == id {
+			keys[i].Active = active
+			return nil
+		}
+	}
+
+	return &client.APIError{StatusCode: 404, Message: "Could not find cryptokey", Operation: operation}
+}
+
+// DeleteCryptokey Deletes a DNSSEC key from zone, which is no longer signed once its
+// last key is gone.
+func (provider *Provider) DeleteCryptokey(zone string, id int) error {
+	provider.mutex.Lock()
+	defer provider.mutex.Unlock()
+
+	operation := fmt.Sprintf("deleting cryptokey: %d of zone: %s", id, zone)
+	zoneInfo, err := provider.zone(zone, operation)
+	if err != nil {
+		return err
+	}
+
+	keys := provider.keys[zoneKey(zone)]
+	for i := range keys {
+		if keys[i].ID == id {
+			provider.keys[zoneKey(zone)] = append(keys[:i], keys[i+1:]...)
+			zoneInfo.DNSSec = len(provider.keys[zoneKey(zone)]) > 0
+			return nil
+		}
+	}
+
+	return &client.APIError{StatusCode: 404, Message: "Could not find cryptokey", Operation: operation}
+}
@@ -0,0 +1,58 @@
+package memory
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+func TestProtectedMetadataRefused(t *testing.T) {
+	provider := New()
+	if _, err := provider.CreateZone(types.ZoneInfo{Name: "example.com."}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	var apiErr *client.APIError
+	if err := provider.SetMetadata("example.com.", client.MetadataPresigned, []string{"1"}); !errors.As(err, &apiErr) || apiErr.StatusCode != 422 {
+		t.Errorf("SetMetadata(PRESIGNED) = %v, want a 422", err)
+	}
+	if err := provider.DeleteMetadata("example.com.", "SOA-EDIT-API"); !errors.As(err, &apiErr) || apiErr.StatusCode != 422 {
+		t.Errorf("DeleteMetadata(SOA-EDIT-API) = %v, want a 422", err)
+	}
+	if err := provider.SetMetadata("example.com.", "ALLOW-AXFR-FROM", []string{"AUTO-NS"}); err != nil {
+		t.Errorf("SetMetadata(ALLOW-AXFR-FROM) = %v", err)
+	}
+}
+
+func TestSetPresigned(t *testing.T) {
+	provider := New()
+	if _, err := provider.CreateZone(types.ZoneInfo{Name: "example.com."}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, presigned := range []bool{true, false} {
+		if err := provider.SetPresigned("example.com.", presigned); err != nil {
+			t.Fatal(err)
+		}
+
+		zone, err := provider.GetZone("example.com.")
+		if err != nil {
+			t.Fatal(err)
+		}
+		values, err := provider.GetMetadata("example.com.", client.MetadataPresigned)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var want []string
+		if presigned {
+			want = []string{"1"}
+		}
+		if zone.Presigned != presigned || !reflect.DeepEqual(values, want) {
+			t.Errorf("after SetPresigned(%v): presigned %v, PRESIGNED metadata %v", presigned, zone.Presigned, values)
+		}
+	}
+}
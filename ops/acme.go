@@ -0,0 +1,193 @@
+package ops
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+	"golang.org/x/sync/errgroup"
+)
+
+// ACMEChallengeTTL TTL of the challenge TXT records, short so retries see new values.
+const ACMEChallengeTTL = 60
+
+// ACMEChallenge DNS-01 challenge of one identifier: Hostname, e.g. "example.com" or
+// "*.example.com", must publish Value, the key authorization digest, in a TXT record.
+type ACMEChallenge struct {
+	Hostname string
+	Value    string
+}
+
+// ChallengeName Returns the _acme-challenge name of the challenge. Wildcard and apex
+// identifiers share the name, so their values must coexist in one rrset.
+func (challenge ACMEChallenge) ChallengeName() string {
+	host := strings.TrimPrefix(strings.TrimSuffix(challenge.Hostname, "."), "*.")
+	return "_acme-challenge." + host + "."
+}
+
+// ACMEBundle Creates and cleans up DNS-01 challenge records for certificates covering
+// several host names, including apex and wildcard names of the same domain. A challenge
+// name delegated with a CNAME, e.g. to a zone dedicated to validation, is followed and
+// the TXT record is written in the target zone, through whichever client hosts it.
+type ACMEBundle struct {
+	// Clients are searched in order for the zone hosting each challenge record.
+	Clients []client.Provider
+	TTL     int
+}
+
+// Location of a challenge TXT rrset
+type acmeTarget struct {
+	client client.Provider
+	zone   string
+	name   string
+}
+
+// Present Publishes the values of every challenge, one PATCH per challenge name, written
+// in parallel. Values already present, e.g. from a concurrent order, are kept.
+func (bundle *ACMEBundle) Present(challenges []ACMEChallenge) error {
+	return bundle.update(challenges, true)
+}
+
+// CleanUp Removes the values of every challenge, deleting challenge rrsets left empty.
+func (bundle *ACMEBundle) CleanUp(challenges []ACMEChallenge) error {
+	return bundle.update(challenges, false)
+}
+
+func (bundle *ACMEBundle) update(challenges []ACMEChallenge, present bool) error {
+	zones := make(map[client.Provider][]string)
+	var targets []acmeTarget
+	values := make(map[acmeTarget][]string)
+	for _, challenge := range challenges {
+		target, err := bundle.resolve(zones, challenge.ChallengeName())
+		if err != nil {
+			return err
+		}
+
+		if _, ok := values[target]; !ok {
+			targets = append(targets, target)
+		}
+		values[target] = append(values[target], types.QuoteTXT(challenge.Value))
+	}
+
+	var group errgroup.Group
+	for _, target := range targets {
+		target := target
+		group.Go(func() error {
+			return bundle.apply(target, values[target], present)
+		})
+	}
+
+	return group.Wait()
+}
+
+// Adds or removes values in the TXT rrset of target
+func (bundle *ACMEBundle) apply(target acmeTarget, values []string, present bool) error {
+	current, err := target.client.GetRecordSet(target.zone, target.name, string(types.TypeTXT))
+	if err != nil {
+		return err
+	}
+
+	contents := make(map[string]bool)
+	var records []types.Record
+	if current != nil {
+		for _, record := range current.Records {
+			contents[record.Content] = true
+			records = append(records, record)
+		}
+	}
+
+	if present {
+		for _, value := range values {
+			if !contents[value] {
+				contents[value] = true
+				records = append(records, types.Record{Content: value})
+			}
+		}
+	} else {
+		removed := make(map[string]bool, len(values))
+		for _, value := range values {
+			removed[value] = true
+		}
+		kept := records[:0]
+		for _, record := range records {
+			if !removed[record.Content] {
+				kept = append(kept, record)
+			}
+		}
+		records = kept
+	}
+
+	if len(records) == 0 {
+		if current == nil {
+			return nil
+		}
+		return target.client.DeleteRecordSet(target.zone, target.name, string(types.TypeTXT))
+	}
+
+	ttl := bundle.TTL
+	if ttl == 0 {
+		ttl = ACMEChallengeTTL
+	}
+
+	_, err = target.client.ReplaceRecordSet(target.zone, types.ResourceRecordSet{
+		Name:    target.name,
+		Type:    types.TypeTXT,
+		TTL:     ttl,
+		Records: records,
+	})
+	return err
+}
+
+// Returns where the TXT rrset of name is written, following a CNAME delegation once
+func (bundle *ACMEBundle) resolve(zones map[client.Provider][]string, name string) (acmeTarget, error) {
+	target, err := bundle.locate(zones, name)
+	if err != nil {
+		return acmeTarget{}, err
+	}
+
+	cname, err := target.client.GetRecordSet(target.zone, name, string(types.TypeCNAME))
+	if err != nil {
+		return acmeTarget{}, err
+	}
+	if cname == nil || cname.Empty() {
+		return target, nil
+	}
+
+	delegated := cname.Records[0].Content
+	if !strings.HasSuffix(delegated, ".") {
+		delegated += "."
+	}
+
+	return bundle.locate(zones, delegated)
+}
+
+// Returns the client and zone hosting name, preferring the most specific zone. The zone
+// names of each client are listed once and kept in zones
+func (bundle *ACMEBundle) locate(zones map[client.Provider][]string, name string) (acmeTarget, error) {
+	best := acmeTarget{name: name}
+	for _, c := range bundle.Clients {
+		if _, ok := zones[c]; !ok {
+			zoneInfos, err := c.ListZones()
+			if err != nil {
+				return acmeTarget{}, err
+			}
+			zones[c] = make([]string, 0, len(zoneInfos))
+			for _, zoneInfo := range zoneInfos {
+				zones[c] = append(zones[c], zoneInfo.Name)
+			}
+		}
+
+		for _, zone := range zones[c] {
+			if types.IsSubdomainOf(name, zone) && len(zone) > len(best.zone) {
+				best.client, best.zone = c, zone
+			}
+		}
+	}
+
+	if best.client == nil {
+		return acmeTarget{}, fmt.Errorf("Error locating ACME challenge: %s, no zone hosts it", name)
+	}
+
+	return best, nil
+}
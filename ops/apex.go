@@ -0,0 +1,51 @@
+package ops
+
+import (
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// ApexName Resolves "@" and empty names to the zone origin; other names are returned as is.
+func ApexName(zone string, name string) string {
+	if name == "" || name == "@" {
+		return zone
+	}
+
+	return name
+}
+
+// SetApexRecords Replaces the rrset of the given type at the apex of zone. CNAME is
+// rejected with client.ErrCNAMEAtApex; use SetApexALIAS to point the apex at a host name.
+func SetApexRecords(c client.RecordAPI, zone string, tpe types.RecordType, ttl int, contents []string) error {
+	if tpe == types.TypeCNAME {
+		return client.ErrCNAMEAtApex
+	}
+
+	rrSet := types.ResourceRecordSet{
+		Name: zone,
+		Type: tpe,
+		TTL:  ttl,
+	}
+	for _, content := range contents {
+		rrSet.Records = append(rrSet.Records, types.Record{Content: content})
+	}
+
+	_, err := c.ReplaceRecordSet(zone, rrSet)
+	return err
+}
+
+// SetApexA Replaces the A records at the apex of zone.
+func SetApexA(c client.RecordAPI, zone string, ttl int, ips []string) error {
+	return SetApexRecords(c, zone, types.TypeA, ttl, ips)
+}
+
+// SetApexAAAA Replaces the AAAA records at the apex of zone.
+func SetApexAAAA(c client.RecordAPI, zone string, ttl int, ips []string) error {
+	return SetApexRecords(c, zone, types.TypeAAAA, ttl, ips)
+}
+
+// SetApexALIAS Points the apex of zone at target with an ALIAS record, the PowerDNS
+// alternative to a CNAME at the apex. The server must have ALIAS expansion enabled.
+func SetApexALIAS(c client.RecordAPI, zone string, ttl int, target string) error {
+	return SetApexRecords(c, zone, types.TypeALIAS, ttl, []string{target})
+}
@@ -0,0 +1,144 @@
+package ops
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// ErrConflict Matched by the error of CheckConflicts when the proposed rrsets conflict.
+var ErrConflict = errors.New("record sets conflict")
+
+// Conflict Problem found in a proposed rrset that the server would reject, or that would
+// leave the zone serving data it cannot answer with.
+type Conflict struct {
+	Key    RecordKey
+	Reason string
+}
+
+func (conflict Conflict) String() string {
+	return fmt.Sprintf("%s %s: %s", conflict.Key.Name, conflict.Key.Type, conflict.Reason)
+}
+
+// ConflictError Error returned by CheckConflicts listing every conflict found.
+type ConflictError struct {
+	Zone      string
+	Conflicts []Conflict
+}
+
+func (err *ConflictError) Error() string {
+	reasons := make([]string, len(err.Conflicts))
+	for i, conflict := range err.Conflicts {
+		reasons[i] = conflict.String()
+	}
+
+	return fmt.Sprintf("Error checking record sets of zone: %s, %d conflicts: %s", err.Zone, len(reasons), strings.Join(reasons, "; "))
+}
+
+// Is Makes errors.Is(err, ErrConflict) match.
+func (err *ConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// CheckConflicts Checks locally, before submitting, that applying proposed to the live
+// rrsets of zone does not put a CNAME next to other data, repeat a record content within
+// an rrset, or add data other than glue below a delegation. Only conflicts involving a
+// proposed rrset are reported, as a *ConflictError.
+func CheckConflicts(c client.RecordAPI, zone string, proposed []types.ResourceRecordSet) error {
+	live, err := c.ListRecordsAsRRSet(zone)
+	if err != nil {
+		return err
+	}
+
+	if conflicts := FindConflicts(zone, live, proposed); len(conflicts) > 0 {
+		return &ConflictError{Zone: zone, Conflicts: conflicts}
+	}
+
+	return nil
+}
+
+// FindConflicts Returns the conflicts CheckConflicts reports for proposed applied on top
+// of live, without contacting the server.
+func FindConflicts(zone string, live []types.ResourceRecordSet, proposed []types.ResourceRecordSet) []Conflict {
+	merged := make(map[RecordKey]*types.ResourceRecordSet, len(live)+len(proposed))
+	for i := range live {
+		if !live[i].Empty() {
+			merged[diffKey(&live[i])] = &live[i]
+		}
+	}
+
+	touched := make(map[RecordKey]bool, len(proposed))
+	for i := range proposed {
+		key := diffKey(&proposed[i])
+		if proposed[i].ChangeType == types.ChangeTypeDelete || proposed[i].Empty() {
+			delete(merged, key)
+			continue
+		}
+		merged[key] = &proposed[i]
+		touched[key] = true
+	}
+
+	byName := make(map[string][]types.RecordType)
+	var delegations []string
+	for key := range merged {
+		byName[key.Name] = append(byName[key.Name], key.Type)
+		if key.Type == types.TypeNS && !types.EqualNames(key.Name, zone) {
+			delegations = append(delegations, key.Name)
+		}
+	}
+
+	var conflicts []Conflict
+	for key := range touched {
+		rrSet := merged[key]
+
+		seen := make(map[string]bool, len(rrSet.Records))
+		for _, record := range rrSet.Records {
+			if seen[record.Content] {
+				conflicts = append(conflicts, Conflict{Key: key, Reason: fmt.Sprintf("duplicate content %q", record.Content)})
+			}
+			seen[record.Content] = true
+		}
+
+		for _, other := range byName[key.Name] {
+			if other == key.Type || isDNSSECType(other) || isDNSSECType(key.Type) {
+				continue
+			}
+			if key.Type == types.TypeCNAME || other == types.TypeCNAME {
+				conflicts = append(conflicts, Conflict{Key: key, Reason: fmt.Sprintf("CNAME and %s at the same name", otherType(key.Type, other))})
+			}
+		}
+
+		if key.Type == types.TypeA || key.Type == types.TypeAAAA {
+			continue
+		}
+		for _, delegation := range delegations {
+			if !types.EqualNames(key.Name, delegation) && types.IsSubdomainOf(key.Name, delegation) {
+				conflicts = append(conflicts, Conflict{Key: key, Reason: fmt.Sprintf("below the delegation at %s", delegation)})
+			}
+		}
+	}
+
+	sort.Slice(conflicts, func(i, j int) bool {
+		return conflicts[i].String() < conflicts[j].String()
+	})
+
+	return conflicts
+}
+
+// Types PowerDNS allows next to a CNAME, since they sign it
+func isDNSSECType(tpe types.RecordType) bool {
+	return tpe == types.TypeRRSIG || tpe == types.TypeNSEC || tpe == types.TypeNSEC3
+}
+
+// Returns whichever of a and b is not the CNAME
+func otherType(a types.RecordType, b types.RecordType) types.RecordType {
+	if a == types.TypeCNAME {
+		return b
+	}
+
+	return a
+}
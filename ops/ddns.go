@@ -0,0 +1,144 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// Default endpoints answering with the caller's public address as plain text.
+const (
+	DefaultIPv4Endpoint = "https://api.ipify.org"
+	DefaultIPv6Endpoint = "https://api6.ipify.org"
+)
+
+// DefaultDDNSTTL TTL of dynamic address records created by DDNS.
+const DefaultDDNSTTL = 60
+
+// DDNS Keeps the A/AAAA records of a name pointed at the public address of the host,
+// like ddclient does for other providers.
+type DDNS struct {
+	Client client.RecordAPI
+	Zone   string
+	TTL    int
+
+	// IPv4Endpoint and IPv6Endpoint answer with the caller's address as plain text.
+	IPv4Endpoint string
+	IPv6Endpoint string
+	Timeout      time.Duration
+}
+
+// NewDDNS Returns a DDNS updater for zone using the default address endpoints.
+func NewDDNS(c client.RecordAPI, zone string) *DDNS {
+	return &DDNS{
+		Client:       c,
+		Zone:         zone,
+		TTL:          DefaultDDNSTTL,
+		IPv4Endpoint: DefaultIPv4Endpoint,
+		IPv6Endpoint: DefaultIPv6Endpoint,
+		Timeout:      10 * time.Second,
+	}
+}
+
+// UpdateMyIP Detects the public IPv4 and/or IPv6 address of the host and updates the A
+// and AAAA records of name when they differ. It returns true if any record changed.
+func (ddns *DDNS) UpdateMyIP(name string, v4 bool, v6 bool) (bool, error) {
+	changed := false
+
+	if v4 {
+		ip, err := ddns.DetectIP("tcp4")
+		if err != nil {
+			return changed, err
+		}
+		updated, err := ddns.update(name, types.TypeA, ip)
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || updated
+	}
+
+	if v6 {
+		ip, err := ddns.DetectIP("tcp6")
+		if err != nil {
+			return changed, err
+		}
+		updated, err := ddns.update(name, types.TypeAAAA, ip)
+		if err != nil {
+			return changed, err
+		}
+		changed = changed || updated
+	}
+
+	return changed, nil
+}
+
+// DetectIP Returns the public address of the host as seen over network "tcp4" or "tcp6".
+func (ddns *DDNS) DetectIP(network string) (string, error) {
+	endpoint := ddns.IPv4Endpoint
+	if network == "tcp6" {
+		endpoint = ddns.IPv6Endpoint
+	}
+
+	dialer := &net.Dialer{Timeout: ddns.Timeout}
+	httpClient := &http.Client{
+		Timeout: ddns.Timeout,
+		Transport: &http.Transport{
+			Proxy: http.ProxyFromEnvironment,
+			DialContext: func(ctx context.Context, _ string, addr string) (net.Conn, error) {
+				return dialer.DialContext(ctx, network, addr)
+			},
+		},
+	}
+
+	resp, err := httpClient.Get(endpoint)
+	if err != nil {
+		return "", fmt.Errorf("Error detecting public address over %s: %w", network, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return "", fmt.Errorf("Error detecting public address over %s, status: %d", network, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 256))
+	if err != nil {
+		return "", err
+	}
+
+	ip := net.ParseIP(strings.TrimSpace(string(body)))
+	if ip == nil || (network == "tcp4") != (ip.To4() != nil) {
+		return "", fmt.Errorf("Invalid %s address from %s: %q", network, endpoint, strings.TrimSpace(string(body)))
+	}
+
+	return ip.String(), nil
+}
+
+func (ddns *DDNS) update(name string, tpe types.RecordType, ip string) (bool, error) {
+	current, err := ddns.Client.GetRecordSet(ddns.Zone, name, string(tpe))
+	if err != nil {
+		return false, err
+	}
+
+	if current != nil && len(current.Records) == 1 && current.Records[0].Content == ip && !current.Records[0].Disabled {
+		return false, nil
+	}
+
+	_, err = ddns.Client.ReplaceRecordSet(ddns.Zone, types.ResourceRecordSet{
+		Name:    name,
+		Type:    tpe,
+		TTL:     ddns.TTL,
+		Records: []types.Record{{Content: ip}},
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
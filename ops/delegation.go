@@ -0,0 +1,233 @@
+package ops
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+	"github.com/miekg/dns"
+)
+
+// DelegationTimeout bounds a single query of CheckDelegation.
+var DelegationTimeout = 5 * time.Second
+
+// DelegationReport Consistency of the delegation of a zone: the NS records of the parent,
+// the NS records at the apex and the answers of every name server.
+type DelegationReport struct {
+	Zone string
+	// Serial is the serial the server reports for the zone, after SOA-EDIT.
+	Serial int64
+	// ZoneNS are the apex NS records as stored in PowerDNS.
+	ZoneNS []string
+	// ParentNS are the NS records of the delegation in the parent zone.
+	ParentNS []string
+	Servers  []NameserverCheck
+	// Problems lists every inconsistency found; it is empty for a healthy delegation.
+	Problems []string
+}
+
+// NameserverCheck Answers of one address of a name server.
+type NameserverCheck struct {
+	Name    string
+	Address string
+	// Lame is true when the server does not answer authoritatively for the zone.
+	Lame   bool
+	Serial int64
+	NS     []string
+	Err    error
+}
+
+// Healthy Returns true when no problem was found.
+func (report *DelegationReport) Healthy() bool {
+	return len(report.Problems) == 0
+}
+
+func (report *DelegationReport) problem(format string, args ...interface{}) {
+	report.Problems = append(report.Problems, fmt.Sprintf(format, args...))
+}
+
+// CheckDelegation Queries the parent name servers of zone and every name server listed at
+// the parent or at the apex, comparing their answers with the zone as stored in
+// PowerDNS. It reports NS sets that differ between parent and zone, lame name servers,
+// name servers serving other NS records and serials differing from the stored one. An
+// error is only returned when the zone or its parent could not be read at all.
+func CheckDelegation(c client.ZoneAPI, zone string) (*DelegationReport, error) {
+	zone = dns.Fqdn(zone)
+
+	zoneInfo, err := c.GetZone(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DelegationReport{Zone: zone, Serial: zoneInfo.Serial}
+	if zoneInfo.EditedSerial != 0 {
+		report.Serial = zoneInfo.EditedSerial
+	}
+	for _, rrSet := range zoneInfo.ResourceRecordSets {
+		if rrSet.Type == types.TypeNS && types.EqualNames(rrSet.Name, zone) {
+			for _, record := range rrSet.Records {
+				if !record.Disabled {
+					report.ZoneNS = append(report.ZoneNS, strings.ToLower(dns.Fqdn(record.Content)))
+				}
+			}
+		}
+	}
+	sort.Strings(report.ZoneNS)
+
+	if report.ParentNS, err = parentNS(zone); err != nil {
+		return nil, err
+	}
+
+	if missing, extra := compareNS(report.ZoneNS, report.ParentNS); len(missing)+len(extra) > 0 {
+		report.problem("parent NS set differs from zone NS set, only at parent: %v, only in zone: %v", extra, missing)
+	}
+
+	seen := make(map[string]bool)
+	for _, ns := range append(append([]string(nil), report.ParentNS...), report.ZoneNS...) {
+		if seen[ns] {
+			continue
+		}
+		seen[ns] = true
+
+		addresses, err := net.LookupHost(strings.TrimSuffix(ns, "."))
+		if err != nil {
+			report.Servers = append(report.Servers, NameserverCheck{Name: ns, Lame: true, Err: err})
+			report.problem("%s does not resolve: %s", ns, err)
+			continue
+		}
+
+		for _, address := range addresses {
+			check := checkNameserverAnswers(zone, ns, address)
+			report.Servers = append(report.Servers, check)
+
+			switch {
+			case check.Err != nil:
+				report.problem("%s (%s) did not answer: %s", ns, address, check.Err)
+			case check.Lame:
+				report.problem("%s (%s) is lame, it does not answer authoritatively", ns, address)
+			default:
+				if check.Serial != report.Serial {
+					report.problem("%s (%s) serves serial %d, expected %d", ns, address, check.Serial, report.Serial)
+				}
+				if missing, extra := compareNS(report.ZoneNS, check.NS); len(missing)+len(extra) > 0 {
+					report.problem("%s (%s) serves NS set %v, expected %v", ns, address, check.NS, report.ZoneNS)
+				}
+			}
+		}
+	}
+
+	return report, nil
+}
+
+// Returns the NS records of the delegation of zone, asking the name servers of the
+// closest enclosing zone found through the system resolver
+func parentNS(zone string) ([]string, error) {
+	labels := dns.SplitDomainName(zone)
+	for i := 1; i < len(labels); i++ {
+		parent := dns.Fqdn(strings.Join(labels[i:], "."))
+		servers, err := net.LookupNS(parent)
+		if err != nil || len(servers) == 0 {
+			continue
+		}
+
+		var lastErr error
+		for _, server := range servers {
+			resp, err := queryAuthoritative(server.Host, zone, dns.TypeNS)
+			if err != nil {
+				lastErr = err
+				continue
+			}
+
+			var ns []string
+			for _, rr := range append(resp.Answer, resp.Ns...) {
+				if record, ok := rr.(*dns.NS); ok && strings.EqualFold(record.Hdr.Name, zone) {
+					ns = append(ns, strings.ToLower(record.Ns))
+				}
+			}
+			sort.Strings(ns)
+			return ns, nil
+		}
+
+		return nil, fmt.Errorf("Error querying the parent name servers of %s: %w", zone, lastErr)
+	}
+
+	return nil, fmt.Errorf("Error querying the parent name servers of %s: no parent zone found", zone)
+}
+
+// Queries the SOA and NS records of zone at address
+func checkNameserverAnswers(zone string, ns string, address string) NameserverCheck {
+	check := NameserverCheck{Name: ns, Address: address}
+
+	resp, err := queryAuthoritative(address, zone, dns.TypeSOA)
+	if err != nil {
+		check.Err = err
+		return check
+	}
+	if !resp.Authoritative || resp.Rcode != dns.RcodeSuccess {
+		check.Lame = true
+		return check
+	}
+	for _, rr := range resp.Answer {
+		if soa, ok := rr.(*dns.SOA); ok {
+			check.Serial = int64(soa.Serial)
+		}
+	}
+
+	if resp, err = queryAuthoritative(address, zone, dns.TypeNS); err != nil {
+		check.Err = err
+		return check
+	}
+	for _, rr := range resp.Answer {
+		if record, ok := rr.(*dns.NS); ok {
+			check.NS = append(check.NS, strings.ToLower(record.Ns))
+		}
+	}
+	sort.Strings(check.NS)
+
+	return check
+}
+
+// Sends a non-recursive query to server, over TCP when the UDP answer is truncated
+func queryAuthoritative(server string, name string, qtype uint16) (*dns.Msg, error) {
+	query := new(dns.Msg)
+	query.SetQuestion(dns.Fqdn(name), qtype)
+	query.RecursionDesired = false
+
+	address := net.JoinHostPort(strings.TrimSuffix(server, "."), "53")
+	resp, _, err := (&dns.Client{Timeout: DelegationTimeout}).Exchange(query, address)
+	if err == nil && resp.Truncated {
+		resp, _, err = (&dns.Client{Net: "tcp", Timeout: DelegationTimeout}).Exchange(query, address)
+	}
+
+	return resp, err
+}
+
+// Returns the names of expected missing from actual, and the extra ones
+func compareNS(expected []string, actual []string) ([]string, []string) {
+	want := make(map[string]bool, len(expected))
+	for _, ns := range expected {
+		want[ns] = true
+	}
+	have := make(map[string]bool, len(actual))
+	for _, ns := range actual {
+		have[ns] = true
+	}
+
+	var missing, extra []string
+	for _, ns := range expected {
+		if !have[ns] {
+			missing = append(missing, ns)
+		}
+	}
+	for _, ns := range actual {
+		if !want[ns] {
+			extra = append(extra, ns)
+		}
+	}
+
+	return missing, extra
+}
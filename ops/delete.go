@@ -0,0 +1,115 @@
+package ops
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// DefaultDeleteBatchSize Number of rrsets DeleteMatching deletes per PATCH by default.
+const DefaultDeleteBatchSize = 100
+
+// ErrDeleteAborted Returned by DeleteMatching when the confirmation callback declined.
+var ErrDeleteAborted = errors.New("deletion aborted")
+
+// DeleteOptions Controls DeleteMatching.
+type DeleteOptions struct {
+	// Confirm is called with every matching rrset before anything is deleted. Returning
+	// false aborts with ErrDeleteAborted. When nil, matches are deleted unconfirmed.
+	Confirm func(matches []types.ResourceRecordSet) bool
+	// BatchSize bounds the number of rrsets deleted per PATCH.
+	BatchSize int
+	// Interval is waited between two batches, to spread the load on the server.
+	Interval time.Duration
+}
+
+// DeleteMatching Deletes the rrsets of zone whose name matches pattern, a path.Match
+// pattern such as "*.old-env.example.com", and whose type is one of rtypes, or any
+// type when rtypes is empty. The SOA and apex NS rrsets are never deleted. It returns
+// the keys of the deleted rrsets, including those of the batches that succeeded when
+// a later one failed.
+func DeleteMatching(c client.RecordAPI, zone string, pattern string, rtypes []types.RecordType, opts DeleteOptions) ([]RecordKey, error) {
+	pattern = strings.ToLower(strings.TrimSuffix(pattern, "."))
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("Invalid pattern: %q, %w", pattern, err)
+	}
+
+	rrSets, err := c.ListRecordsAsRRSet(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []types.ResourceRecordSet
+	for _, rrSet := range rrSets {
+		if rrSet.Type == types.TypeSOA || (rrSet.Type == types.TypeNS && types.EqualNames(rrSet.Name, zone)) {
+			continue
+		}
+		if !matchesType(rrSet.Type, rtypes) {
+			continue
+		}
+		if matched, _ := path.Match(pattern, strings.ToLower(strings.TrimSuffix(rrSet.Name, "."))); matched {
+			matches = append(matches, rrSet)
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	if opts.Confirm != nil && !opts.Confirm(matches) {
+		return nil, ErrDeleteAborted
+	}
+
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultDeleteBatchSize
+	}
+
+	var deleted []RecordKey
+	for start := 0; start < len(matches); start += batchSize {
+		if start > 0 && opts.Interval > 0 {
+			time.Sleep(opts.Interval)
+		}
+
+		end := start + batchSize
+		if end > len(matches) {
+			end = len(matches)
+		}
+
+		changes := make([]types.ResourceRecordSet, 0, end-start)
+		for _, rrSet := range matches[start:end] {
+			changes = append(changes, types.ResourceRecordSet{
+				Name:       rrSet.Name,
+				Type:       rrSet.Type,
+				ChangeType: types.ChangeTypeDelete,
+			})
+		}
+		if err = c.PatchRecordSets(zone, changes); err != nil {
+			return deleted, err
+		}
+
+		for _, rrSet := range matches[start:end] {
+			deleted = append(deleted, Key(&rrSet))
+		}
+	}
+
+	return deleted, nil
+}
+
+func matchesType(tpe types.RecordType, rtypes []types.RecordType) bool {
+	if len(rtypes) == 0 {
+		return true
+	}
+
+	for _, t := range rtypes {
+		if t == tpe {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,98 @@
+package ops
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+// ChangeKind Kind of change needed to bring a live rrset to its desired state.
+type ChangeKind string
+
+// Kinds of change reported by Diff.
+const (
+	ChangeCreate ChangeKind = "create"
+	ChangeUpdate ChangeKind = "update"
+	ChangeDelete ChangeKind = "delete"
+)
+
+// Change Difference between the desired and live state of one rrset. Desired is nil
+// for deletions and Live is nil for creations.
+type Change struct {
+	Kind    ChangeKind
+	Desired *types.ResourceRecordSet
+	Live    *types.ResourceRecordSet
+}
+
+// Key Returns the RecordKey of the rrset the change applies to.
+func (change Change) Key() RecordKey {
+	if change.Desired != nil {
+		return Key(change.Desired)
+	}
+
+	return Key(change.Live)
+}
+
+// RecordSet Returns the record set to PATCH to apply the change.
+func (change Change) RecordSet() types.ResourceRecordSet {
+	if change.Kind == ChangeDelete {
+		return types.ResourceRecordSet{
+			Name:       change.Live.Name,
+			Type:       change.Live.Type,
+			ChangeType: types.ChangeTypeDelete,
+		}
+	}
+
+	rrSet := *change.Desired
+	rrSet.ChangeType = types.ChangeTypeReplace
+	return rrSet
+}
+
+// Diff Returns the changes turning live into desired, sorted by name and type. Rrsets
+// are compared on TTL and records; comments are ignored. The SOA rrset is only compared
+// when desired contains one, since PowerDNS manages its serial.
+func Diff(desired []types.ResourceRecordSet, live []types.ResourceRecordSet) []Change {
+	wanted := make(map[RecordKey]*types.ResourceRecordSet, len(desired))
+	manageSOA := false
+	for i := range desired {
+		wanted[diffKey(&desired[i])] = &desired[i]
+		manageSOA = manageSOA || desired[i].Type == types.TypeSOA
+	}
+
+	var changes []Change
+	seen := make(map[RecordKey]bool, len(live))
+	for i := range live {
+		key := diffKey(&live[i])
+		seen[key] = true
+
+		want, ok := wanted[key]
+		switch {
+		case !ok && (live[i].Type != types.TypeSOA || manageSOA):
+			changes = append(changes, Change{Kind: ChangeDelete, Live: &live[i]})
+		case ok && !types.EqualContents(*want, live[i]):
+			changes = append(changes, Change{Kind: ChangeUpdate, Desired: want, Live: &live[i]})
+		}
+	}
+
+	for i := range desired {
+		if !seen[diffKey(&desired[i])] {
+			changes = append(changes, Change{Kind: ChangeCreate, Desired: &desired[i]})
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		a, b := changes[i].Key(), changes[j].Key()
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Type < b.Type
+	})
+
+	return changes
+}
+
+// Names are compared case-insensitively and with or without the trailing dot
+func diffKey(rrSet *types.ResourceRecordSet) RecordKey {
+	return RecordKey{Name: strings.ToLower(strings.TrimSuffix(rrSet.Name, ".") + "."), Type: rrSet.Type}
+}
@@ -0,0 +1,53 @@
+package ops
+
+import (
+	"fmt"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// DefaultDSTTL TTL used for DS record sets published in a parent zone.
+const DefaultDSTTL = 3600
+
+// ChildDS Returns the DS records of the active key signing keys of childZone.
+func ChildDS(child client.DNSSECAPI, childZone string) ([]string, error) {
+	keys, err := child.ListCryptokeys(childZone)
+	if err != nil {
+		return nil, err
+	}
+
+	var ds []string
+	for _, key := range keys {
+		if key.Active && key.IsKeySigningKey() {
+			ds = append(ds, key.DS...)
+		}
+	}
+
+	if len(ds) == 0 {
+		return nil, fmt.Errorf("Zone %s has no active key signing key", childZone)
+	}
+
+	return ds, nil
+}
+
+// PublishDS Reads the DS records of childZone's active key signing keys and replaces the
+// DS record set for childZone in parentZone. parent and child may point at the same server.
+func PublishDS(parent client.RecordAPI, parentZone string, child client.DNSSECAPI, childZone string) error {
+	ds, err := ChildDS(child, childZone)
+	if err != nil {
+		return err
+	}
+
+	rrSet := types.ResourceRecordSet{
+		Name: childZone,
+		Type: types.TypeDS,
+		TTL:  DefaultDSTTL,
+	}
+	for _, content := range ds {
+		rrSet.Records = append(rrSet.Records, types.Record{Content: content})
+	}
+
+	_, err = parent.ReplaceRecordSet(parentZone, rrSet)
+	return err
+}
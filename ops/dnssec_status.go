@@ -0,0 +1,142 @@
+package ops
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// DNSSECReport Signing health of a zone, combining the server state with the DS records
+// its parent serves.
+type DNSSECReport struct {
+	Zone         string
+	Signed       bool
+	ActiveKeys   []types.Cryptokey
+	InactiveKeys []types.Cryptokey
+	NSEC3Param   string
+	NSEC3Narrow  bool
+	// ExpectedDS are the DS records of the active key signing keys.
+	ExpectedDS []string
+	// ParentDS are the DS records served for the zone, as resolved over DNS.
+	ParentDS []string
+	// DSPublished is true when the parent serves at least one expected DS record.
+	DSPublished bool
+}
+
+// DNSSECStatus Returns the DNSSEC report of zone. The parent DS set is resolved through
+// the DNS-over-HTTPS resolver at dohURL, e.g. DoHCloudflare.
+func DNSSECStatus(c client.Provider, zone string, dohURL string) (*DNSSECReport, error) {
+	zoneInfo, err := c.GetZone(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &DNSSECReport{
+		Zone:        zone,
+		Signed:      zoneInfo.DNSSec,
+		NSEC3Param:  zoneInfo.NSEC3Param,
+		NSEC3Narrow: zoneInfo.NSEC3Narrow,
+	}
+
+	keys, err := c.ListCryptokeys(zone)
+	if err != nil {
+		return nil, err
+	}
+	for _, key := range keys {
+		if !key.Active {
+			report.InactiveKeys = append(report.InactiveKeys, key)
+			continue
+		}
+
+		report.ActiveKeys = append(report.ActiveKeys, key)
+		if key.IsKeySigningKey() {
+			report.ExpectedDS = append(report.ExpectedDS, key.DS...)
+		}
+	}
+
+	if report.ParentDS, err = QueryDoH(zone, types.TypeDS, dohURL); err != nil {
+		return nil, err
+	}
+
+	parent := make(map[string]bool, len(report.ParentDS))
+	for _, ds := range report.ParentDS {
+		parent[normalizeDS(ds)] = true
+	}
+	for _, ds := range report.ExpectedDS {
+		if parent[normalizeDS(ds)] {
+			report.DSPublished = true
+			break
+		}
+	}
+
+	return report, nil
+}
+
+// Normalizes DS content for comparison: digests are hex in any case, and PowerDNS and
+// resolvers may split them differently
+func normalizeDS(ds string) string {
+	fields := strings.Fields(strings.ToLower(ds))
+	if len(fields) < 4 {
+		return strings.Join(fields, " ")
+	}
+
+	return strings.Join(fields[:3], " ") + " " + strings.Join(fields[3:], "")
+}
+
+// ErrBrokenChainOfTrust Returned by VerifyChainOfTrust when the parent serves DS records
+// but none matches an active key signing key, so validating resolvers fail the zone.
+var ErrBrokenChainOfTrust = errors.New("no DS record at the parent matches an active key signing key")
+
+// ChainOfTrust Comparison of the DS records served by the parent of a zone with the DS
+// records of its active key signing keys.
+type ChainOfTrust struct {
+	Zone string
+	// Matching DS records are served by the parent and match an active key.
+	Matching []string
+	// Missing DS records belong to active keys but are not served by the parent, e.g.
+	// after a key signing key was added and PublishDS not run yet.
+	Missing []string
+	// Stale DS records are served by the parent but match no active key.
+	Stale []string
+}
+
+// VerifyChainOfTrust Compares the DS set served for zone over DNS, resolved through the
+// DNS-over-HTTPS resolver at dohURL, with the DS records of the active key signing keys
+// of zone. It returns ErrBrokenChainOfTrust, with the comparison, when the parent serves
+// DS records and none of them matches; an unsigned delegation without DS is not an error.
+func VerifyChainOfTrust(c client.Provider, zone string, dohURL string) (*ChainOfTrust, error) {
+	report, err := DNSSECStatus(c, zone, dohURL)
+	if err != nil {
+		return nil, err
+	}
+
+	chain := &ChainOfTrust{Zone: zone}
+
+	expected := make(map[string]bool, len(report.ExpectedDS))
+	for _, ds := range report.ExpectedDS {
+		expected[normalizeDS(ds)] = true
+	}
+	parent := make(map[string]bool, len(report.ParentDS))
+	for _, ds := range report.ParentDS {
+		parent[normalizeDS(ds)] = true
+		if expected[normalizeDS(ds)] {
+			chain.Matching = append(chain.Matching, ds)
+		} else {
+			chain.Stale = append(chain.Stale, ds)
+		}
+	}
+	for _, ds := range report.ExpectedDS {
+		if !parent[normalizeDS(ds)] {
+			chain.Missing = append(chain.Missing, ds)
+		}
+	}
+
+	if len(report.ParentDS) > 0 && len(chain.Matching) == 0 {
+		return chain, fmt.Errorf("Error verifying chain of trust of zone: %s, %w", zone, ErrBrokenChainOfTrust)
+	}
+
+	return chain, nil
+}
@@ -0,0 +1,4 @@
+// Package ops contains higher level operations built on top of the PowerDNS
+// client, such as record synchronisation, ensure-style helpers and DNSSEC
+// workflows. Functions in this package only use the exported client API.
+package ops
@@ -0,0 +1,124 @@
+package ops
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dmportella/powerdns/types"
+	"github.com/miekg/dns"
+)
+
+// Public DNS-over-HTTPS resolvers accepting RFC 8484 queries.
+const (
+	DoHCloudflare = "https://cloudflare-dns.com/dns-query"
+	DoHGoogle     = "https://dns.google/dns-query"
+)
+
+// DoHTimeout bounds a single DNS-over-HTTPS query.
+var DoHTimeout = 10 * time.Second
+
+// QueryDoH Resolves name and type through the RFC 8484 DNS-over-HTTPS resolver at dohURL
+// and returns the contents of the matching answer records.
+func QueryDoH(name string, rtype types.RecordType, dohURL string) ([]string, error) {
+	qtype, ok := dns.StringToType[string(rtype)]
+	if !ok {
+		return nil, fmt.Errorf("Unknown record type: %q", string(rtype))
+	}
+
+	query := new(dns.Msg)
+	query.SetQuestion(dns.Fqdn(name), qtype)
+	query.Id = 0 // RFC 8484 recommends ID 0 for cache friendliness
+
+	packed, err := query.Pack()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", dohURL, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := (&http.Client{Timeout: DoHTimeout}).Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("Error querying %s: %w", dohURL, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("Error querying %s, status: %d", dohURL, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 65535))
+	if err != nil {
+		return nil, err
+	}
+
+	answer := new(dns.Msg)
+	if err = answer.Unpack(body); err != nil {
+		return nil, fmt.Errorf("Invalid DNS answer from %s: %w", dohURL, err)
+	}
+
+	if answer.Rcode != dns.RcodeSuccess && answer.Rcode != dns.RcodeNameError {
+		return nil, fmt.Errorf("Error resolving %s %s via %s: %s", name, rtype, dohURL, dns.RcodeToString[answer.Rcode])
+	}
+
+	var contents []string
+	for _, rr := range answer.Answer {
+		if rr.Header().Rrtype == qtype && strings.EqualFold(rr.Header().Name, dns.Fqdn(name)) {
+			contents = append(contents, rrContent(rr))
+		}
+	}
+
+	return contents, nil
+}
+
+// VerifyViaDoH Checks through a DNS-over-HTTPS resolver that name and type resolve to
+// exactly the expected contents, in any order. It is useful in CI environments where
+// direct port 53 queries are blocked.
+func VerifyViaDoH(name string, rtype types.RecordType, expected []string, dohURL string) error {
+	actual, err := QueryDoH(name, rtype, dohURL)
+	if err != nil {
+		return err
+	}
+
+	want := make([]string, len(expected))
+	for i, content := range expected {
+		want[i] = canonicalContent(name, rtype, content)
+	}
+
+	got := make([]string, len(actual))
+	for i, content := range actual {
+		got[i] = strings.ToLower(content)
+	}
+
+	sort.Strings(want)
+	sort.Strings(got)
+	if strings.Join(want, "\n") != strings.Join(got, "\n") {
+		return fmt.Errorf("Record %s %s resolves to %q via %s, expected %q", name, rtype, actual, dohURL, expected)
+	}
+
+	return nil
+}
+
+// Returns the content of rr in presentation format, without the RR header
+func rrContent(rr dns.RR) string {
+	return strings.TrimSpace(strings.TrimPrefix(rr.String(), rr.Header().String()))
+}
+
+// Normalizes content the way it comes back in DNS answers, for comparison
+func canonicalContent(name string, rtype types.RecordType, content string) string {
+	rr, err := dns.NewRR(fmt.Sprintf("%s 0 IN %s %s", dns.Fqdn(name), rtype, content))
+	if err != nil || rr == nil {
+		return strings.ToLower(content)
+	}
+
+	return strings.ToLower(rrContent(rr))
+}
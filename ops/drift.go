@@ -0,0 +1,85 @@
+package ops
+
+import (
+	"context"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// NewDriftGauge Returns the gauge DriftDetector reports to, labelled by zone and change
+// kind. It must be registered by the caller, e.g. with prometheus.MustRegister.
+func NewDriftGauge() *prometheus.GaugeVec {
+	return prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "powerdns",
+		Name:      "zone_drift_rrsets",
+		Help:      "Number of rrsets whose live state differs from the zone spec.",
+	}, []string{"zone", "kind"})
+}
+
+// DriftDetector Compares zone specs against the live zones on a fixed interval, to detect
+// DNS changes made outside of the repository holding the specs.
+type DriftDetector struct {
+	Client client.RecordAPI
+	// SpecFiles are re-read on every check, so spec updates are picked up.
+	SpecFiles []string
+	Interval  time.Duration
+	// Gauge, when set, receives the drift counts per zone and change kind.
+	Gauge *prometheus.GaugeVec
+	// OnDrift, when set, is called with the changes found in a zone, including none.
+	OnDrift func(zone string, changes []Change)
+	// OnError, when set, is called when a spec could not be checked.
+	OnError func(specFile string, err error)
+}
+
+// Run Checks every spec immediately and then on each interval until ctx is done.
+func (detector *DriftDetector) Run(ctx context.Context) error {
+	ticker := time.NewTicker(detector.Interval)
+	defer ticker.Stop()
+
+	for {
+		for _, specFile := range detector.SpecFiles {
+			if err := detector.Check(specFile); err != nil && detector.OnError != nil {
+				detector.OnError(specFile, err)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check Compares one spec against its live zone and reports the drift.
+func (detector *DriftDetector) Check(specFile string) error {
+	spec, err := LoadZoneSpec(specFile)
+	if err != nil {
+		return err
+	}
+
+	live, err := detector.Client.ListRecordsAsRRSet(spec.Zone)
+	if err != nil {
+		return err
+	}
+
+	changes := Diff(spec.RecordSets, live)
+
+	if detector.Gauge != nil {
+		counts := map[ChangeKind]int{ChangeCreate: 0, ChangeUpdate: 0, ChangeDelete: 0}
+		for _, change := range changes {
+			counts[change.Kind]++
+		}
+		for kind, count := range counts {
+			detector.Gauge.WithLabelValues(spec.Zone, string(kind)).Set(float64(count))
+		}
+	}
+
+	if detector.OnDrift != nil {
+		detector.OnDrift(spec.Zone, changes)
+	}
+
+	return nil
+}
@@ -0,0 +1,302 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+	"golang.org/x/sync/errgroup"
+)
+
+// EnsureBatchSize Maximum number of rrsets EnsureRecords sends in one PATCH.
+var EnsureBatchSize = 50
+
+// ErrNotOwner Returned when an rrset exists but is not owned by the caller, so it is
+// left untouched rather than taken over.
+var ErrNotOwner = errors.New("record set exists and is not owned by this owner")
+
+// EnsureRecordSet Makes the rrset of rrSet's name and type in zone hold the records and
+// TTL of rrSet, tagged as owned by ownerID. Nothing is written when it already does.
+// An existing rrset without owner or owned by someone else is refused with ErrNotOwner.
+// The name of rrSet may be relative to zone. It returns true when the rrset was written.
+func EnsureRecordSet(c client.RecordAPI, zone string, rrSet types.ResourceRecordSet, ownerID string) (bool, error) {
+	if ownerID == "" {
+		return false, fmt.Errorf("Error ensuring record set: %s, an owner ID is required", rrSet.ID())
+	}
+	rrSet.Name = types.QualifyName(rrSet.Name, zone)
+
+	current, err := c.GetRecordSet(zone, rrSet.Name, string(rrSet.Type))
+	if err != nil {
+		return false, err
+	}
+
+	if current != nil && !current.Empty() {
+		if owner := Owner(current); owner != ownerID {
+			return false, fmt.Errorf("Error ensuring record set: %s, owner: %q, %w", rrSet.ID(), owner, ErrNotOwner)
+		}
+		if types.EqualContents(*current, rrSet) {
+			return false, nil
+		}
+		// keep comments, e.g. tags, added by others
+		rrSet.Comments = current.Comments
+	}
+
+	SetOwner(&rrSet, ownerID)
+	if _, err = c.ReplaceRecordSet(zone, rrSet); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// EnsureError Errors of the rrsets EnsureRecords could not apply, by rrset.
+type EnsureError map[RecordKey]error
+
+func (err EnsureError) Error() string {
+	keys := make([]RecordKey, 0, len(err))
+	for key := range err {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Name != keys[j].Name {
+			return keys[i].Name < keys[j].Name
+		}
+		return keys[i].Type < keys[j].Type
+	})
+
+	messages := make([]string, len(keys))
+	for i, key := range keys {
+		messages[i] = fmt.Sprintf("%s %s: %s", key.Name, key.Type, err[key])
+	}
+
+	return fmt.Sprintf("Error ensuring %d record sets: %s", len(keys), strings.Join(messages, "; "))
+}
+
+// EnsureOptions Controls EnsureRecordsContext.
+type EnsureOptions struct {
+	// Concurrency is the number of PATCHes in flight, at least 1.
+	Concurrency int
+	// OnProgress, when set, is called after each batch, one call at a time.
+	OnProgress func(EnsureProgress)
+	// Owner, when set, applies the ownership rule of EnsureRecordSet: written rrsets are
+	// tagged as owned by Owner, and existing rrsets without owner or owned by someone
+	// else fail with ErrNotOwner. When empty, ownership is neither checked nor recorded.
+	Owner string
+}
+
+// EnsureProgress Progress of EnsureRecordsContext, counted in rrsets to write.
+type EnsureProgress struct {
+	Total          int
+	Processed      int
+	Written        int
+	Failed         int
+	Batches        int
+	BatchesApplied int
+	Elapsed        time.Duration
+	// ETA extrapolates the remaining time from the rate so far.
+	ETA time.Duration
+}
+
+// EnsureSummary Outcome of EnsureRecordsContext.
+type EnsureSummary struct {
+	Written   int
+	Unchanged int
+	Failed    EnsureError
+	// Pending rrsets were not sent because the run was cancelled.
+	Pending []RecordKey
+}
+
+// EnsureRecords Makes the rrsets of zone hold the records and TTL of rrSets, sending
+// only those that differ from the live zone. Rrsets are grouped by name, so that
+// conflicting types such as a CNAME and other data always share a PATCH, and batches of
+// up to EnsureBatchSize rrsets are sent with at most concurrency PATCHes in flight. When
+// a batch fails its rrsets are retried one by one, so the returned EnsureError names the
+// rrsets that actually failed. Names may be relative to zone; they are qualified before
+// being compared and written, and errors are keyed by the qualified name. It returns the
+// number of rrsets written. Unlike EnsureRecordSet it ignores ownership; set
+// EnsureOptions.Owner to enforce it.
+func EnsureRecords(c client.RecordAPI, zone string, rrSets []types.ResourceRecordSet, concurrency int) (int, error) {
+	summary, err := EnsureRecordsContext(context.Background(), c, zone, rrSets, EnsureOptions{Concurrency: concurrency})
+	if summary == nil {
+		return 0, err
+	}
+
+	return summary.Written, err
+}
+
+// EnsureRecordsContext Works like EnsureRecords, reporting progress to opts.OnProgress
+// and checking ownership when opts.Owner is set.
+// When ctx is done no further PATCH is sent, PATCHes in flight are cancelled, and the
+// summary lists the rrsets not sent or cancelled as pending along with ctx.Err(). A
+// cancelled PATCH may still have been applied by the server.
+func EnsureRecordsContext(ctx context.Context, c client.RecordAPI, zone string, rrSets []types.ResourceRecordSet, opts EnsureOptions) (*EnsureSummary, error) {
+	live, err := client.RecordAPIWithContext(ctx, c).ListRecordsAsRRSet(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[RecordKey]*types.ResourceRecordSet, len(live))
+	for i := range live {
+		current[diffKey(&live[i])] = &live[i]
+	}
+
+	summary := &EnsureSummary{Failed: EnsureError{}}
+	wanted := make(map[RecordKey]bool, len(rrSets))
+	var names []string
+	byName := make(map[string][]types.ResourceRecordSet)
+	for _, rrSet := range rrSets {
+		rrSet.Name = types.QualifyName(rrSet.Name, zone)
+		key := diffKey(&rrSet)
+		if wanted[key] {
+			summary.Failed[Key(&rrSet)] = fmt.Errorf("record set listed more than once")
+			continue
+		}
+		wanted[key] = true
+
+		existing, ok := current[key]
+		if ok && opts.Owner != "" && !existing.Empty() {
+			if owner := Owner(existing); owner != opts.Owner {
+				summary.Failed[Key(&rrSet)] = fmt.Errorf("owner: %q, %w", owner, ErrNotOwner)
+				continue
+			}
+		}
+		if ok && types.EqualContents(*existing, rrSet) {
+			summary.Unchanged++
+			continue
+		}
+
+		if opts.Owner != "" {
+			if ok {
+				// keep comments, e.g. tags, added by others
+				rrSet.Comments = existing.Comments
+			}
+			SetOwner(&rrSet, opts.Owner)
+		}
+
+		rrSet.ChangeType = types.ChangeTypeReplace
+		if _, ok := byName[key.Name]; !ok {
+			names = append(names, key.Name)
+		}
+		byName[key.Name] = append(byName[key.Name], rrSet)
+	}
+
+	var batches [][]types.ResourceRecordSet
+	var batch []types.ResourceRecordSet
+	for _, name := range names {
+		if len(batch) > 0 && len(batch)+len(byName[name]) > EnsureBatchSize {
+			batches = append(batches, batch)
+			batch = nil
+		}
+		batch = append(batch, byName[name]...)
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	progress := EnsureProgress{Batches: len(batches)}
+	for _, batch := range batches {
+		progress.Total += len(batch)
+	}
+	start := time.Now()
+
+	var mutex sync.Mutex
+	var group errgroup.Group
+	group.SetLimit(concurrency)
+	for _, batch := range batches {
+		batch := batch
+		group.Go(func() error {
+			if ctx.Err() != nil {
+				mutex.Lock()
+				defer mutex.Unlock()
+				summary.Pending = append(summary.Pending, recordKeys(batch)...)
+				return nil
+			}
+
+			batchErrs, pending := ensureBatch(ctx, c, zone, batch)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			applied := len(batch) - len(batchErrs) - len(pending)
+			summary.Written += applied
+			summary.Pending = append(summary.Pending, pending...)
+			for key, err := range batchErrs {
+				summary.Failed[key] = err
+			}
+
+			progress.Processed += len(batch) - len(pending)
+			progress.Written += applied
+			progress.Failed += len(batchErrs)
+			progress.BatchesApplied++
+			progress.Elapsed = time.Since(start)
+			if progress.Processed > 0 {
+				progress.ETA = progress.Elapsed * time.Duration(progress.Total-progress.Processed) / time.Duration(progress.Processed)
+			}
+			if opts.OnProgress != nil {
+				opts.OnProgress(progress)
+			}
+			return nil
+		})
+	}
+	group.Wait()
+
+	if len(summary.Pending) > 0 {
+		return summary, fmt.Errorf("Error ensuring record sets in zone: %s, %d left pending: %w", zone, len(summary.Pending), ctx.Err())
+	}
+	if len(summary.Failed) > 0 {
+		return summary, summary.Failed
+	}
+
+	return summary, nil
+}
+
+// Patches batch, falling back to one rrset at a time to find the failing ones. Rrsets
+// not retried because ctx is done are returned as pending
+func ensureBatch(ctx context.Context, c client.RecordAPI, zone string, batch []types.ResourceRecordSet) (EnsureError, []RecordKey) {
+	c = client.RecordAPIWithContext(ctx, c)
+
+	err := c.PatchRecordSets(zone, batch)
+	if err == nil {
+		return nil, nil
+	}
+	if ctx.Err() != nil {
+		return nil, recordKeys(batch)
+	}
+	if len(batch) == 1 {
+		return EnsureError{Key(&batch[0]): err}, nil
+	}
+
+	failed := EnsureError{}
+	for i := range batch {
+		if ctx.Err() != nil {
+			return failed, recordKeys(batch[i:])
+		}
+		if err := c.PatchRecordSets(zone, batch[i:i+1]); err != nil {
+			if ctx.Err() != nil {
+				return failed, recordKeys(batch[i:])
+			}
+			failed[Key(&batch[i])] = err
+		}
+	}
+
+	return failed, nil
+}
+
+func recordKeys(rrSets []types.ResourceRecordSet) []RecordKey {
+	keys := make([]RecordKey, len(rrSets))
+	for i := range rrSets {
+		keys[i] = Key(&rrSets[i])
+	}
+
+	return keys
+}
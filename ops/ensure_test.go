@@ -0,0 +1,233 @@
+package ops
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dmportella/powerdns/memory"
+	"github.com/dmportella/powerdns/types"
+)
+
+const testZone = "example.com."
+
+func newTestZone(t *testing.T) *memory.Provider {
+	t.Helper()
+
+	provider := memory.New()
+	if _, err := provider.CreateZone(types.ZoneInfo{
+		Name:        testZone,
+		Nameservers: []string{"ns1.example.com.", "ns2.example.com."},
+	}, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	return provider
+}
+
+func recordSet(name string, tpe types.RecordType, contents ...string) types.ResourceRecordSet {
+	rrSet := types.ResourceRecordSet{Name: name, Type: tpe, TTL: 300}
+	for _, content := range contents {
+		rrSet.Records = append(rrSet.Records, types.Record{Content: content})
+	}
+
+	return rrSet
+}
+
+func TestEnsureRecordSet(t *testing.T) {
+	provider := newTestZone(t)
+	rrSet := recordSet("www.example.com.", types.TypeA, "192.0.2.1")
+
+	written, err := EnsureRecordSet(provider, testZone, rrSet, "controller-a")
+	if err != nil || !written {
+		t.Fatalf("first EnsureRecordSet() = %v, %v; want true, nil", written, err)
+	}
+
+	written, err = EnsureRecordSet(provider, testZone, rrSet, "controller-a")
+	if err != nil || written {
+		t.Fatalf("unchanged EnsureRecordSet() = %v, %v; want false, nil", written, err)
+	}
+
+	live, err := provider.GetRecordSet(testZone, "www.example.com.", "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner := Owner(live); owner != "controller-a" {
+		t.Errorf("Owner() = %q, want controller-a", owner)
+	}
+
+	rrSet.Records[0].Content = "192.0.2.2"
+	if _, err = EnsureRecordSet(provider, testZone, rrSet, "controller-b"); !errors.Is(err, ErrNotOwner) {
+		t.Errorf("EnsureRecordSet() by another owner = %v, want ErrNotOwner", err)
+	}
+}
+
+func TestEnsureRecordSetRefusesUnowned(t *testing.T) {
+	provider := newTestZone(t)
+	if _, err := provider.ReplaceRecordSet(testZone, recordSet("www.example.com.", types.TypeA, "192.0.2.1")); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := EnsureRecordSet(provider, testZone, recordSet("www.example.com.", types.TypeA, "192.0.2.2"), "controller-a")
+	if !errors.Is(err, ErrNotOwner) {
+		t.Errorf("EnsureRecordSet() over unowned rrset = %v, want ErrNotOwner", err)
+	}
+}
+
+func TestEnsureRecordsContext(t *testing.T) {
+	provider := newTestZone(t)
+	if _, err := provider.ReplaceRecordSet(testZone, recordSet("same.example.com.", types.TypeA, "192.0.2.1")); err != nil {
+		t.Fatal(err)
+	}
+
+	rrSets := []types.ResourceRecordSet{
+		recordSet("same.example.com.", types.TypeA, "192.0.2.1"),
+		recordSet("www.example.com.", types.TypeA, "192.0.2.2", "192.0.2.3"),
+		recordSet("mail.example.com.", types.TypeMX, "10 mx.example.com."),
+		recordSet("outside.example.net.", types.TypeA, "192.0.2.4"),
+	}
+
+	var calls int
+	summary, err := EnsureRecordsContext(context.Background(), provider, testZone, rrSets, EnsureOptions{
+		OnProgress: func(EnsureProgress) { calls++ },
+	})
+
+	var ensureErr EnsureError
+	if !errors.As(err, &ensureErr) {
+		t.Fatalf("EnsureRecordsContext() error = %v, want EnsureError", err)
+	}
+	if summary.Written != 2 || summary.Unchanged != 1 || len(summary.Failed) != 1 {
+		t.Errorf("summary = %+v, want 2 written, 1 unchanged, 1 failed", summary)
+	}
+	if _, ok := summary.Failed[RecordKey{Name: "outside.example.net.", Type: types.TypeA}]; !ok {
+		t.Errorf("failed rrsets = %v, want outside.example.net. A", summary.Failed)
+	}
+	if calls == 0 {
+		t.Error("OnProgress was never called")
+	}
+
+	exists, err := provider.RecordExists(testZone, "www.example.com.", "A")
+	if err != nil || !exists {
+		t.Errorf("RecordExists(www A) = %v, %v; want true", exists, err)
+	}
+}
+
+func TestEnsureRecordsContextCancelled(t *testing.T) {
+	provider := newTestZone(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	summary, err := EnsureRecordsContext(ctx, provider, testZone, []types.ResourceRecordSet{
+		recordSet("www.example.com.", types.TypeA, "192.0.2.1"),
+	}, EnsureOptions{})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("EnsureRecordsContext() error = %v, want context.Canceled", err)
+	}
+	if summary.Written != 0 || len(summary.Pending) != 1 {
+		t.Errorf("summary = %+v, want 1 pending", summary)
+	}
+}
+
+func TestEnsureRecordsContextOwner(t *testing.T) {
+	provider := newTestZone(t)
+	if _, err := EnsureRecordSet(provider, testZone, recordSet("theirs.example.com.", types.TypeA, "192.0.2.1"), "controller-b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.ReplaceRecordSet(testZone, recordSet("manual.example.com.", types.TypeA, "192.0.2.1")); err != nil {
+		t.Fatal(err)
+	}
+
+	summary, err := EnsureRecordsContext(context.Background(), provider, testZone, []types.ResourceRecordSet{
+		recordSet("theirs.example.com.", types.TypeA, "192.0.2.2"),
+		recordSet("manual.example.com.", types.TypeA, "192.0.2.2"),
+		recordSet("mine.example.com.", types.TypeA, "192.0.2.2"),
+	}, EnsureOptions{Owner: "controller-a"})
+	if err == nil {
+		t.Fatal("EnsureRecordsContext() over rrsets of other owners succeeded")
+	}
+	if summary.Written != 1 || len(summary.Failed) != 2 {
+		t.Errorf("summary = %+v, want 1 written, 2 failed", summary)
+	}
+	for key, err := range summary.Failed {
+		if !errors.Is(err, ErrNotOwner) {
+			t.Errorf("%s %s: %v, want ErrNotOwner", key.Name, key.Type, err)
+		}
+	}
+
+	mine, err := provider.GetRecordSet(testZone, "mine.example.com.", "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner := Owner(mine); owner != "controller-a" {
+		t.Errorf("Owner() = %q, want controller-a", owner)
+	}
+}
+
+func TestEnsureRecordsContextOwnerRelativeNames(t *testing.T) {
+	provider := newTestZone(t)
+	if _, err := EnsureRecordSet(provider, testZone, recordSet("theirs.example.com.", types.TypeA, "192.0.2.1"), "controller-b"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"theirs", "theirs.example.com", "THEIRS.Example.com."} {
+		summary, err := EnsureRecordsContext(context.Background(), provider, testZone, []types.ResourceRecordSet{
+			recordSet(name, types.TypeA, "192.0.2.2"),
+		}, EnsureOptions{Owner: "controller-a"})
+		if !errors.As(err, new(EnsureError)) || summary.Written != 0 {
+			t.Fatalf("EnsureRecordsContext(%s) = %+v, %v; want nothing written", name, summary, err)
+		}
+		for key, err := range summary.Failed {
+			if !errors.Is(err, ErrNotOwner) {
+				t.Errorf("%s: %s %s: %v, want ErrNotOwner", name, key.Name, key.Type, err)
+			}
+		}
+
+		if _, err = EnsureRecordSet(provider, testZone, recordSet(name, types.TypeA, "192.0.2.2"), "controller-a"); !errors.Is(err, ErrNotOwner) {
+			t.Errorf("EnsureRecordSet(%s) = %v, want ErrNotOwner", name, err)
+		}
+	}
+
+	live, err := provider.GetRecordSet(testZone, "theirs.example.com.", "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner := Owner(live); owner != "controller-b" || live.Records[0].Content != "192.0.2.1" {
+		t.Errorf("theirs.example.com. A = %+v, want it untouched", live)
+	}
+}
+
+// Counts the PATCHes sent to the provider
+type patchCounter struct {
+	*memory.Provider
+	patches int
+}
+
+func (counter *patchCounter) PatchRecordSets(zone string, rrSets []types.ResourceRecordSet) error {
+	counter.patches++
+	return counter.Provider.PatchRecordSets(zone, rrSets)
+}
+
+func TestEnsureRecordsContextRelativeNamesIdempotent(t *testing.T) {
+	provider := &patchCounter{Provider: newTestZone(t)}
+	rrSets := []types.ResourceRecordSet{
+		recordSet("www", types.TypeA, "192.0.2.1"),
+		recordSet("api.example.com", types.TypeA, "192.0.2.2"),
+		recordSet("Mail", types.TypeMX, "10 mx.example.com."),
+		recordSet("@", types.TypeTXT, `"v=spf1 -all"`),
+	}
+
+	summary, err := EnsureRecordsContext(context.Background(), provider, testZone, rrSets, EnsureOptions{Owner: "controller-a"})
+	if err != nil || summary.Written != len(rrSets) {
+		t.Fatalf("first EnsureRecordsContext() = %+v, %v; want %d written", summary, err, len(rrSets))
+	}
+
+	provider.patches = 0
+	summary, err = EnsureRecordsContext(context.Background(), provider, testZone, rrSets, EnsureOptions{Owner: "controller-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Written != 0 || summary.Unchanged != len(rrSets) || provider.patches != 0 {
+		t.Errorf("second EnsureRecordsContext() = %+v with %d PATCHes, want all unchanged and no PATCH", summary, provider.patches)
+	}
+}
@@ -0,0 +1,85 @@
+package ops
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// IdempotencyCommentPrefix prefix of the rrset comment holding the idempotency key of the
+// last change applied to it.
+const IdempotencyCommentPrefix = "idempotency-key:"
+
+// IdempotencyKey Returns the idempotency key stored in the comments of rrSet, if any.
+func IdempotencyKey(rrSet *types.ResourceRecordSet) string {
+	for _, comment := range rrSet.Comments {
+		if strings.HasPrefix(comment.Content, IdempotencyCommentPrefix) {
+			return strings.TrimPrefix(comment.Content, IdempotencyCommentPrefix)
+		}
+	}
+
+	return ""
+}
+
+// ApplyIdempotent Applies the rrset changes to zone in a single PatchRecordSets call,
+// stamping replaced rrsets with key. When every replaced rrset already carries key and
+// every deleted rrset is gone, the change was applied before, e.g. by a retry racing a
+// slow response, and nothing is sent. It returns true when the change was applied.
+func ApplyIdempotent(c client.RecordAPI, zone string, key string, rrSets []types.ResourceRecordSet) (bool, error) {
+	if key == "" {
+		return false, fmt.Errorf("Error applying changes to zone: %s, an idempotency key is required", zone)
+	}
+
+	live, err := c.ListRecordsAsRRSet(zone)
+	if err != nil {
+		return false, err
+	}
+
+	current := make(map[RecordKey]*types.ResourceRecordSet, len(live))
+	for i := range live {
+		current[diffKey(&live[i])] = &live[i]
+	}
+
+	applied := true
+	changes := make([]types.ResourceRecordSet, 0, len(rrSets))
+	for _, rrSet := range rrSets {
+		existing := current[diffKey(&rrSet)]
+
+		if rrSet.ChangeType == types.ChangeTypeDelete {
+			applied = applied && (existing == nil || existing.Empty())
+			changes = append(changes, rrSet)
+			continue
+		}
+
+		if existing == nil || IdempotencyKey(existing) != key {
+			applied = false
+		}
+
+		// keep the other comments of the rrset, e.g. tags
+		comments := rrSet.Comments
+		if comments == nil && existing != nil {
+			comments = existing.Comments
+		}
+		rrSet.Comments = make([]types.Comment, 0, len(comments)+1)
+		for _, comment := range comments {
+			if !strings.HasPrefix(comment.Content, IdempotencyCommentPrefix) {
+				rrSet.Comments = append(rrSet.Comments, comment)
+			}
+		}
+		rrSet.Comments = append(rrSet.Comments, types.Comment{Content: IdempotencyCommentPrefix + key})
+
+		changes = append(changes, rrSet)
+	}
+
+	if applied {
+		return false, nil
+	}
+
+	if err = c.PatchRecordSets(zone, changes); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
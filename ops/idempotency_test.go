@@ -0,0 +1,59 @@
+package ops
+
+import (
+	"testing"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+func TestApplyIdempotent(t *testing.T) {
+	provider := newTestZone(t)
+	if _, err := provider.ReplaceRecordSet(testZone, recordSet("old.example.com.", types.TypeA, "192.0.2.9")); err != nil {
+		t.Fatal(err)
+	}
+
+	changes := []types.ResourceRecordSet{
+		recordSet("www.example.com.", types.TypeA, "192.0.2.1"),
+		{Name: "old.example.com.", Type: types.TypeA, ChangeType: types.ChangeTypeDelete},
+	}
+	changes[0].ChangeType = types.ChangeTypeReplace
+
+	applied, err := ApplyIdempotent(provider, testZone, "request-1", changes)
+	if err != nil || !applied {
+		t.Fatalf("first ApplyIdempotent() = %v, %v; want true, nil", applied, err)
+	}
+
+	serial := zoneSerial(t, provider)
+	applied, err = ApplyIdempotent(provider, testZone, "request-1", changes)
+	if err != nil || applied {
+		t.Fatalf("repeated ApplyIdempotent() = %v, %v; want false, nil", applied, err)
+	}
+	if got := zoneSerial(t, provider); got != serial {
+		t.Errorf("repeated ApplyIdempotent() changed the zone: serial %d, want %d", got, serial)
+	}
+
+	live, err := provider.GetRecordSet(testZone, "www.example.com.", "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if key := IdempotencyKey(live); key != "request-1" {
+		t.Errorf("IdempotencyKey() = %q, want request-1", key)
+	}
+
+	applied, err = ApplyIdempotent(provider, testZone, "request-2", changes)
+	if err != nil || !applied {
+		t.Errorf("ApplyIdempotent() with a new key = %v, %v; want true, nil", applied, err)
+	}
+}
+
+func zoneSerial(t *testing.T, c client.ZoneAPI) int64 {
+	t.Helper()
+
+	zone, err := c.GetZone(testZone)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return zone.Serial
+}
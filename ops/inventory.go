@@ -0,0 +1,75 @@
+package ops
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+	"golang.org/x/sync/errgroup"
+)
+
+// ServerZone Zone found on one or more servers by ListAllZonesParallel.
+type ServerZone struct {
+	Zone types.ZoneInfo
+	// Servers lists the URLs of every server the zone was found on.
+	Servers []string
+}
+
+// ListAllZonesParallel Lists the zones of every client concurrently and returns them
+// deduplicated by zone ID, sorted by ID, each annotated with the servers hosting it.
+// The first error cancels the requests still in flight and is returned.
+func ListAllZonesParallel(clients []*client.Client) ([]ServerZone, error) {
+	return ListAllZonesParallelContext(context.Background(), clients)
+}
+
+// ListAllZonesParallelContext Works like ListAllZonesParallel, cancelling every request
+// when ctx is done.
+func ListAllZonesParallelContext(ctx context.Context, clients []*client.Client) ([]ServerZone, error) {
+	var mutex sync.Mutex
+	byID := map[string]*ServerZone{}
+
+	group, ctx := errgroup.WithContext(ctx)
+	for _, c := range clients {
+		c := c
+		group.Go(func() error {
+			zones, err := c.WithContext(ctx).ListZones()
+			if err != nil {
+				return err
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			for _, zone := range zones {
+				id := zone.ID
+				if id == "" {
+					id = zone.Name
+				}
+
+				entry, ok := byID[id]
+				if !ok {
+					entry = &ServerZone{Zone: zone}
+					byID[id] = entry
+				}
+				entry.Servers = append(entry.Servers, c.ServerURL())
+			}
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	all := make([]ServerZone, 0, len(byID))
+	for _, entry := range byID {
+		sort.Strings(entry.Servers)
+		all = append(all, *entry)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Zone.ID+all[i].Zone.Name < all[j].Zone.ID+all[j].Zone.Name
+	})
+
+	return all, nil
+}
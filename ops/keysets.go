@@ -0,0 +1,157 @@
+package ops
+
+import (
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// ZoneKeySets DNSKEY, CDS and CDNSKEY records of a zone, parsed. CDS and CDNSKEY are
+// the records a parent, or a registrar polling them (RFC 7344), updates DS records from.
+type ZoneKeySets struct {
+	Zone    string
+	DNSKEY  []types.DNSKEY
+	CDS     []types.DS
+	CDNSKEY []types.DNSKEY
+}
+
+// ReadZoneKeySets Returns the key sets published for zone as the server builds them
+// from its cryptokeys and the PUBLISH-CDS and PUBLISH-CDNSKEY metadata, plus records of
+// these types stored in the zone, as presigned zones do.
+func ReadZoneKeySets(c client.Provider, zone string) (*ZoneKeySets, error) {
+	keys, err := c.ListCryptokeys(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	publishCDNSKEY, err := c.GetMetadata(zone, "PUBLISH-CDNSKEY")
+	if err != nil {
+		return nil, err
+	}
+
+	var dnskeys, cds, cdnskeys []string
+	for _, key := range keys {
+		if !key.Published || key.DNSKey == "" {
+			continue
+		}
+		dnskeys = append(dnskeys, key.DNSKey)
+
+		if key.Active && key.IsKeySigningKey() {
+			cds = append(cds, key.CDS...)
+			if len(publishCDNSKEY) > 0 && publishCDNSKEY[0] == "1" {
+				cdnskeys = append(cdnskeys, key.DNSKey)
+			}
+		}
+	}
+
+	rrSets, err := c.GetRecordsByName(zone, zone)
+	if err != nil {
+		return nil, err
+	}
+	for _, rrSet := range rrSets {
+		for _, record := range rrSet.Records {
+			if record.Disabled {
+				continue
+			}
+			switch rrSet.Type {
+			case types.TypeDNSKEY:
+				dnskeys = append(dnskeys, record.Content)
+			case types.TypeCDS:
+				cds = append(cds, record.Content)
+			case types.TypeCDNSKEY:
+				cdnskeys = append(cdnskeys, record.Content)
+			}
+		}
+	}
+
+	return parseZoneKeySets(zone, dnskeys, cds, cdnskeys)
+}
+
+// QueryZoneKeySets Returns the key sets served for zone, resolved through the
+// DNS-over-HTTPS resolver at dohURL, i.e. what a parent polling the zone sees.
+func QueryZoneKeySets(zone string, dohURL string) (*ZoneKeySets, error) {
+	dnskeys, err := QueryDoH(zone, types.TypeDNSKEY, dohURL)
+	if err != nil {
+		return nil, err
+	}
+	cds, err := QueryDoH(zone, types.TypeCDS, dohURL)
+	if err != nil {
+		return nil, err
+	}
+	cdnskeys, err := QueryDoH(zone, types.TypeCDNSKEY, dohURL)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseZoneKeySets(zone, dnskeys, cds, cdnskeys)
+}
+
+// Parses record contents into key sets, dropping duplicates
+func parseZoneKeySets(zone string, dnskeys []string, cds []string, cdnskeys []string) (*ZoneKeySets, error) {
+	keySets := &ZoneKeySets{Zone: zone}
+	seen := make(map[string]bool)
+
+	for _, content := range dnskeys {
+		key, err := types.ParseDNSKEY(content)
+		if err != nil {
+			return nil, err
+		}
+		if !seen["DNSKEY "+key.String()] {
+			seen["DNSKEY "+key.String()] = true
+			keySets.DNSKEY = append(keySets.DNSKEY, key)
+		}
+	}
+
+	for _, content := range cds {
+		ds, err := types.ParseDS(content)
+		if err != nil {
+			return nil, err
+		}
+		if !seen["CDS "+ds.String()] {
+			seen["CDS "+ds.String()] = true
+			keySets.CDS = append(keySets.CDS, ds)
+		}
+	}
+
+	for _, content := range cdnskeys {
+		key, err := types.ParseDNSKEY(content)
+		if err != nil {
+			return nil, err
+		}
+		if !seen["CDNSKEY "+key.String()] {
+			seen["CDNSKEY "+key.String()] = true
+			keySets.CDNSKEY = append(keySets.CDNSKEY, key)
+		}
+	}
+
+	return keySets, nil
+}
+
+// DS Returns the DS records the parent should publish according to the CDS records, or
+// to the CDNSKEY records hashed with digestType when there are no CDS records. It
+// returns no records when the zone asks for the removal of its DS records.
+func (keySets *ZoneKeySets) DS(digestType int) ([]types.DS, error) {
+	if len(keySets.CDS) > 0 {
+		var ds []types.DS
+		for _, record := range keySets.CDS {
+			if record.IsDelete() {
+				return nil, nil
+			}
+			ds = append(ds, record)
+		}
+		return ds, nil
+	}
+
+	var ds []types.DS
+	for _, key := range keySets.CDNSKEY {
+		if key.IsDelete() {
+			return nil, nil
+		}
+		record, err := key.DS(keySets.Zone, digestType)
+		if err != nil {
+			return nil, err
+		}
+		ds = append(ds, record)
+	}
+
+	return ds, nil
+}
@@ -0,0 +1,136 @@
+package ops
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// LeaseCommentPrefix prefix of the rrset comment holding the lease expiry (RFC 3339).
+const LeaseCommentPrefix = "lease-expires:"
+
+// LeaseRecord Replaces the rrset of name and type with content and marks it to expire
+// after lease, keeping the other comments of the rrset, such as its owner tag. Expired
+// leases are deleted by a Reaper.
+func LeaseRecord(c client.RecordAPI, zone string, name string, tpe types.RecordType, content string, ttl int, lease time.Duration) error {
+	current, err := c.GetRecordSet(zone, name, string(tpe))
+	if err != nil {
+		return err
+	}
+
+	rrSet := types.ResourceRecordSet{
+		Name:    name,
+		Type:    tpe,
+		TTL:     ttl,
+		Records: []types.Record{{Content: content}},
+	}
+	if current != nil {
+		rrSet.Comments = current.Comments
+	}
+	SetLeaseExpiry(&rrSet, time.Now().Add(lease))
+
+	_, err = c.ReplaceRecordSet(zone, rrSet)
+	return err
+}
+
+// LeaseExpiry Returns the lease expiry stored in the comments of rrSet.
+func LeaseExpiry(rrSet *types.ResourceRecordSet) (time.Time, bool) {
+	for _, comment := range rrSet.Comments {
+		if !strings.HasPrefix(comment.Content, LeaseCommentPrefix) {
+			continue
+		}
+
+		expiry, err := time.Parse(time.RFC3339, strings.TrimPrefix(comment.Content, LeaseCommentPrefix))
+		if err == nil {
+			return expiry, true
+		}
+	}
+
+	return time.Time{}, false
+}
+
+// SetLeaseExpiry Replaces the lease comment of rrSet, keeping any other comments.
+func SetLeaseExpiry(rrSet *types.ResourceRecordSet, expiry time.Time) {
+	comments := make([]types.Comment, 0, len(rrSet.Comments)+1)
+	for _, comment := range rrSet.Comments {
+		if !strings.HasPrefix(comment.Content, LeaseCommentPrefix) {
+			comments = append(comments, comment)
+		}
+	}
+
+	rrSet.Comments = append(comments, types.Comment{
+		Content: LeaseCommentPrefix + expiry.UTC().Format(time.RFC3339),
+	})
+}
+
+// Reaper Deletes rrsets whose lease has expired, on a fixed interval.
+type Reaper struct {
+	Client   client.RecordAPI
+	Zones    []string
+	Interval time.Duration
+	// OnReap, when set, is called with the rrsets deleted from a zone.
+	OnReap func(zone string, reaped []RecordKey)
+	// OnError, when set, is called when a zone could not be reaped.
+	OnError func(zone string, err error)
+}
+
+// Run Reaps every zone immediately and then on each interval until ctx is done.
+func (reaper *Reaper) Run(ctx context.Context) error {
+	ticker := time.NewTicker(reaper.Interval)
+	defer ticker.Stop()
+
+	for {
+		for _, zone := range reaper.Zones {
+			reaped, err := reaper.Reap(zone, time.Now())
+			if err != nil && reaper.OnError != nil {
+				reaper.OnError(zone, err)
+			}
+			if len(reaped) > 0 && reaper.OnReap != nil {
+				reaper.OnReap(zone, reaped)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Reap Deletes, in a single PATCH, the rrsets of zone whose lease expired before now.
+func (reaper *Reaper) Reap(zone string, now time.Time) ([]RecordKey, error) {
+	rrSets, err := reaper.Client.ListRecordsAsRRSet(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var reaped []RecordKey
+	var changes []types.ResourceRecordSet
+	for _, rrSet := range rrSets {
+		expiry, ok := LeaseExpiry(&rrSet)
+		if !ok || expiry.After(now) {
+			continue
+		}
+
+		reaped = append(reaped, Key(&rrSet))
+		changes = append(changes, types.ResourceRecordSet{
+			Name:       rrSet.Name,
+			Type:       rrSet.Type,
+			ChangeType: types.ChangeTypeDelete,
+		})
+	}
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	if err = reaper.Client.PatchRecordSets(zone, changes); err != nil {
+		return nil, err
+	}
+
+	return reaped, nil
+}
@@ -0,0 +1,35 @@
+package ops
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+func TestLeaseRecordKeepsOwner(t *testing.T) {
+	provider := newTestZone(t)
+	rrSet := recordSet("ci.example.com.", types.TypeA, "192.0.2.1")
+	if _, err := EnsureRecordSet(provider, testZone, rrSet, "controller-a"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := LeaseRecord(provider, testZone, "ci.example.com.", types.TypeA, "192.0.2.1", 300, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	live, err := provider.GetRecordSet(testZone, "ci.example.com.", "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner := Owner(live); owner != "controller-a" {
+		t.Errorf("Owner() after lease = %q, want controller-a", owner)
+	}
+	if expiry, ok := LeaseExpiry(live); !ok || expiry.Before(time.Now()) {
+		t.Errorf("LeaseExpiry() = %v, %v; want an expiry in the future", expiry, ok)
+	}
+
+	if _, err = EnsureRecordSet(provider, testZone, rrSet, "controller-a"); err != nil {
+		t.Errorf("EnsureRecordSet() after lease = %v", err)
+	}
+}
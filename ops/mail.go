@@ -0,0 +1,329 @@
+package ops
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// DefaultMailTTL TTL used for mail records when MailSpec.TTL is zero.
+const DefaultMailTTL = 3600
+
+// MaxSPFLookups Number of DNS lookups an SPF record may cause (RFC 7208, section 4.6.4).
+const MaxSPFLookups = 10
+
+// MXHost Mail exchanger of a domain.
+type MXHost struct {
+	Preference int
+	Host       string
+}
+
+// DMARCPolicy DMARC record of a domain (RFC 7489).
+type DMARCPolicy struct {
+	// Policy is "none", "quarantine" or "reject".
+	Policy string
+	// SubdomainPolicy defaults to Policy when empty.
+	SubdomainPolicy string
+	// Percent of messages the policy applies to, 100 when zero.
+	Percent int
+	// RUA and RUF are the aggregate and failure report addresses, e.g. "mailto:dmarc@example.com".
+	RUA []string
+	RUF []string
+}
+
+// String Returns the DMARC record value, unquoted.
+func (policy DMARCPolicy) String() string {
+	tags := []string{"v=DMARC1", "p=" + policy.Policy}
+	if policy.SubdomainPolicy != "" {
+		tags = append(tags, "sp="+policy.SubdomainPolicy)
+	}
+	if policy.Percent != 0 && policy.Percent != 100 {
+		tags = append(tags, "pct="+strconv.Itoa(policy.Percent))
+	}
+	if len(policy.RUA) > 0 {
+		tags = append(tags, "rua="+strings.Join(policy.RUA, ","))
+	}
+	if len(policy.RUF) > 0 {
+		tags = append(tags, "ruf="+strings.Join(policy.RUF, ","))
+	}
+
+	return strings.Join(tags, "; ")
+}
+
+// MailSpec Mail records of a domain. Empty fields leave the corresponding records
+// untouched.
+type MailSpec struct {
+	// Domain defaults to the zone apex.
+	Domain string
+	MX     []MXHost
+	// SPF is the SPF policy, e.g. "v=spf1 mx include:_spf.example.net -all".
+	SPF string
+	// DKIM maps selectors to their public key records, e.g. "v=DKIM1; k=rsa; p=MIIBIjAN...".
+	DKIM  map[string]string
+	DMARC *DMARCPolicy
+	TTL   int
+}
+
+// ConfigureMail Writes the MX, SPF, DKIM and DMARC records of spec in a single PATCH.
+// The SPF policy replaces any other SPF policy of the domain while other TXT records,
+// such as site verification tokens, are kept. DKIM keys longer than 255 bytes are split
+// into several strings.
+func ConfigureMail(c client.RecordAPI, zone string, spec MailSpec) error {
+	domain := spec.Domain
+	if domain == "" {
+		domain = zone
+	}
+	ttl := spec.TTL
+	if ttl == 0 {
+		ttl = DefaultMailTTL
+	}
+
+	var changes []types.ResourceRecordSet
+
+	if len(spec.MX) > 0 {
+		mx := types.ResourceRecordSet{Name: domain, Type: types.TypeMX, ChangeType: types.ChangeTypeReplace, TTL: ttl}
+		for _, host := range spec.MX {
+			mx.Records = append(mx.Records, types.Record{Content: fmt.Sprintf("%d %s", host.Preference, host.Host)})
+		}
+		changes = append(changes, mx)
+	}
+
+	if spec.SPF != "" {
+		if !isSPF(spec.SPF) {
+			return fmt.Errorf("Error configuring mail of domain: %s, SPF policy must start with v=spf1: %q", domain, spec.SPF)
+		}
+
+		current, err := c.GetRecordSet(zone, domain, string(types.TypeTXT))
+		if err != nil {
+			return err
+		}
+
+		txt := types.ResourceRecordSet{Name: domain, Type: types.TypeTXT, ChangeType: types.ChangeTypeReplace, TTL: ttl}
+		if current != nil {
+			txt.TTL = current.TTL
+			for _, record := range current.Records {
+				if value, err := types.UnquoteTXT(record.Content); err != nil || !isSPF(value) {
+					txt.Records = append(txt.Records, record)
+				}
+			}
+		}
+		txt.Records = append(txt.Records, types.Record{Content: types.QuoteTXT(spec.SPF)})
+		changes = append(changes, txt)
+	}
+
+	for selector, key := range spec.DKIM {
+		changes = append(changes, types.ResourceRecordSet{
+			Name:       selector + "._domainkey." + domain,
+			Type:       types.TypeTXT,
+			ChangeType: types.ChangeTypeReplace,
+			TTL:        ttl,
+			Records:    []types.Record{{Content: types.QuoteTXT(key)}},
+		})
+	}
+
+	if spec.DMARC != nil {
+		if err := checkDMARCPolicy(spec.DMARC.Policy); err != nil {
+			return fmt.Errorf("Error configuring mail of domain: %s, %w", domain, err)
+		}
+		changes = append(changes, types.ResourceRecordSet{
+			Name:       "_dmarc." + domain,
+			Type:       types.TypeTXT,
+			ChangeType: types.ChangeTypeReplace,
+			TTL:        ttl,
+			Records:    []types.Record{{Content: types.QuoteTXT(spec.DMARC.String())}},
+		})
+	}
+
+	if len(changes) == 0 {
+		return nil
+	}
+
+	return c.PatchRecordSets(zone, changes)
+}
+
+// LintMail Returns the problems found in the mail records of domain in zone: MX records
+// pointing at addresses or CNAMEs, missing or several SPF policies, SPF policies
+// exceeding MaxSPFLookups or without an all mechanism, DKIM records without a key and
+// invalid DMARC records.
+func LintMail(c client.RecordAPI, zone string, domain string) ([]string, error) {
+	rrSets, err := c.ListRecordsAsRRSet(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var problems []string
+	problem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	cnames := make(map[string]bool)
+	for _, rrSet := range rrSets {
+		if rrSet.Type == types.TypeCNAME && !rrSet.Empty() {
+			cnames[strings.ToLower(rrSet.Name)] = true
+		}
+	}
+
+	var spf []string
+	dmarcFound := false
+	for _, rrSet := range rrSets {
+		name := rrSet.Name
+		switch {
+		case rrSet.Type == types.TypeSPF && types.EqualNames(name, domain):
+			problem("%s has an SPF type record, which is obsolete: publish SPF policies as TXT", name)
+
+		case rrSet.Type == types.TypeMX && types.EqualNames(name, domain):
+			for _, record := range rrSet.Records {
+				fields := strings.Fields(record.Content)
+				if len(fields) != 2 {
+					problem("%s has an invalid MX record: %q", name, record.Content)
+					continue
+				}
+				host := fields[1]
+				if net.ParseIP(strings.TrimSuffix(host, ".")) != nil {
+					problem("%s MX points at an address, it must name a host: %s", name, host)
+				}
+				if cnames[strings.ToLower(host)] {
+					problem("%s MX points at %s, which is a CNAME", name, host)
+				}
+				if host == "." && len(rrSet.Records) > 1 {
+					problem("%s has a null MX next to other MX records", name)
+				}
+			}
+
+		case rrSet.Type == types.TypeTXT && types.EqualNames(name, domain):
+			for _, value := range txtValues(rrSet) {
+				if isSPF(value) {
+					spf = append(spf, value)
+				}
+			}
+
+		case rrSet.Type == types.TypeTXT && strings.Contains(strings.ToLower(name), "._domainkey.") && types.IsSubdomainOf(name, domain):
+			for _, value := range txtValues(rrSet) {
+				tags := parseTags(value)
+				if version, ok := tags["v"]; ok && version != "DKIM1" {
+					problem("%s has an invalid DKIM version: %q", name, version)
+				}
+				if _, ok := tags["p"]; !ok {
+					problem("%s DKIM record has no p= key tag", name)
+				}
+			}
+
+		case rrSet.Type == types.TypeTXT && types.EqualNames(name, "_dmarc."+domain):
+			var records []string
+			for _, value := range txtValues(rrSet) {
+				if strings.HasPrefix(value, "v=DMARC1") {
+					records = append(records, value)
+				}
+			}
+			if len(records) > 1 {
+				problem("%s has %d DMARC records, receivers ignore all of them", name, len(records))
+			}
+			for _, value := range records {
+				dmarcFound = true
+				tags := parseTags(value)
+				if err := checkDMARCPolicy(tags["p"]); err != nil {
+					problem("%s %s", name, err)
+				}
+				for _, address := range strings.Split(tags["rua"], ",") {
+					if address != "" && !strings.HasPrefix(strings.TrimSpace(address), "mailto:") {
+						problem("%s rua address is not a mailto URI: %q", name, address)
+					}
+				}
+			}
+		}
+	}
+
+	switch {
+	case len(spf) == 0:
+		problem("%s has no SPF policy", domain)
+	case len(spf) > 1:
+		problem("%s has %d SPF policies, receivers treat this as a permanent error", domain, len(spf))
+	default:
+		if lookups := spfLookups(spf[0]); lookups > MaxSPFLookups {
+			problem("%s SPF policy needs %d DNS lookups, more than %d", domain, lookups, MaxSPFLookups)
+		}
+		if !spfHasAll(spf[0]) {
+			problem("%s SPF policy has no all mechanism or redirect", domain)
+		}
+	}
+
+	if !dmarcFound {
+		problem("%s has no DMARC record", domain)
+	}
+
+	return problems, nil
+}
+
+func isSPF(value string) bool {
+	return value == "v=spf1" || strings.HasPrefix(value, "v=spf1 ")
+}
+
+// Returns the unquoted values of the enabled records of a TXT rrset
+func txtValues(rrSet types.ResourceRecordSet) []string {
+	var values []string
+	for _, record := range rrSet.Records {
+		if record.Disabled {
+			continue
+		}
+		if value, err := types.UnquoteTXT(record.Content); err == nil {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// Parses "k=v; k=v" tag lists of DKIM and DMARC records
+func parseTags(value string) map[string]string {
+	tags := make(map[string]string)
+	for _, tag := range strings.Split(value, ";") {
+		key, val, ok := strings.Cut(tag, "=")
+		if ok {
+			tags[strings.TrimSpace(key)] = strings.Join(strings.Fields(val), "")
+		}
+	}
+
+	return tags
+}
+
+func checkDMARCPolicy(policy string) error {
+	switch policy {
+	case "none", "quarantine", "reject":
+		return nil
+	}
+
+	return fmt.Errorf("invalid DMARC policy: %q, expected none, quarantine or reject", policy)
+}
+
+// Counts the mechanisms and modifiers of an SPF policy that cause DNS lookups; included
+// policies are not followed
+func spfLookups(policy string) int {
+	lookups := 0
+	for _, term := range strings.Fields(policy)[1:] {
+		term = strings.ToLower(strings.TrimLeft(term, "+-~?"))
+		name, _, _ := strings.Cut(term, ":")
+		name, _, _ = strings.Cut(name, "/")
+		name, _, _ = strings.Cut(name, "=")
+		switch name {
+		case "include", "a", "mx", "ptr", "exists", "redirect":
+			lookups++
+		}
+	}
+
+	return lookups
+}
+
+func spfHasAll(policy string) bool {
+	for _, term := range strings.Fields(policy)[1:] {
+		term = strings.ToLower(term)
+		if strings.TrimLeft(term, "+-~?") == "all" || strings.HasPrefix(term, "redirect=") {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,102 @@
+package ops
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+	"github.com/miekg/dns"
+)
+
+// ErrTooFewNameservers Returned by SetNameservers when fewer than two name servers would
+// remain at the apex and Force is not set.
+var ErrTooFewNameservers = errors.New("at least two name servers are required")
+
+// DefaultNSTTL TTL of apex NS records when neither the options nor the current rrset set one.
+const DefaultNSTTL = 86400
+
+// NameserverOptions Controls the safety checks of SetNameservers.
+type NameserverOptions struct {
+	TTL int
+	// CheckResolve verifies that every name server resolves: in-zone names must have
+	// A or AAAA records in the zone, other names must resolve through the system resolver.
+	CheckResolve bool
+	// Force allows leaving fewer than two name servers, or none, at the apex.
+	Force bool
+}
+
+// SetNameservers Replaces the NS records at the apex of zone. Breaking the apex NS rrset
+// takes the zone offline, so fewer than two name servers are refused unless Force is set.
+func SetNameservers(c client.RecordAPI, zone string, nameservers []string, opts NameserverOptions) error {
+	if len(nameservers) < 2 && !opts.Force {
+		return fmt.Errorf("Error setting name servers of zone: %s, %w", zone, ErrTooFewNameservers)
+	}
+
+	current, err := c.GetRecordSet(zone, zone, string(types.TypeNS))
+	if err != nil {
+		return err
+	}
+
+	if len(nameservers) == 0 {
+		if current == nil {
+			return nil
+		}
+		return c.DeleteRecordSet(zone, zone, string(types.TypeNS))
+	}
+
+	rrSet := types.ResourceRecordSet{
+		Name: zone,
+		Type: types.TypeNS,
+		TTL:  opts.TTL,
+	}
+	if rrSet.TTL == 0 && current != nil {
+		rrSet.TTL = current.TTL
+	}
+	if rrSet.TTL == 0 {
+		rrSet.TTL = DefaultNSTTL
+	}
+
+	for _, ns := range nameservers {
+		ns = dns.Fqdn(ns)
+		if _, ok := dns.IsDomainName(ns); !ok {
+			return fmt.Errorf("Error setting name servers of zone: %s, invalid name server: %q", zone, ns)
+		}
+
+		if opts.CheckResolve {
+			if err = checkNameserver(c, zone, ns); err != nil {
+				return err
+			}
+		}
+
+		rrSet.Records = append(rrSet.Records, types.Record{Content: ns})
+	}
+
+	_, err = c.ReplaceRecordSet(zone, rrSet)
+	return err
+}
+
+// Checks that ns resolves, looking up in-zone names in the zone itself since they
+// need glue and may not be delegated yet
+func checkNameserver(c client.RecordAPI, zone string, ns string) error {
+	if dns.IsSubDomain(dns.Fqdn(zone), ns) {
+		for _, tpe := range []types.RecordType{types.TypeA, types.TypeAAAA} {
+			rrSet, err := c.GetRecordSet(zone, ns, string(tpe))
+			if err != nil {
+				return err
+			}
+			if rrSet != nil && len(rrSet.Records) > 0 {
+				return nil
+			}
+		}
+		return fmt.Errorf("Error setting name servers of zone: %s, %s has no A or AAAA records in the zone", zone, ns)
+	}
+
+	if _, err := net.LookupHost(strings.TrimSuffix(ns, ".")); err != nil {
+		return fmt.Errorf("Error setting name servers of zone: %s, %s does not resolve: %w", zone, ns, err)
+	}
+
+	return nil
+}
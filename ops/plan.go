@@ -0,0 +1,140 @@
+package ops
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// ANSI colors of the plan markers
+const (
+	colorReset  = "\x1b[0m"
+	colorRed    = "\x1b[31m"
+	colorGreen  = "\x1b[32m"
+	colorYellow = "\x1b[33m"
+)
+
+// Plan Changes needed to bring a zone to its desired state, to be reviewed before they
+// are applied.
+type Plan struct {
+	Zone    string
+	Changes []Change
+	// Color makes Render use ANSI colors, for terminals and CI logs that support them.
+	Color bool
+}
+
+// NewPlan Returns the plan turning the live rrsets of zone into desired.
+func NewPlan(zone string, desired []types.ResourceRecordSet, live []types.ResourceRecordSet) *Plan {
+	return &Plan{
+		Zone:    zone,
+		Changes: Diff(desired, live),
+	}
+}
+
+// PlanZone Returns the plan turning the live state of the spec zone into the spec.
+func PlanZone(c client.RecordAPI, spec *ZoneSpec) (*Plan, error) {
+	live, err := c.ListRecordsAsRRSet(spec.Zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewPlan(spec.Zone, spec.RecordSets, live), nil
+}
+
+// Empty Returns true when the plan has no changes.
+func (plan *Plan) Empty() bool {
+	return len(plan.Changes) == 0
+}
+
+// Counts Returns the number of changes of each kind.
+func (plan *Plan) Counts() map[ChangeKind]int {
+	counts := map[ChangeKind]int{ChangeCreate: 0, ChangeUpdate: 0, ChangeDelete: 0}
+	for _, change := range plan.Changes {
+		counts[change.Kind]++
+	}
+
+	return counts
+}
+
+// Render Writes the plan as a diff: "+" lines for records created, "-" lines for records
+// deleted and "~" for updated rrsets, followed by their old and new records.
+func (plan *Plan) Render(w io.Writer) error {
+	counts := plan.Counts()
+	if _, err := fmt.Fprintf(w, "Zone %s: %d to create, %d to update, %d to delete\n",
+		plan.Zone, counts[ChangeCreate], counts[ChangeUpdate], counts[ChangeDelete]); err != nil {
+		return err
+	}
+
+	for _, change := range plan.Changes {
+		var err error
+		switch change.Kind {
+		case ChangeCreate:
+			err = plan.renderRecords(w, "+ ", colorGreen, change.Desired)
+		case ChangeDelete:
+			err = plan.renderRecords(w, "- ", colorRed, change.Live)
+		case ChangeUpdate:
+			if err = plan.line(w, colorYellow, fmt.Sprintf("~ %s %s", change.Live.Name, change.Live.Type)); err == nil {
+				if err = plan.renderRecords(w, "    - ", colorRed, change.Live); err == nil {
+					err = plan.renderRecords(w, "    + ", colorGreen, change.Desired)
+				}
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// String Returns the plan rendered without colors.
+func (plan *Plan) String() string {
+	uncolored := *plan
+	uncolored.Color = false
+
+	var buf bytes.Buffer
+	uncolored.Render(&buf)
+	return buf.String()
+}
+
+// Apply Applies every change of the plan with PatchRecordSets.
+func (plan *Plan) Apply(c client.RecordAPI) error {
+	if plan.Empty() {
+		return nil
+	}
+
+	changes := make([]types.ResourceRecordSet, 0, len(plan.Changes))
+	for _, change := range plan.Changes {
+		changes = append(changes, change.RecordSet())
+	}
+
+	return c.PatchRecordSets(plan.Zone, changes)
+}
+
+func (plan *Plan) renderRecords(w io.Writer, prefix string, color string, rrSet *types.ResourceRecordSet) error {
+	for _, record := range rrSet.Records {
+		text := fmt.Sprintf("%s%s %d %s %s", prefix, rrSet.Name, rrSet.TTL, rrSet.Type, record.Content)
+		if record.Disabled {
+			text += " (disabled)"
+		}
+		if err := plan.line(w, color, text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (plan *Plan) line(w io.Writer, color string, text string) error {
+	var err error
+	if plan.Color {
+		_, err = fmt.Fprintln(w, color+text+colorReset)
+	} else {
+		_, err = fmt.Fprintln(w, text)
+	}
+
+	return err
+}
@@ -0,0 +1,116 @@
+package ops
+
+import (
+	"fmt"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// Pool Manages an rrset, e.g. round-robin A records, as a load-balancing pool whose
+// members are added, removed and disabled individually.
+type Pool struct {
+	client client.RecordAPI
+	Zone   string
+	Name   string
+	Type   string
+}
+
+// CreatePool Replaces the rrset of name and type with the given members and returns the pool.
+func CreatePool(c client.RecordAPI, zone string, name string, tpe string, ttl int, members []string) (*Pool, error) {
+	rrSet := types.ResourceRecordSet{
+		Name: name,
+		Type: types.RecordType(tpe),
+		TTL:  ttl,
+	}
+	for _, member := range members {
+		rrSet.Records = append(rrSet.Records, types.Record{Content: member})
+	}
+
+	if _, err := c.ReplaceRecordSet(zone, rrSet); err != nil {
+		return nil, err
+	}
+
+	return OpenPool(c, zone, name, tpe), nil
+}
+
+// OpenPool Returns the pool backed by an existing rrset.
+func OpenPool(c client.RecordAPI, zone string, name string, tpe string) *Pool {
+	return &Pool{
+		client: c,
+		Zone:   zone,
+		Name:   name,
+		Type:   tpe,
+	}
+}
+
+// Members Returns the members of the pool, including disabled ones.
+func (pool *Pool) Members() ([]types.Record, error) {
+	rrSet, err := pool.client.GetRecordSet(pool.Zone, pool.Name, pool.Type)
+	if err != nil || rrSet == nil || rrSet.Empty() {
+		return nil, err
+	}
+
+	return rrSet.Records, nil
+}
+
+// AddMember Adds content to the pool; adding an existing member is a no-op.
+func (pool *Pool) AddMember(content string) error {
+	return pool.update(func(rrSet *types.ResourceRecordSet) error {
+		for _, record := range rrSet.Records {
+			if record.Content == content {
+				return nil
+			}
+		}
+		rrSet.Records = append(rrSet.Records, types.Record{Content: content})
+		return nil
+	})
+}
+
+// RemoveMember Removes content from the pool. Removing the last member deletes the rrset.
+func (pool *Pool) RemoveMember(content string) error {
+	return pool.update(func(rrSet *types.ResourceRecordSet) error {
+		records := rrSet.Records[:0]
+		for _, record := range rrSet.Records {
+			if record.Content != content {
+				records = append(records, record)
+			}
+		}
+		rrSet.Records = records
+		return nil
+	})
+}
+
+// SetMemberDisabled Disables or re-enables a member without removing it from the pool.
+func (pool *Pool) SetMemberDisabled(content string, disabled bool) error {
+	return pool.update(func(rrSet *types.ResourceRecordSet) error {
+		for i := range rrSet.Records {
+			if rrSet.Records[i].Content == content {
+				rrSet.Records[i].Disabled = disabled
+				return nil
+			}
+		}
+		return fmt.Errorf("Error updating pool: %s, %q is not a member", rrSet.ID(), content)
+	})
+}
+
+func (pool *Pool) update(change func(*types.ResourceRecordSet) error) error {
+	rrSet, err := pool.client.GetRecordSet(pool.Zone, pool.Name, pool.Type)
+	if err != nil {
+		return err
+	}
+	if rrSet == nil {
+		rrSet = &types.ResourceRecordSet{Name: pool.Name, Type: types.RecordType(pool.Type)}
+	}
+
+	if err = change(rrSet); err != nil {
+		return err
+	}
+
+	if len(rrSet.Records) == 0 {
+		return pool.client.DeleteRecordSet(pool.Zone, pool.Name, pool.Type)
+	}
+
+	_, err = pool.client.ReplaceRecordSet(pool.Zone, *rrSet)
+	return err
+}
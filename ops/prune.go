@@ -0,0 +1,86 @@
+package ops
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// OwnerTag tag naming the controller that owns an rrset.
+const OwnerTag = "owner"
+
+// RecordKey Identifies an rrset by name and type.
+type RecordKey struct {
+	Name string
+	Type types.RecordType
+}
+
+// Key Returns the RecordKey of rrSet.
+func Key(rrSet *types.ResourceRecordSet) RecordKey {
+	return RecordKey{Name: rrSet.Name, Type: rrSet.Type}
+}
+
+// Owner Returns the owner tag of rrSet, or an empty string for unowned rrsets.
+func Owner(rrSet *types.ResourceRecordSet) string {
+	return Tags(rrSet)[OwnerTag]
+}
+
+// SetOwner Tags rrSet as owned by ownerID, keeping its other tags.
+func SetOwner(rrSet *types.ResourceRecordSet, ownerID string) {
+	tags := Tags(rrSet)
+	tags[OwnerTag] = ownerID
+	SetTags(rrSet, tags)
+}
+
+// Returns key with its name qualified against zone and lowercased, so that keys given
+// relative to the zone, without the trailing dot or in another case match the live rrset
+func canonicalKey(zone string, key RecordKey) RecordKey {
+	return RecordKey{Name: strings.ToLower(types.QualifyName(key.Name, zone)), Type: key.Type}
+}
+
+// PruneRecords Deletes, in a single PATCH, every rrset of zone owned by ownerID that is
+// not in keep, and returns the keys of the deleted rrsets. Rrsets without an owner tag
+// or owned by someone else are never touched. Keep names may be relative to zone.
+func PruneRecords(c client.RecordAPI, zone string, ownerID string, keep []RecordKey) ([]RecordKey, error) {
+	if ownerID == "" {
+		return nil, fmt.Errorf("Error pruning zone: %s, an owner ID is required", zone)
+	}
+
+	rrSets, err := c.ListRecordsAsRRSet(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	kept := make(map[RecordKey]bool, len(keep))
+	for _, key := range keep {
+		kept[canonicalKey(zone, key)] = true
+	}
+
+	var pruned []RecordKey
+	var changes []types.ResourceRecordSet
+	for _, rrSet := range rrSets {
+		key := Key(&rrSet)
+		if Owner(&rrSet) != ownerID || kept[canonicalKey(zone, key)] {
+			continue
+		}
+
+		pruned = append(pruned, key)
+		changes = append(changes, types.ResourceRecordSet{
+			Name:       rrSet.Name,
+			Type:       rrSet.Type,
+			ChangeType: types.ChangeTypeDelete,
+		})
+	}
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	if err = c.PatchRecordSets(zone, changes); err != nil {
+		return nil, err
+	}
+
+	return pruned, nil
+}
@@ -0,0 +1,65 @@
+package ops
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dmportella/powerdns/types"
+)
+
+func TestPruneRecords(t *testing.T) {
+	provider := newTestZone(t)
+	for _, name := range []string{"keep.example.com.", "stale.example.com."} {
+		if _, err := EnsureRecordSet(provider, testZone, recordSet(name, types.TypeA, "192.0.2.1"), "controller-a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := EnsureRecordSet(provider, testZone, recordSet("other.example.com.", types.TypeA, "192.0.2.1"), "controller-b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := provider.ReplaceRecordSet(testZone, recordSet("manual.example.com.", types.TypeA, "192.0.2.1")); err != nil {
+		t.Fatal(err)
+	}
+
+	pruned, err := PruneRecords(provider, testZone, "controller-a", []RecordKey{{Name: "keep.example.com.", Type: types.TypeA}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []RecordKey{{Name: "stale.example.com.", Type: types.TypeA}}; !reflect.DeepEqual(pruned, want) {
+		t.Errorf("PruneRecords() = %v, want %v", pruned, want)
+	}
+
+	for name, want := range map[string]bool{
+		"keep.example.com.":   true,
+		"stale.example.com.":  false,
+		"other.example.com.":  true,
+		"manual.example.com.": true,
+	} {
+		exists, err := provider.RecordExists(testZone, name, "A")
+		if err != nil || exists != want {
+			t.Errorf("RecordExists(%s) = %v, %v; want %v", name, exists, err, want)
+		}
+	}
+}
+
+func TestPruneRecordsQualifiesKeepNames(t *testing.T) {
+	provider := newTestZone(t)
+	for _, name := range []string{"www.example.com.", "api.example.com.", "mail.example.com.", "example.com.", "stale.example.com."} {
+		if _, err := EnsureRecordSet(provider, testZone, recordSet(name, types.TypeA, "192.0.2.1"), "controller-a"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	pruned, err := PruneRecords(provider, testZone, "controller-a", []RecordKey{
+		{Name: "www", Type: types.TypeA},
+		{Name: "api.example.com", Type: types.TypeA},
+		{Name: "MAIL.Example.COM.", Type: types.TypeA},
+		{Name: "@", Type: types.TypeA},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []RecordKey{{Name: "stale.example.com.", Type: types.TypeA}}; !reflect.DeepEqual(pruned, want) {
+		t.Errorf("PruneRecords() = %v, want %v", pruned, want)
+	}
+}
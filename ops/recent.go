@@ -0,0 +1,33 @@
+package ops
+
+import (
+	"sort"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// ListRecentChanges Returns the rrsets of zone with a comment modified after since, most
+// recently modified first. PowerDNS only timestamps comments, so this is a rough change
+// history: it sees rrsets written with comments, e.g. tagged or owned ones, and misses
+// changes to rrsets without comments.
+func ListRecentChanges(c client.RecordAPI, zone string, since time.Time) ([]types.ResourceRecordSet, error) {
+	rrSets, err := c.ListRecordsAsRRSet(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var recent []types.ResourceRecordSet
+	for _, rrSet := range rrSets {
+		if rrSet.LastModified().After(since) {
+			recent = append(recent, rrSet)
+		}
+	}
+
+	sort.SliceStable(recent, func(i, j int) bool {
+		return recent[i].LastModified().After(recent[j].LastModified())
+	})
+
+	return recent, nil
+}
@@ -0,0 +1,197 @@
+package ops
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// RegistrarDSPublisher Submits the DS records of a domain to its registrar, or any other
+// party maintaining the parent zone, completing the chain of trust of signed zones.
+type RegistrarDSPublisher interface {
+	// Publish replaces the DS records of domain with ds.
+	Publish(ctx context.Context, domain string, ds []types.DS) error
+	// Delete removes all DS records of domain, making it insecure.
+	Delete(ctx context.Context, domain string) error
+}
+
+// ParentZoneDSPublisher Publishes DS records in a parent zone hosted on PowerDNS.
+type ParentZoneDSPublisher struct {
+	Client client.RecordAPI
+	Zone   string
+	TTL    int
+}
+
+// Publish Replaces the DS record set of domain in the parent zone.
+func (publisher *ParentZoneDSPublisher) Publish(ctx context.Context, domain string, ds []types.DS) error {
+	ttl := publisher.TTL
+	if ttl == 0 {
+		ttl = DefaultDSTTL
+	}
+
+	rrSet := types.ResourceRecordSet{Name: domain, Type: types.TypeDS, TTL: ttl}
+	for _, record := range ds {
+		rrSet.Records = append(rrSet.Records, types.Record{Content: record.String()})
+	}
+
+	_, err := client.RecordAPIWithContext(ctx, publisher.Client).ReplaceRecordSet(publisher.Zone, rrSet)
+	return err
+}
+
+// Delete Deletes the DS record set of domain in the parent zone.
+func (publisher *ParentZoneDSPublisher) Delete(ctx context.Context, domain string) error {
+	return client.RecordAPIWithContext(ctx, publisher.Client).DeleteRecordSet(publisher.Zone, domain, string(types.TypeDS))
+}
+
+// WebhookDSPublisher Posts DS changes as JSON to a URL, for registrars reached through
+// an in-house service or automation platform. The body is
+//
+//	{"action": "publish", "domain": "example.com.", "ds": [{"key_tag": 2371, "algorithm": 13,
+//	 "digest_type": 2, "digest": "..."}]}
+//
+// with "delete" as action and no records to remove all DS records. Any status other
+// than 2xx is an error.
+type WebhookDSPublisher struct {
+	URL string
+	// Header is added to every request, e.g. to authenticate it.
+	Header http.Header
+	// HTTPClient defaults to a client with a 30s timeout.
+	HTTPClient *http.Client
+}
+
+type webhookDS struct {
+	KeyTag     int    `json:"key_tag"`
+	Algorithm  int    `json:"algorithm"`
+	DigestType int    `json:"digest_type"`
+	Digest     string `json:"digest"`
+}
+
+type webhookDSRequest struct {
+	Action string      `json:"action"`
+	Domain string      `json:"domain"`
+	DS     []webhookDS `json:"ds"`
+}
+
+// Publish Posts the DS records of domain.
+func (publisher *WebhookDSPublisher) Publish(ctx context.Context, domain string, ds []types.DS) error {
+	body := webhookDSRequest{Action: "publish", Domain: domain, DS: make([]webhookDS, len(ds))}
+	for i, record := range ds {
+		body.DS[i] = webhookDS(record)
+	}
+
+	return publisher.post(ctx, body)
+}
+
+// Delete Posts the removal of the DS records of domain.
+func (publisher *WebhookDSPublisher) Delete(ctx context.Context, domain string) error {
+	return publisher.post(ctx, webhookDSRequest{Action: "delete", Domain: domain, DS: []webhookDS{}})
+}
+
+func (publisher *WebhookDSPublisher) post(ctx context.Context, body webhookDSRequest) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest("POST", publisher.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	for key, values := range publisher.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := publisher.HTTPClient
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("Error submitting DS of %s: %w", body.Domain, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Error submitting DS of %s, status: %d", body.Domain, resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SubmitDS Submits the DS records of the active key signing keys of zone to registrar.
+func SubmitDS(ctx context.Context, c client.DNSSECAPI, zone string, registrar RegistrarDSPublisher) error {
+	contents, err := ChildDS(c, zone)
+	if err != nil {
+		return err
+	}
+
+	ds := make([]types.DS, 0, len(contents))
+	for _, content := range contents {
+		record, err := types.ParseDS(content)
+		if err != nil {
+			return err
+		}
+		ds = append(ds, record)
+	}
+
+	return registrar.Publish(ctx, zone, ds)
+}
+
+// EnableDNSSEC Signs zone with a new combined signing key, unless it has an active key
+// signing key already, and submits its DS records to registrar.
+func EnableDNSSEC(ctx context.Context, c client.DNSSECAPI, zone string, registrar RegistrarDSPublisher) error {
+	keys, err := c.ListCryptokeys(zone)
+	if err != nil {
+		return err
+	}
+
+	signed := false
+	for _, key := range keys {
+		signed = signed || key.Active && key.IsKeySigningKey()
+	}
+
+	if !signed {
+		_, err = c.CreateCryptokey(zone, types.Cryptokey{KeyType: "csk", Active: true, Published: true})
+		if err != nil {
+			return err
+		}
+	}
+
+	return SubmitDS(ctx, c, zone, registrar)
+}
+
+// DisableDNSSEC Removes the DS records of zone through registrar and, once they have
+// expired from caches after dsTTL, deletes the keys of zone. Deleting the keys first would
+// make the zone fail validation until then.
+func DisableDNSSEC(ctx context.Context, c client.DNSSECAPI, zone string, registrar RegistrarDSPublisher, dsTTL time.Duration) error {
+	if err := registrar.Delete(ctx, zone); err != nil {
+		return err
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(dsTTL):
+	}
+
+	keys, err := c.ListCryptokeys(zone)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		if err := c.DeleteCryptokey(zone, key.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,236 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+	"github.com/miekg/dns"
+)
+
+// ReverseZoneName Returns the in-addr.arpa or ip6.arpa zone covering cidr. The prefix
+// must fall on an octet boundary for IPv4 and a nibble boundary for IPv6; classless
+// RFC 2317 delegations are not supported.
+func ReverseZoneName(cidr string) (string, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return "", fmt.Errorf("Invalid CIDR: %q, %w", cidr, err)
+	}
+
+	ones, bits := network.Mask.Size()
+	labelBits := 4
+	if bits == 32 {
+		labelBits = 8
+	}
+	if ones%labelBits != 0 {
+		return "", fmt.Errorf("Invalid CIDR: %q, prefix length must be a multiple of %d", cidr, labelBits)
+	}
+
+	// ReverseAddr yields one label per octet or nibble of the full address, followed by
+	// the arpa suffix; keep only the labels covered by the prefix
+	labels := dns.SplitDomainName(mustReverseAddr(network.IP))
+	host := (bits - ones) / labelBits
+
+	return dns.Fqdn(strings.Join(labels[host:], ".")), nil
+}
+
+// ReverseRecordSets Returns the PTR rrsets pointing back at the A and AAAA records of
+// forward whose addresses fall in cidr. Addresses used by several names get one PTR
+// record per name.
+func ReverseRecordSets(forward []types.ResourceRecordSet, cidr string, ttl int) ([]types.ResourceRecordSet, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid CIDR: %q, %w", cidr, err)
+	}
+
+	var names []string
+	byName := make(map[string]*types.ResourceRecordSet)
+	for _, rrSet := range forward {
+		if rrSet.Type != types.TypeA && rrSet.Type != types.TypeAAAA {
+			continue
+		}
+
+		for _, record := range rrSet.Records {
+			ip := net.ParseIP(record.Content)
+			if record.Disabled || ip == nil || !network.Contains(ip) {
+				continue
+			}
+
+			name := mustReverseAddr(ip)
+			ptr, ok := byName[name]
+			if !ok {
+				ptr = &types.ResourceRecordSet{Name: name, Type: types.TypePTR, TTL: ttl}
+				byName[name] = ptr
+				names = append(names, name)
+			}
+			ptr.Records = append(ptr.Records, types.Record{Content: dns.Fqdn(rrSet.Name)})
+		}
+	}
+
+	sort.Strings(names)
+	rrSets := make([]types.ResourceRecordSet, 0, len(names))
+	for _, name := range names {
+		byName[name].Canonicalize()
+		rrSets = append(rrSets, *byName[name])
+	}
+
+	return rrSets, nil
+}
+
+// GenerateReverseZone Brings the reverse zone of cidr in line with the A and AAAA records
+// of forwardZone, creating it with the name servers of forwardZone when it does not exist.
+// Only PTR records pointing into forwardZone are managed: stale ones are deleted, while
+// PTR records pointing elsewhere, e.g. made by hand or for another forward zone sharing
+// cidr, and the SOA and NS rrsets are left alone. The applied plan is returned.
+func GenerateReverseZone(c client.Provider, forwardZone string, cidr string) (*Plan, error) {
+	reverseZone, err := ReverseZoneName(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	forward, err := c.ListRecordsAsRRSet(forwardZone)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := 0
+	var nameservers []string
+	for _, rrSet := range forward {
+		if rrSet.Type == types.TypeSOA && types.EqualNames(rrSet.Name, forwardZone) {
+			ttl = rrSet.TTL
+		}
+		if rrSet.Type == types.TypeNS && types.EqualNames(rrSet.Name, forwardZone) {
+			for _, record := range rrSet.Records {
+				nameservers = append(nameservers, record.Content)
+			}
+		}
+	}
+
+	desired, err := ReverseRecordSets(forward, cidr, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := c.ZoneExists(reverseZone)
+	if err != nil {
+		return nil, err
+	}
+
+	var live []types.ResourceRecordSet
+	if exists {
+		rrSets, err := c.ListRecordsAsRRSet(reverseZone)
+		if err != nil {
+			return nil, err
+		}
+		for _, rrSet := range rrSets {
+			if rrSet.Type == types.TypePTR {
+				live = append(live, rrSet)
+			}
+		}
+	} else {
+		_, err = c.CreateZone(types.ZoneInfo{Name: reverseZone, Nameservers: nameservers}, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	desired, live = keepForeignPTRs(forwardZone, desired, live)
+	plan := NewPlan(reverseZone, desired, live)
+	if err = plan.Apply(c); err != nil {
+		return nil, err
+	}
+
+	return plan, nil
+}
+
+// Adds the live PTR records not pointing into forwardZone to desired, so that applying
+// the plan leaves them alone. Live rrsets holding only such records are dropped from live
+func keepForeignPTRs(forwardZone string, desired []types.ResourceRecordSet, live []types.ResourceRecordSet) ([]types.ResourceRecordSet, []types.ResourceRecordSet) {
+	wanted := make(map[RecordKey]int, len(desired))
+	for i := range desired {
+		wanted[diffKey(&desired[i])] = i
+	}
+
+	var managed []types.ResourceRecordSet
+	for _, rrSet := range live {
+		var foreign []types.Record
+		for _, record := range rrSet.Records {
+			if !types.IsSubdomainOf(record.Content, forwardZone) {
+				foreign = append(foreign, record)
+			}
+		}
+
+		i, ok := wanted[diffKey(&rrSet)]
+		switch {
+		case ok:
+			desired[i].Records = append(desired[i].Records, foreign...)
+			desired[i].Comments = rrSet.Comments
+			desired[i].Canonicalize()
+		case len(foreign) == len(rrSet.Records):
+			continue
+		case len(foreign) > 0:
+			kept := rrSet
+			kept.Records = foreign
+			desired = append(desired, kept)
+		}
+		managed = append(managed, rrSet)
+	}
+
+	return desired, managed
+}
+
+// ReverseSync Keeps the reverse zones of forward zones consistent on a fixed interval.
+type ReverseSync struct {
+	Client client.Provider
+	// Networks maps each forward zone to the CIDRs whose reverse zones it feeds.
+	Networks map[string][]string
+	Interval time.Duration
+	// OnSync, when set, is called with the plan applied to each reverse zone.
+	OnSync func(forwardZone string, cidr string, plan *Plan)
+	// OnError, when set, is called when a reverse zone could not be synced.
+	OnError func(forwardZone string, cidr string, err error)
+}
+
+// Run Syncs every reverse zone immediately and then on each interval until ctx is done.
+func (reverseSync *ReverseSync) Run(ctx context.Context) error {
+	ticker := time.NewTicker(reverseSync.Interval)
+	defer ticker.Stop()
+
+	for {
+		for forwardZone, cidrs := range reverseSync.Networks {
+			for _, cidr := range cidrs {
+				plan, err := GenerateReverseZone(reverseSync.Client, forwardZone, cidr)
+				if err != nil {
+					if reverseSync.OnError != nil {
+						reverseSync.OnError(forwardZone, cidr, err)
+					}
+					continue
+				}
+				if reverseSync.OnSync != nil {
+					reverseSync.OnSync(forwardZone, cidr, plan)
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// ReverseAddr only fails on invalid addresses, which callers have already parsed
+func mustReverseAddr(ip net.IP) string {
+	name, err := dns.ReverseAddr(ip.String())
+	if err != nil {
+		panic(err)
+	}
+
+	return name
+}
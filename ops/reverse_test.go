@@ -0,0 +1,85 @@
+package ops
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dmportella/powerdns/memory"
+	"github.com/dmportella/powerdns/types"
+)
+
+func ptrTargets(t *testing.T, provider *memory.Provider) map[string][]string {
+	t.Helper()
+
+	rrSets, err := provider.GetRecordsByType("2.0.192.in-addr.arpa.", "PTR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	targets := make(map[string][]string, len(rrSets))
+	for _, rrSet := range rrSets {
+		for _, record := range rrSet.Records {
+			targets[rrSet.Name] = append(targets[rrSet.Name], record.Content)
+		}
+	}
+
+	return targets
+}
+
+func TestGenerateReverseZoneSharedNetwork(t *testing.T) {
+	provider := memory.New()
+	for _, zone := range []string{"example.com.", "example.net."} {
+		if _, err := provider.CreateZone(types.ZoneInfo{Name: zone, Nameservers: []string{"ns1.example.com."}}, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, rrSet := range []struct {
+		zone    string
+		name    string
+		address string
+	}{
+		{"example.com.", "host.example.com.", "192.0.2.1"},
+		{"example.com.", "www.example.com.", "192.0.2.10"},
+		{"example.net.", "host.example.net.", "192.0.2.2"},
+		{"example.net.", "www.example.net.", "192.0.2.10"},
+	} {
+		if _, err := provider.ReplaceRecordSet(rrSet.zone, recordSet(rrSet.name, types.TypeA, rrSet.address)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sync := func() {
+		t.Helper()
+		for _, zone := range []string{"example.com.", "example.net."} {
+			if _, err := GenerateReverseZone(provider, zone, "192.0.2.0/24"); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	sync()
+	if _, err := provider.ReplaceRecordSet("2.0.192.in-addr.arpa.", recordSet("99.2.0.192.in-addr.arpa.", types.TypePTR, "printer.example.org.")); err != nil {
+		t.Fatal(err)
+	}
+	sync()
+
+	want := map[string][]string{
+		"1.2.0.192.in-addr.arpa.":  {"host.example.com."},
+		"2.2.0.192.in-addr.arpa.":  {"host.example.net."},
+		"10.2.0.192.in-addr.arpa.": {"www.example.com.", "www.example.net."},
+		"99.2.0.192.in-addr.arpa.": {"printer.example.org."},
+	}
+	if got := ptrTargets(t, provider); !reflect.DeepEqual(got, want) {
+		t.Errorf("PTR records = %v, want %v", got, want)
+	}
+
+	if err := provider.DeleteRecordSet("example.com.", "www.example.com.", "A"); err != nil {
+		t.Fatal(err)
+	}
+	sync()
+
+	want["10.2.0.192.in-addr.arpa."] = []string{"www.example.net."}
+	if got := ptrTargets(t, provider); !reflect.DeepEqual(got, want) {
+		t.Errorf("PTR records after removing www.example.com. = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,232 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// Rollover stages reported through Rollover.OnStage.
+const (
+	StagePrePublish   = "pre-publish"
+	StageActivate     = "activate"
+	StageRetire       = "retire"
+	StagePublishDS    = "publish-ds"
+	StageRemoveOldKey = "remove-old-key"
+)
+
+// DefaultDNSKeyTTL TTL assumed for the DNSKEY record set when none is configured.
+const DefaultDNSKeyTTL = time.Hour
+
+// Clock abstracts time so rollover waits can be driven by tests.
+type Clock interface {
+	After(d time.Duration) <-chan time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Rollover orchestrates multi-step DNSSEC key rollovers, waiting for caches to
+// expire between stages.
+type Rollover struct {
+	Client client.Provider
+	Clock  Clock
+
+	// DNSKeyTTL is how long resolvers may cache the DNSKEY record set.
+	DNSKeyTTL time.Duration
+	// DSTTL is how long resolvers may cache the DS record set in the parent zone.
+	DSTTL time.Duration
+	// PropagationDelay is added to every wait to cover secondary transfers.
+	PropagationDelay time.Duration
+
+	// PublishDS updates the DS record set at the parent during a KSK rollover,
+	// e.g. by calling PublishDS for a parent hosted on PowerDNS.
+	PublishDS func(ctx context.Context, zone string) error
+	// Registrar, when PublishDS is not set, receives the DS records during a KSK rollover.
+	Registrar RegistrarDSPublisher
+	// OnStage, when set, is called as each rollover stage starts.
+	OnStage func(zone string, stage string)
+}
+
+// NewRollover Returns a Rollover for zones of c using the system clock and default TTLs.
+func NewRollover(c client.Provider) *Rollover {
+	return &Rollover{
+		Client:    c,
+		Clock:     systemClock{},
+		DNSKeyTTL: DefaultDNSKeyTTL,
+		DSTTL:     time.Duration(DefaultDSTTL) * time.Second,
+	}
+}
+
+// RolloverZSK Performs a pre-publish ZSK rollover: the new key is published inactive,
+// activated once the DNSKEY set has expired from caches, and the old key is removed
+// after the signatures it made have expired.
+func (r *Rollover) RolloverZSK(ctx context.Context, zone string) error {
+	old, err := r.activeKeys(zone, "zsk")
+	if err != nil {
+		return err
+	}
+
+	r.stage(zone, StagePrePublish)
+	created, err := r.Client.CreateCryptokey(zone, types.Cryptokey{
+		KeyType:   "zsk",
+		Active:    false,
+		Published: true,
+		Algorithm: old[0].Algorithm,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = r.wait(ctx, r.DNSKeyTTL); err != nil {
+		return err
+	}
+
+	r.stage(zone, StageActivate)
+	if err = r.Client.SetCryptokeyActive(zone, created.ID, true); err != nil {
+		return err
+	}
+
+	r.stage(zone, StageRetire)
+	for _, key := range old {
+		if err = r.Client.SetCryptokeyActive(zone, key.ID, false); err != nil {
+			return err
+		}
+	}
+
+	maxTTL, err := r.zoneMaxTTL(zone)
+	if err != nil {
+		return err
+	}
+	if err = r.wait(ctx, maxTTL); err != nil {
+		return err
+	}
+
+	r.stage(zone, StageRemoveOldKey)
+	return r.removeKeys(zone, old)
+}
+
+// RolloverKSK Performs a double-signature KSK rollover: the new key signs alongside the
+// old one while PublishDS or Registrar adds its DS at the parent, the old key is removed
+// once the old DS has expired from caches, and the DS records are published again to drop
+// the old DS.
+func (r *Rollover) RolloverKSK(ctx context.Context, zone string) error {
+	if r.PublishDS == nil && r.Registrar == nil {
+		return fmt.Errorf("KSK rollover of zone %s requires a PublishDS function or a Registrar", zone)
+	}
+
+	old, err := r.activeKeys(zone, "ksk")
+	if err != nil {
+		return err
+	}
+
+	r.stage(zone, StagePrePublish)
+	_, err = r.Client.CreateCryptokey(zone, types.Cryptokey{
+		KeyType:   "ksk",
+		Active:    true,
+		Published: true,
+		Algorithm: old[0].Algorithm,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err = r.wait(ctx, r.DNSKeyTTL); err != nil {
+		return err
+	}
+
+	// Both keys are active here, so the parent briefly carries both DS records.
+	r.stage(zone, StagePublishDS)
+	if err = r.publishDS(ctx, zone); err != nil {
+		return err
+	}
+
+	if err = r.wait(ctx, r.DSTTL); err != nil {
+		return err
+	}
+
+	r.stage(zone, StageRemoveOldKey)
+	if err = r.removeKeys(zone, old); err != nil {
+		return err
+	}
+
+	return r.publishDS(ctx, zone)
+}
+
+func (r *Rollover) publishDS(ctx context.Context, zone string) error {
+	if r.PublishDS != nil {
+		return r.PublishDS(ctx, zone)
+	}
+
+	return SubmitDS(ctx, r.Client, zone, r.Registrar)
+}
+
+func (r *Rollover) activeKeys(zone string, keyType string) ([]types.Cryptokey, error) {
+	keys, err := r.Client.ListCryptokeys(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var active []types.Cryptokey
+	for _, key := range keys {
+		if key.Active && key.KeyType == keyType {
+			active = append(active, key)
+		}
+	}
+
+	if len(active) == 0 {
+		return nil, fmt.Errorf("Zone %s has no active %s to roll over", zone, keyType)
+	}
+
+	return active, nil
+}
+
+func (r *Rollover) removeKeys(zone string, keys []types.Cryptokey) error {
+	for _, key := range keys {
+		if err := r.Client.DeleteCryptokey(zone, key.ID); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Rollover) zoneMaxTTL(zone string) (time.Duration, error) {
+	rrSets, err := r.Client.ListRecordsAsRRSet(zone)
+	if err != nil {
+		return 0, err
+	}
+
+	maxTTL := r.DNSKeyTTL
+	for _, rrSet := range rrSets {
+		if ttl := rrSet.TTLDuration(); ttl > maxTTL {
+			maxTTL = ttl
+		}
+	}
+
+	return maxTTL, nil
+}
+
+func (r *Rollover) wait(ctx context.Context, ttl time.Duration) error {
+	clock := r.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-clock.After(ttl + r.PropagationDelay):
+		return nil
+	}
+}
+
+func (r *Rollover) stage(zone string, stage string) {
+	if r.OnStage != nil {
+		r.OnStage(zone, stage)
+	}
+}
@@ -0,0 +1,90 @@
+package ops
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// SecondaryPollInterval Interval between checks for a completed zone transfer.
+var SecondaryPollInterval = 2 * time.Second
+
+// SecondaryResult Outcome of provisioning a zone on one secondary server.
+type SecondaryResult struct {
+	Server string
+	// Created is false when the zone already existed on the server.
+	Created bool
+	// Serial is the SOA serial transferred from the primaries.
+	Serial int64
+	Err    error
+}
+
+// ProvisionSecondaries Creates zone as a secondary zone of primaries, given as
+// "address[:port]", on every secondary server concurrently, triggers a transfer and waits
+// until the zone has a serial, or ctx is done. Zones that already exist are only
+// retransferred. The result of every server is returned in the order of secondaries.
+func ProvisionSecondaries(ctx context.Context, zone string, primaries []string, secondaries []*client.Client) []SecondaryResult {
+	results := make([]SecondaryResult, len(secondaries))
+	done := make(chan struct{}, len(secondaries))
+
+	for i, c := range secondaries {
+		go func(i int, c *client.Client) {
+			results[i] = provisionSecondary(ctx, zone, primaries, c)
+			done <- struct{}{}
+		}(i, c)
+	}
+	for range secondaries {
+		<-done
+	}
+
+	return results
+}
+
+func provisionSecondary(ctx context.Context, zone string, primaries []string, c *client.Client) SecondaryResult {
+	result := SecondaryResult{Server: c.ServerURL()}
+
+	exists, err := c.ZoneExists(zone)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+
+	if !exists {
+		_, err = c.CreateZone(types.ZoneInfo{
+			Name:    zone,
+			Kind:    types.KindSecondary,
+			Masters: primaries,
+		}, nil)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		result.Created = true
+	}
+
+	if result.Err = c.RetrieveZone(zone); result.Err != nil {
+		return result
+	}
+
+	for {
+		zoneInfo, err := c.GetZone(zone)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+		if zoneInfo.Serial > 0 {
+			result.Serial = zoneInfo.Serial
+			return result
+		}
+
+		select {
+		case <-ctx.Done():
+			result.Err = fmt.Errorf("Error waiting for transfer of zone: %s, %w", zone, ctx.Err())
+			return result
+		case <-time.After(SecondaryPollInterval):
+		}
+	}
+}
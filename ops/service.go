@@ -0,0 +1,79 @@
+package ops
+
+import (
+	"strings"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// DefaultServiceTTL TTL used for service records when ServiceSpec.TTL is zero.
+const DefaultServiceTTL = 60
+
+// ServiceSpec Describes a DNS-SD style service registration.
+type ServiceSpec struct {
+	Service  string // service name, e.g. "http"
+	Proto    string // protocol, e.g. "tcp"
+	Host     string // target host name
+	Port     int
+	Weight   int
+	Priority int
+	TTL      int
+	TXT      []string // TXT strings, e.g. "path=/api"
+}
+
+// ServiceName Returns the _service._proto owner name of a service in zone.
+func ServiceName(zone string, service string, proto string) string {
+	return "_" + strings.TrimPrefix(service, "_") + "._" + strings.TrimPrefix(proto, "_") + "." + zone
+}
+
+// RegisterService Replaces the SRV and TXT rrsets of the service in a single PATCH.
+// When spec has no TXT strings any existing TXT rrset of the service is removed.
+func RegisterService(c client.RecordAPI, zone string, spec ServiceSpec) error {
+	name := ServiceName(zone, spec.Service, spec.Proto)
+	ttl := spec.TTL
+	if ttl == 0 {
+		ttl = DefaultServiceTTL
+	}
+
+	srv := types.SRV{
+		Priority: spec.Priority,
+		Weight:   spec.Weight,
+		Port:     spec.Port,
+		Target:   spec.Host,
+	}
+
+	changes := []types.ResourceRecordSet{
+		{
+			Name:       name,
+			Type:       types.TypeSRV,
+			ChangeType: types.ChangeTypeReplace,
+			TTL:        ttl,
+			Records:    []types.Record{{Content: srv.String()}},
+		},
+	}
+
+	txt := types.ResourceRecordSet{
+		Name:       name,
+		Type:       types.TypeTXT,
+		ChangeType: types.ChangeTypeDelete,
+		TTL:        ttl,
+	}
+	for _, s := range spec.TXT {
+		txt.ChangeType = types.ChangeTypeReplace
+		txt.Records = append(txt.Records, types.Record{Content: types.QuoteTXT(s)})
+	}
+	changes = append(changes, txt)
+
+	return c.PatchRecordSets(zone, changes)
+}
+
+// DeregisterService Deletes the SRV and TXT rrsets of the service in a single PATCH.
+func DeregisterService(c client.RecordAPI, zone string, service string, proto string) error {
+	name := ServiceName(zone, service, proto)
+
+	return c.PatchRecordSets(zone, []types.ResourceRecordSet{
+		{Name: name, Type: types.TypeSRV, ChangeType: types.ChangeTypeDelete},
+		{Name: name, Type: types.TypeTXT, ChangeType: types.ChangeTypeDelete},
+	})
+}
@@ -0,0 +1,61 @@
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dmportella/powerdns/types"
+	"gopkg.in/yaml.v3"
+)
+
+// ZoneSpec Declarative description of the rrsets a zone should hold, e.g. kept in a
+// Git repository. Names not ending with a dot are relative to the zone, "@" is the apex.
+type ZoneSpec struct {
+	Zone       string                    `json:"zone" yaml:"zone"`
+	RecordSets []types.ResourceRecordSet `json:"rrsets" yaml:"rrsets"`
+}
+
+// LoadZoneSpec Reads a zone spec from path and qualifies its names. Files ending in
+// .yaml or .yml are read as YAML, using the JSON field names, others as JSON.
+func LoadZoneSpec(path string) (*ZoneSpec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := new(ZoneSpec)
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, spec)
+	default:
+		err = json.Unmarshal(data, spec)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("Error reading zone spec: %s, %w", path, err)
+	}
+	if spec.Zone == "" {
+		return nil, fmt.Errorf("Error reading zone spec: %s, missing zone", path)
+	}
+
+	spec.Qualify()
+	return spec, nil
+}
+
+// Qualify Makes the rrset names of the spec absolute.
+func (spec *ZoneSpec) Qualify() {
+	zone := spec.Zone
+	if !strings.HasSuffix(zone, ".") {
+		zone += "."
+	}
+
+	for i := range spec.RecordSets {
+		name := ApexName(zone, spec.RecordSets[i].Name)
+		if !strings.HasSuffix(name, ".") {
+			name += "." + zone
+		}
+		spec.RecordSets[i].Name = name
+	}
+}
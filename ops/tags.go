@@ -0,0 +1,92 @@
+package ops
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// TagCommentPrefix prefix of the rrset comment holding the JSON encoded tags.
+const TagCommentPrefix = "tags:"
+
+// Tags Returns the tags stored in the comments of rrSet.
+func Tags(rrSet *types.ResourceRecordSet) map[string]string {
+	tags := map[string]string{}
+	for _, comment := range rrSet.Comments {
+		if !strings.HasPrefix(comment.Content, TagCommentPrefix) {
+			continue
+		}
+
+		var parsed map[string]string
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(comment.Content, TagCommentPrefix)), &parsed); err != nil {
+			continue
+		}
+		for key, value := range parsed {
+			tags[key] = value
+		}
+	}
+
+	return tags
+}
+
+// SetTags Replaces the tag comment of rrSet with tags, keeping any other comments.
+func SetTags(rrSet *types.ResourceRecordSet, tags map[string]string) {
+	comments := make([]types.Comment, 0, len(rrSet.Comments)+1)
+	for _, comment := range rrSet.Comments {
+		if !strings.HasPrefix(comment.Content, TagCommentPrefix) {
+			comments = append(comments, comment)
+		}
+	}
+
+	if len(tags) > 0 {
+		encoded, _ := json.Marshal(tags)
+		comments = append(comments, types.Comment{Content: TagCommentPrefix + string(encoded)})
+	}
+
+	rrSet.Comments = comments
+}
+
+// TagRecord Merges tags into the tags of the rrset of given name and type.
+// A tag with an empty value is removed.
+func TagRecord(c client.RecordAPI, zone string, name string, tpe string, tags map[string]string) error {
+	rrSet, err := c.GetRecordSet(zone, name, tpe)
+	if err != nil {
+		return err
+	}
+	if rrSet == nil {
+		return fmt.Errorf("Error tagging record set: %s, it does not exist", name+types.IDSeparator+tpe)
+	}
+
+	merged := Tags(rrSet)
+	for key, value := range tags {
+		if value == "" {
+			delete(merged, key)
+		} else {
+			merged[key] = value
+		}
+	}
+
+	SetTags(rrSet, merged)
+	_, err = c.ReplaceRecordSet(zone, *rrSet)
+	return err
+}
+
+// ListRecordsByTag Returns the rrsets of zone tagged with key set to value.
+func ListRecordsByTag(c client.RecordAPI, zone string, key string, value string) ([]types.ResourceRecordSet, error) {
+	rrSets, err := c.ListRecordsAsRRSet(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var tagged []types.ResourceRecordSet
+	for _, rrSet := range rrSets {
+		if tag, ok := Tags(&rrSet)[key]; ok && tag == value {
+			tagged = append(tagged, rrSet)
+		}
+	}
+
+	return tagged, nil
+}
@@ -0,0 +1,66 @@
+package ops
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+var templateVar = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+
+// RecordTemplate Reusable set of rrsets whose names and contents may contain {{var}}
+// placeholders, e.g. "{{env}}.example.com." or "{{web_ip}}".
+type RecordTemplate struct {
+	RecordSets []types.ResourceRecordSet `json:"rrsets"`
+}
+
+// Render Returns the template rrsets with every placeholder substituted from vars.
+// Placeholders without a value are reported as an error.
+func (template *RecordTemplate) Render(vars map[string]string) ([]types.ResourceRecordSet, error) {
+	var missing []string
+	substitute := func(s string) string {
+		return templateVar.ReplaceAllStringFunc(s, func(placeholder string) string {
+			name := templateVar.FindStringSubmatch(placeholder)[1]
+			value, ok := vars[name]
+			if !ok {
+				missing = append(missing, name)
+			}
+			return value
+		})
+	}
+
+	rrSets := make([]types.ResourceRecordSet, 0, len(template.RecordSets))
+	for _, tmpl := range template.RecordSets {
+		rrSet := tmpl
+		rrSet.Name = substitute(tmpl.Name)
+		rrSet.Records = make([]types.Record, len(tmpl.Records))
+		for i, record := range tmpl.Records {
+			record.Content = substitute(record.Content)
+			rrSet.Records[i] = record
+		}
+		rrSets = append(rrSets, rrSet)
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("Missing template variables: %v", missing)
+	}
+
+	return rrSets, nil
+}
+
+// ApplyRecordTemplate Renders template with vars and replaces the resulting rrsets in
+// zone in a single PATCH.
+func ApplyRecordTemplate(c client.RecordAPI, zone string, template RecordTemplate, vars map[string]string) error {
+	rrSets, err := template.Render(vars)
+	if err != nil {
+		return err
+	}
+
+	for i := range rrSets {
+		rrSets[i].ChangeType = types.ChangeTypeReplace
+	}
+
+	return c.PatchRecordSets(zone, rrSets)
+}
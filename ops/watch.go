@@ -0,0 +1,63 @@
+package ops
+
+import (
+	"context"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// ZoneEvent Change of a zone seen by WatchZone.
+type ZoneEvent struct {
+	Zone string
+	// Hash is the types.ZoneHash of the zone after the change.
+	Hash string
+	// Changes turn the previous state into the current one; the first event of a watch
+	// lists every rrset as created.
+	Changes []Change
+	Err     error
+}
+
+// WatchZone Polls zone every interval and sends an event whenever its ZoneHash changes,
+// starting with one for the initial state, and one with Err set when a poll fails. The
+// channel is closed once ctx is done.
+func WatchZone(ctx context.Context, c client.RecordAPI, zone string, interval time.Duration) <-chan ZoneEvent {
+	events := make(chan ZoneEvent)
+
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		var previous []types.ResourceRecordSet
+		hash := ""
+		for {
+			var event *ZoneEvent
+			rrSets, err := client.RecordAPIWithContext(ctx, c).ListRecordsAsRRSet(zone)
+			if err != nil {
+				event = &ZoneEvent{Zone: zone, Hash: hash, Err: err}
+			} else if current := types.ZoneHash(rrSets); current != hash {
+				event = &ZoneEvent{Zone: zone, Hash: current, Changes: Diff(rrSets, previous)}
+				previous, hash = rrSets, current
+			}
+
+			if event != nil && ctx.Err() == nil {
+				select {
+				case events <- *event:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return events
+}
@@ -0,0 +1,171 @@
+// Package rfc2136 bridges RFC 2136 dynamic DNS UPDATE messages to the PowerDNS HTTP API,
+// so legacy DDNS clients (nsupdate, DHCP servers, routers) can update API-only deployments.
+package rfc2136
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+	"github.com/miekg/dns"
+)
+
+// ErrNoTSIGSecrets Returned by ListenAndServe when no TSIG secrets are configured and
+// unsigned updates are not explicitly allowed.
+var ErrNoTSIGSecrets = errors.New("no TSIG secrets configured for signed updates")
+
+// Gateway DNS server accepting UPDATE messages and applying them as rrset PATCHes.
+type Gateway struct {
+	Client client.RecordAPI
+	// Zones maps zone names as sent in UPDATE messages to PowerDNS zone IDs. When nil
+	// every zone is accepted and its name used as ID; otherwise other zones are refused.
+	Zones map[string]string
+	// TSIGSecrets maps TSIG key names to base64 secrets. Updates must be signed with
+	// one of them unless AllowUnsigned is set.
+	TSIGSecrets map[string]string
+	// AllowUnsigned accepts updates without a TSIG signature. Signed updates are still
+	// verified against TSIGSecrets.
+	AllowUnsigned bool
+	// Logger receives a line per rejected or failed update; nil disables logging.
+	Logger *log.Logger
+}
+
+// ListenAndServe Serves UPDATE messages on addr over UDP and TCP until one listener fails.
+// It refuses to start without TSIG secrets unless AllowUnsigned is set.
+func (gateway *Gateway) ListenAndServe(addr string) error {
+	if len(gateway.TSIGSecrets) == 0 && !gateway.AllowUnsigned {
+		return ErrNoTSIGSecrets
+	}
+
+	errs := make(chan error, 2)
+	for _, network := range []string{"udp", "tcp"} {
+		server := &dns.Server{
+			Addr:       addr,
+			Net:        network,
+			Handler:    gateway,
+			TsigSecret: gateway.tsigSecrets(),
+		}
+		go func() { errs <- server.ListenAndServe() }()
+	}
+
+	return <-errs
+}
+
+// ServeDNS Handles a single DNS message.
+func (gateway *Gateway) ServeDNS(w dns.ResponseWriter, req *dns.Msg) {
+	resp := new(dns.Msg)
+	resp.SetRcode(req, gateway.handle(w, req))
+
+	if tsig := req.IsTsig(); tsig != nil && w.TsigStatus() == nil {
+		resp.SetTsig(tsig.Hdr.Name, tsig.Algorithm, 300, time.Now().Unix())
+	}
+
+	w.WriteMsg(resp)
+}
+
+func (gateway *Gateway) handle(w dns.ResponseWriter, req *dns.Msg) int {
+	if req.Opcode != dns.OpcodeUpdate {
+		return dns.RcodeNotImplemented
+	}
+
+	if len(req.Question) != 1 || req.Question[0].Qtype != dns.TypeSOA {
+		return dns.RcodeFormatError
+	}
+
+	if req.IsTsig() == nil {
+		if !gateway.AllowUnsigned {
+			gateway.logf("refusing unsigned update from %s", w.RemoteAddr())
+			return dns.RcodeRefused
+		}
+	} else if err := w.TsigStatus(); err != nil {
+		gateway.logf("refusing update from %s: %s", w.RemoteAddr(), err)
+		return dns.RcodeNotAuth
+	}
+
+	zoneName := dns.Fqdn(req.Question[0].Name)
+	zoneID, ok := gateway.zoneID(zoneName)
+	if !ok {
+		return dns.RcodeNotAuth
+	}
+
+	for _, rr := range append(req.Answer, req.Ns...) {
+		if !dns.IsSubDomain(zoneName, rr.Header().Name) {
+			return dns.RcodeNotZone
+		}
+	}
+
+	rrSets, err := gateway.Client.ListRecordsAsRRSet(zoneID)
+	if err != nil {
+		gateway.logf("error reading zone %s: %s", zoneID, err)
+		return dns.RcodeServerFailure
+	}
+
+	state := newZoneState(rrSets)
+	if rcode := state.checkPrerequisites(req.Answer); rcode != dns.RcodeSuccess {
+		return rcode
+	}
+
+	for _, rr := range req.Ns {
+		if rcode := state.apply(zoneName, rr); rcode != dns.RcodeSuccess {
+			return rcode
+		}
+	}
+
+	changes := state.changes()
+	if len(changes) == 0 {
+		return dns.RcodeSuccess
+	}
+
+	if err = gateway.Client.PatchRecordSets(zoneID, changes); err != nil {
+		gateway.logf("error updating zone %s: %s", zoneID, err)
+		return dns.RcodeServerFailure
+	}
+
+	return dns.RcodeSuccess
+}
+
+func (gateway *Gateway) zoneID(zone string) (string, bool) {
+	if gateway.Zones == nil {
+		return zone, true
+	}
+
+	for name, id := range gateway.Zones {
+		if strings.EqualFold(dns.Fqdn(name), zone) {
+			return id, true
+		}
+	}
+
+	return "", false
+}
+
+func (gateway *Gateway) tsigSecrets() map[string]string {
+	if gateway.TSIGSecrets == nil {
+		return nil
+	}
+
+	secrets := make(map[string]string, len(gateway.TSIGSecrets))
+	for name, secret := range gateway.TSIGSecrets {
+		secrets[dns.Fqdn(name)] = secret
+	}
+
+	return secrets
+}
+
+func (gateway *Gateway) logf(format string, args ...interface{}) {
+	if gateway.Logger != nil {
+		gateway.Logger.Printf(format, args...)
+	}
+}
+
+// Content Returns the record content of rr in the presentation format PowerDNS uses.
+func Content(rr dns.RR) string {
+	return strings.TrimSpace(strings.TrimPrefix(rr.String(), rr.Header().String()))
+}
+
+// RecordType Returns the record type of rr.
+func RecordType(rr dns.RR) types.RecordType {
+	return types.RecordType(dns.TypeToString[rr.Header().Rrtype])
+}
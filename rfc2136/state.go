@@ -0,0 +1,194 @@
+package rfc2136
+
+import (
+	"strings"
+
+	"github.com/dmportella/powerdns/types"
+	"github.com/miekg/dns"
+)
+
+type rrSetKey struct {
+	name string
+	tpe  types.RecordType
+}
+
+// Working copy of the zone rrsets the update section is applied to
+type zoneState struct {
+	rrSets  map[rrSetKey]*types.ResourceRecordSet
+	changed map[rrSetKey]bool
+}
+
+func newZoneState(rrSets []types.ResourceRecordSet) *zoneState {
+	state := &zoneState{
+		rrSets:  map[rrSetKey]*types.ResourceRecordSet{},
+		changed: map[rrSetKey]bool{},
+	}
+	for i := range rrSets {
+		rrSet := rrSets[i]
+		state.rrSets[keyOf(rrSet.Name, rrSet.Type)] = &rrSet
+	}
+
+	return state
+}
+
+func keyOf(name string, tpe types.RecordType) rrSetKey {
+	return rrSetKey{name: strings.ToLower(dns.Fqdn(name)), tpe: tpe}
+}
+
+// Evaluates the prerequisite section (RFC 2136 section 3.2)
+func (state *zoneState) checkPrerequisites(prereqs []dns.RR) int {
+	for _, rr := range prereqs {
+		header := rr.Header()
+		tpe := RecordType(rr)
+
+		switch header.Class {
+		case dns.ClassANY:
+			if header.Rrtype == dns.TypeANY {
+				if !state.nameInUse(header.Name) {
+					return dns.RcodeNameError
+				}
+			} else if rrSet := state.rrSets[keyOf(header.Name, tpe)]; rrSet == nil || len(rrSet.Records) == 0 {
+				return dns.RcodeNXRrset
+			}
+		case dns.ClassNONE:
+			if header.Rrtype == dns.TypeANY {
+				if state.nameInUse(header.Name) {
+					return dns.RcodeYXDomain
+				}
+			} else if rrSet := state.rrSets[keyOf(header.Name, tpe)]; rrSet != nil && len(rrSet.Records) > 0 {
+				return dns.RcodeYXRrset
+			}
+		case dns.ClassINET:
+			rrSet := state.rrSets[keyOf(header.Name, tpe)]
+			if rrSet == nil || indexOf(rrSet, Content(rr)) < 0 {
+				return dns.RcodeNXRrset
+			}
+		default:
+			return dns.RcodeFormatError
+		}
+	}
+
+	return dns.RcodeSuccess
+}
+
+// Applies one RR of the update section (RFC 2136 section 3.4.2)
+func (state *zoneState) apply(zone string, rr dns.RR) int {
+	header := rr.Header()
+	key := keyOf(header.Name, RecordType(rr))
+	apex := strings.EqualFold(dns.Fqdn(header.Name), zone)
+
+	switch header.Class {
+	case dns.ClassINET:
+		if key.tpe == types.TypeSOA {
+			if apex {
+				state.replaceSOA(key, rr)
+			}
+			return dns.RcodeSuccess
+		}
+		rrSet := state.rrSets[key]
+		if rrSet == nil {
+			rrSet = &types.ResourceRecordSet{Name: dns.Fqdn(header.Name), Type: key.tpe}
+			state.rrSets[key] = rrSet
+		}
+		rrSet.TTL = int(header.Ttl)
+		if content := Content(rr); indexOf(rrSet, content) < 0 {
+			rrSet.Records = append(rrSet.Records, types.Record{Content: content})
+		}
+		state.changed[key] = true
+	case dns.ClassANY:
+		if header.Rrtype != dns.TypeANY {
+			if apex && (key.tpe == types.TypeSOA || key.tpe == types.TypeNS) {
+				return dns.RcodeSuccess
+			}
+			state.clear(key)
+			return dns.RcodeSuccess
+		}
+		for other := range state.rrSets {
+			if other.name == key.name && !(apex && (other.tpe == types.TypeSOA || other.tpe == types.TypeNS)) {
+				state.clear(other)
+			}
+		}
+	case dns.ClassNONE:
+		rrSet := state.rrSets[key]
+		if rrSet == nil || key.tpe == types.TypeSOA {
+			return dns.RcodeSuccess
+		}
+		i := indexOf(rrSet, Content(rr))
+		// the last apex NS record is never deleted (RFC 2136 section 3.4.2.4)
+		if apex && key.tpe == types.TypeNS && len(rrSet.Records) == 1 {
+			return dns.RcodeSuccess
+		}
+		if i >= 0 {
+			rrSet.Records = append(rrSet.Records[:i], rrSet.Records[i+1:]...)
+			state.changed[key] = true
+		}
+	default:
+		return dns.RcodeFormatError
+	}
+
+	return dns.RcodeSuccess
+}
+
+// Replaces the apex SOA with rr when its serial is higher in sequence space arithmetic,
+// and ignores it otherwise (RFC 2136 section 3.4.2.2)
+func (state *zoneState) replaceSOA(key rrSetKey, rr dns.RR) {
+	soa, ok := rr.(*dns.SOA)
+	rrSet := state.rrSets[key]
+	if !ok || rrSet == nil || len(rrSet.Records) == 0 {
+		return
+	}
+
+	current, err := types.ParseSOA(rrSet.Records[0].Content)
+	if err != nil || int32(soa.Serial-uint32(current.Serial)) <= 0 {
+		return
+	}
+
+	rrSet.TTL = int(soa.Hdr.Ttl)
+	rrSet.Records = []types.Record{{Content: Content(rr)}}
+	state.changed[key] = true
+}
+
+func (state *zoneState) clear(key rrSetKey) {
+	if rrSet := state.rrSets[key]; rrSet != nil {
+		rrSet.Records = nil
+		state.changed[key] = true
+	}
+}
+
+func (state *zoneState) nameInUse(name string) bool {
+	name = strings.ToLower(dns.Fqdn(name))
+	for key, rrSet := range state.rrSets {
+		if key.name == name && len(rrSet.Records) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the PATCH changes for every modified rrset
+func (state *zoneState) changes() []types.ResourceRecordSet {
+	var changes []types.ResourceRecordSet
+	for key := range state.changed {
+		rrSet := *state.rrSets[key]
+		if len(rrSet.Records) == 0 {
+			rrSet.ChangeType = types.ChangeTypeDelete
+		} else {
+			rrSet.ChangeType = types.ChangeTypeReplace
+		}
+		changes = append(changes, rrSet)
+	}
+	types.SortRecordSets(changes)
+
+	return changes
+}
+
+func indexOf(rrSet *types.ResourceRecordSet, content string) int {
+	for i, record := range rrSet.Records {
+		if strings.EqualFold(record.Content, content) {
+			return i
+		}
+	}
+
+	return -1
+}
@@ -0,0 +1,86 @@
+package rfc2136
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/dmportella/powerdns/types"
+	"github.com/miekg/dns"
+)
+
+const testZone = "example.com."
+
+func newTestState() *zoneState {
+	return newZoneState([]types.ResourceRecordSet{
+		{Name: testZone, Type: types.TypeSOA, TTL: 3600, Records: []types.Record{
+			{Content: "ns1.example.com. hostmaster.example.com. 2024010100 10800 3600 604800 3600"},
+		}},
+		{Name: testZone, Type: types.TypeNS, TTL: 3600, Records: []types.Record{
+			{Content: "ns1.example.com."},
+			{Content: "ns2.example.com."},
+		}},
+	})
+}
+
+func mustRR(t *testing.T, text string, class uint16) dns.RR {
+	t.Helper()
+
+	rr, err := dns.NewRR(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rr.Header().Class = class
+
+	return rr
+}
+
+func TestApplySOAReplacesOnHigherSerial(t *testing.T) {
+	cases := map[string]struct {
+		serial string
+		want   string
+	}{
+		"lower serial": {
+			serial: "2023120100",
+			want:   "ns1.example.com. hostmaster.example.com. 2024010100 10800 3600 604800 3600",
+		},
+		"same serial": {
+			serial: "2024010100",
+			want:   "ns1.example.com. hostmaster.example.com. 2024010100 10800 3600 604800 3600",
+		},
+		"higher serial": {
+			serial: "2024010101",
+			want:   "ns1.example.com. hostmaster.example.com. 2024010101 10800 3600 604800 3600",
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			state := newTestState()
+			rr := mustRR(t, "example.com. 3600 IN SOA ns1.example.com. hostmaster.example.com. "+tc.serial+" 10800 3600 604800 3600", dns.ClassINET)
+
+			if rcode := state.apply(testZone, rr); rcode != dns.RcodeSuccess {
+				t.Fatalf("apply() = %s", dns.RcodeToString[rcode])
+			}
+
+			soa := state.rrSets[keyOf(testZone, types.TypeSOA)]
+			if want := []types.Record{{Content: tc.want}}; !reflect.DeepEqual(soa.Records, want) {
+				t.Errorf("SOA records = %+v, want %+v", soa.Records, want)
+			}
+		})
+	}
+}
+
+func TestApplyKeepsLastApexNS(t *testing.T) {
+	state := newTestState()
+	for _, nameserver := range []string{"ns1.example.com.", "ns2.example.com."} {
+		rr := mustRR(t, "example.com. 0 IN NS "+nameserver, dns.ClassNONE)
+		if rcode := state.apply(testZone, rr); rcode != dns.RcodeSuccess {
+			t.Fatalf("apply() = %s", dns.RcodeToString[rcode])
+		}
+	}
+
+	ns := state.rrSets[keyOf(testZone, types.TypeNS)]
+	if want := []types.Record{{Content: "ns2.example.com."}}; !reflect.DeepEqual(ns.Records, want) {
+		t.Errorf("apex NS records = %+v, want %+v", ns.Records, want)
+	}
+}
@@ -0,0 +1,158 @@
+// Package state keeps the last known state of zones on disk, so long-running controllers
+// can tell what changed since their last successful sync across restarts.
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/ops"
+	"github.com/dmportella/powerdns/types"
+	bolt "go.etcd.io/bbolt"
+)
+
+var zonesBucket = []byte("zones")
+
+// Entry Last known state of a zone.
+type Entry struct {
+	Zone       string                    `json:"zone"`
+	SyncedAt   time.Time                 `json:"synced_at"`
+	RecordSets []types.ResourceRecordSet `json:"rrsets"`
+}
+
+// Cache Zone states stored in a bbolt database file.
+type Cache struct {
+	db *bolt.DB
+}
+
+// Open Opens, creating it if needed, the cache database at path. The file is locked
+// while the cache is open.
+func Open(path string) (*Cache, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("Error opening state cache: %s, %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(zonesBucket)
+		return err
+	})
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &Cache{db: db}, nil
+}
+
+// Close Closes the database.
+func (cache *Cache) Close() error {
+	return cache.db.Close()
+}
+
+// Get Returns the cached state of zone, or nil when the zone was never stored.
+func (cache *Cache) Get(zone string) (*Entry, error) {
+	var entry *Entry
+	err := cache.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(zonesBucket).Get([]byte(zone))
+		if data == nil {
+			return nil
+		}
+
+		entry = new(Entry)
+		return json.Unmarshal(data, entry)
+	})
+
+	return entry, err
+}
+
+// Put Stores rrSets as the state of zone.
+func (cache *Cache) Put(zone string, rrSets []types.ResourceRecordSet) error {
+	data, err := json.Marshal(Entry{
+		Zone:       zone,
+		SyncedAt:   time.Now().UTC(),
+		RecordSets: rrSets,
+	})
+	if err != nil {
+		return err
+	}
+
+	return cache.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(zonesBucket).Put([]byte(zone), data)
+	})
+}
+
+// Delete Forgets the state of zone.
+func (cache *Cache) Delete(zone string) error {
+	return cache.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(zonesBucket).Delete([]byte(zone))
+	})
+}
+
+// Zones Returns the names of the cached zones.
+func (cache *Cache) Zones() ([]string, error) {
+	var zones []string
+	err := cache.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(zonesBucket).ForEach(func(key []byte, _ []byte) error {
+			zones = append(zones, string(key))
+			return nil
+		})
+	})
+
+	return zones, err
+}
+
+// Changes Returns the changes turning the cached state of zone into its live state, i.e.
+// what changed since the last Sync. Every live rrset is reported as created when the
+// zone is not cached yet.
+func (cache *Cache) Changes(c client.RecordAPI, zone string) ([]ops.Change, error) {
+	live, err := c.ListRecordsAsRRSet(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	return cache.changes(zone, live)
+}
+
+// Sync Returns the changes since the last Sync, like Changes, and stores the live state.
+func (cache *Cache) Sync(c client.RecordAPI, zone string) ([]ops.Change, error) {
+	live, err := c.ListRecordsAsRRSet(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	changes, err := cache.changes(zone, live)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cache.Put(zone, live); err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+func (cache *Cache) changes(zone string, live []types.ResourceRecordSet) ([]ops.Change, error) {
+	entry, err := cache.Get(zone)
+	if err != nil {
+		return nil, err
+	}
+
+	var cached []types.ResourceRecordSet
+	if entry != nil {
+		cached = entry.RecordSets
+	}
+
+	// Diff reports the SOA only when the desired side has one; its serial always changes
+	withoutSOA := make([]types.ResourceRecordSet, 0, len(live))
+	for _, rrSet := range live {
+		if rrSet.Type != types.TypeSOA {
+			withoutSOA = append(withoutSOA, rrSet)
+		}
+	}
+
+	return ops.Diff(withoutSOA, cached), nil
+}
@@ -0,0 +1,118 @@
+// Package tenant lets a platform holding a single PowerDNS API key offer DNS self-service
+// to internal teams. The Broker issues opaque tokens to tenants, each limited to an
+// allowlist of zones, and hands out clients enforcing that allowlist.
+//
+// Tokens are checked in the library: the broker must run in a process the tenants cannot
+// reach into, e.g. a platform API that receives the token with each request.
+package tenant
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// ErrUnauthorized Returned for tokens that were never issued or were revoked.
+var ErrUnauthorized = errors.New("invalid tenant token")
+
+// ErrZoneNotAllowed Returned for operations on zones outside the allowlist of a tenant.
+var ErrZoneNotAllowed = errors.New("zone not allowed for tenant")
+
+// Tenant Team or service given access to a set of zones.
+type Tenant struct {
+	Name string
+	// Zones is the allowlist of zones the tenant may read, change, create and delete.
+	Zones []string
+}
+
+// Allows Checks if zone is in the allowlist of the tenant.
+func (tenant *Tenant) Allows(zone string) bool {
+	for _, allowed := range tenant.Zones {
+		if types.EqualNames(allowed, zone) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Broker Issues tenant tokens and the scoped clients they unlock. It is safe for
+// concurrent use.
+type Broker struct {
+	provider client.Provider
+	mutex    sync.RWMutex
+	// tenants are keyed by the SHA-256 of their tokens, so a memory dump reveals no token
+	tenants map[string]*Tenant
+}
+
+// NewBroker Returns a broker whose clients call provider, usually the *client.Client
+// holding the real API key.
+func NewBroker(provider client.Provider) *Broker {
+	return &Broker{
+		provider: provider,
+		tenants:  make(map[string]*Tenant),
+	}
+}
+
+// Issue Returns a new random token for tenant. A tenant may hold several tokens, e.g.
+// while rotating them.
+func (broker *Broker) Issue(tenant Tenant) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("Error issuing token for tenant: %s, %w", tenant.Name, err)
+	}
+	token := hex.EncodeToString(secret)
+
+	tenant.Zones = append([]string(nil), tenant.Zones...)
+
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	broker.tenants[tokenKey(token)] = &tenant
+
+	return token, nil
+}
+
+// Revoke Invalidates token; clients already returned for it keep working.
+func (broker *Broker) Revoke(token string) {
+	broker.mutex.Lock()
+	defer broker.mutex.Unlock()
+
+	delete(broker.tenants, tokenKey(token))
+}
+
+// Tenant Returns the tenant token was issued to, or ErrUnauthorized.
+func (broker *Broker) Tenant(token string) (*Tenant, error) {
+	broker.mutex.RLock()
+	defer broker.mutex.RUnlock()
+
+	tenant, ok := broker.tenants[tokenKey(token)]
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+
+	copied := *tenant
+	copied.Zones = append([]string(nil), tenant.Zones...)
+	return &copied, nil
+}
+
+// Client Returns the client of the tenant token was issued to, or ErrUnauthorized.
+func (broker *Broker) Client(token string) (*Client, error) {
+	tenant, err := broker.Tenant(token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{tenant: tenant, provider: broker.provider}, nil
+}
+
+func tokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,318 @@
+package tenant
+
+import (
+	"fmt"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// Client Provider limited to the zones of a tenant. Operations on other zones fail with
+// ErrZoneNotAllowed without reaching the server, and ListZones only returns allowed zones.
+type Client struct {
+	tenant   *Tenant
+	provider client.Provider
+}
+
+var _ client.Provider = (*Client)(nil)
+
+// Tenant Returns the tenant of the client.
+func (tenantClient *Client) Tenant() Tenant {
+	return *tenantClient.tenant
+}
+
+// Checks zone against the allowlist of the tenant
+func (tenantClient *Client) check(zone string) error {
+	if !tenantClient.tenant.Allows(zone) {
+		return fmt.Errorf("Error accessing zone: %s, tenant: %s: %w", zone, tenantClient.tenant.Name, ErrZoneNotAllowed)
+	}
+
+	return nil
+}
+
+// ListZones Returns the allowed zones that exist.
+func (tenantClient *Client) ListZones() ([]types.ZoneInfo, error) {
+	zones, err := tenantClient.provider.ListZones()
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make([]types.ZoneInfo, 0, len(tenantClient.tenant.Zones))
+	for _, zone := range zones {
+		if tenantClient.tenant.Allows(zone.Name) {
+			allowed = append(allowed, zone)
+		}
+	}
+
+	return allowed, nil
+}
+
+// GetZone Returns zone including its record sets.
+func (tenantClient *Client) GetZone(zone string) (*types.ZoneInfo, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return nil, err
+	}
+
+	return tenantClient.provider.GetZone(zone)
+}
+
+// ZoneExists Checks if zone exists.
+func (tenantClient *Client) ZoneExists(zone string) (bool, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return false, err
+	}
+
+	return tenantClient.provider.ZoneExists(zone)
+}
+
+// CreateZone Creates zone.
+func (tenantClient *Client) CreateZone(zone types.ZoneInfo, soa *types.SOATemplate) (*types.ZoneInfo, error) {
+	if err := tenantClient.check(zone.Name); err != nil {
+		return nil, err
+	}
+
+	return tenantClient.provider.CreateZone(zone, soa)
+}
+
+// DeleteZone Deletes zone.
+func (tenantClient *Client) DeleteZone(zone string, opts client.DeleteZoneOptions) (*client.DeletedZone, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return nil, err
+	}
+
+	return tenantClient.provider.DeleteZone(zone, opts)
+}
+
+// SetPresigned Marks zone as presigned or not.
+func (tenantClient *Client) SetPresigned(zone string, presigned bool) error {
+	if err := tenantClient.check(zone); err != nil {
+		return err
+	}
+
+	return tenantClient.provider.SetPresigned(zone, presigned)
+}
+
+// SetSOAEditAPI Changes the SOA-EDIT-API strategy of zone.
+func (tenantClient *Client) SetSOAEditAPI(zone string, value string) error {
+	if err := tenantClient.check(zone); err != nil {
+		return err
+	}
+
+	return tenantClient.provider.SetSOAEditAPI(zone, value)
+}
+
+// ListRecords Returns all records of zone.
+func (tenantClient *Client) ListRecords(zone string) ([]types.Record, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return nil, err
+	}
+
+	return tenantClient.provider.ListRecords(zone)
+}
+
+// ListRecordsAsRRSet Returns the record sets of zone.
+func (tenantClient *Client) ListRecordsAsRRSet(zone string) ([]types.ResourceRecordSet, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return nil, err
+	}
+
+	return tenantClient.provider.ListRecordsAsRRSet(zone)
+}
+
+// GetRecordSet Returns the record set of name and type, or nil when it does not exist.
+func (tenantClient *Client) GetRecordSet(zone string, name string, tpe string) (*types.ResourceRecordSet, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return nil, err
+	}
+
+	return tenantClient.provider.GetRecordSet(zone, name, tpe)
+}
+
+// ListRecordsByNameAndType Returns the records of name and type.
+func (tenantClient *Client) ListRecordsByNameAndType(zone string, name string, tpe string) ([]types.Record, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return nil, err
+	}
+
+	return tenantClient.provider.ListRecordsByNameAndType(zone, name, tpe)
+}
+
+// ListRecordsByID Returns the records of the record set identified by recID.
+func (tenantClient *Client) ListRecordsByID(zone string, recID string) ([]types.Record, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return nil, err
+	}
+
+	return tenantClient.provider.ListRecordsByID(zone, recID)
+}
+
+// GetRecordsByName Returns the record sets of name.
+func (tenantClient *Client) GetRecordsByName(zone string, name string) ([]types.ResourceRecordSet, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return nil, err
+	}
+
+	return tenantClient.provider.GetRecordsByName(zone, name)
+}
+
+// GetRecordsByType Returns the record sets of type tpe.
+func (tenantClient *Client) GetRecordsByType(zone string, tpe string) ([]types.ResourceRecordSet, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return nil, err
+	}
+
+	return tenantClient.provider.GetRecordsByType(zone, tpe)
+}
+
+// RecordExists Checks if the record set of name and type has records.
+func (tenantClient *Client) RecordExists(zone string, name string, tpe string) (bool, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return false, err
+	}
+
+	return tenantClient.provider.RecordExists(zone, name, tpe)
+}
+
+// RecordExistsByID Checks if the record set identified by recID has records.
+func (tenantClient *Client) RecordExistsByID(zone string, recID string) (bool, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return false, err
+	}
+
+	return tenantClient.provider.RecordExistsByID(zone, recID)
+}
+
+// CreateRecord Replaces the record set of the record with that single record.
+func (tenantClient *Client) CreateRecord(zone string, record types.Record) (string, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return "", err
+	}
+
+	return tenantClient.provider.CreateRecord(zone, record)
+}
+
+// ReplaceRecordSet Creates or replaces rrSet.
+func (tenantClient *Client) ReplaceRecordSet(zone string, rrSet types.ResourceRecordSet) (string, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return "", err
+	}
+
+	return tenantClient.provider.ReplaceRecordSet(zone, rrSet)
+}
+
+// DeleteRecordSet Deletes the record set of name and type.
+func (tenantClient *Client) DeleteRecordSet(zone string, name string, tpe string) error {
+	if err := tenantClient.check(zone); err != nil {
+		return err
+	}
+
+	return tenantClient.provider.DeleteRecordSet(zone, name, tpe)
+}
+
+// DeleteRecordSetByID Deletes the record set identified by recID.
+func (tenantClient *Client) DeleteRecordSetByID(zone string, recID string) error {
+	if err := tenantClient.check(zone); err != nil {
+		return err
+	}
+
+	return tenantClient.provider.DeleteRecordSetByID(zone, recID)
+}
+
+// RenameRecord Moves the record set of oldName and type to newName.
+func (tenantClient *Client) RenameRecord(zone string, oldName string, newName string, tpe string) error {
+	if err := tenantClient.check(zone); err != nil {
+		return err
+	}
+
+	return tenantClient.provider.RenameRecord(zone, oldName, newName, tpe)
+}
+
+// PatchRecordSets Applies the record set changes to zone atomically.
+func (tenantClient *Client) PatchRecordSets(zone string, rrSets []types.ResourceRecordSet) error {
+	if err := tenantClient.check(zone); err != nil {
+		return err
+	}
+
+	return tenantClient.provider.PatchRecordSets(zone, rrSets)
+}
+
+// ListMetadata Returns all metadata of zone.
+func (tenantClient *Client) ListMetadata(zone string) ([]types.Metadata, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return nil, err
+	}
+
+	return tenantClient.provider.ListMetadata(zone)
+}
+
+// GetMetadata Returns the values of a metadata kind of zone.
+func (tenantClient *Client) GetMetadata(zone string, kind string) ([]string, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return nil, err
+	}
+
+	return tenantClient.provider.GetMetadata(zone, kind)
+}
+
+// SetMetadata Replaces the values of a metadata kind of zone.
+func (tenantClient *Client) SetMetadata(zone string, kind string, values []string) error {
+	if err := tenantClient.check(zone); err != nil {
+		return err
+	}
+
+	return tenantClient.provider.SetMetadata(zone, kind, values)
+}
+
+// DeleteMetadata Deletes a metadata kind of zone.
+func (tenantClient *Client) DeleteMetadata(zone string, kind string) error {
+	if err := tenantClient.check(zone); err != nil {
+		return err
+	}
+
+	return tenantClient.provider.DeleteMetadata(zone, kind)
+}
+
+// ListCryptokeys Returns the DNSSEC keys of zone.
+func (tenantClient *Client) ListCryptokeys(zone string) ([]types.Cryptokey, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return nil, err
+	}
+
+	return tenantClient.provider.ListCryptokeys(zone)
+}
+
+// GetCryptokey Returns a DNSSEC key of zone including its private key.
+func (tenantClient *Client) GetCryptokey(zone string, id int) (*types.Cryptokey, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return nil, err
+	}
+
+	return tenantClient.provider.GetCryptokey(zone, id)
+}
+
+// CreateCryptokey Creates a DNSSEC key in zone.
+func (tenantClient *Client) CreateCryptokey(zone string, key types.Cryptokey) (*types.Cryptokey, error) {
+	if err := tenantClient.check(zone); err != nil {
+		return nil, err
+	}
+
+	return tenantClient.provider.CreateCryptokey(zone, key)
+}
+
+// SetCryptokeyActive Activates or deactivates a DNSSEC key of zone.
+func (tenantClient *Client) SetCryptokeyActive(zone string, id int, active bool) error {
+	if err := tenantClient.check(zone); err != nil {
+		return err
+	}
+
+	return tenantClient.provider.SetCryptokeyActive(zone, id, active)
+}
+
+// DeleteCryptokey Deletes a DNSSEC key of zone.
+func (tenantClient *Client) DeleteCryptokey(zone string, id int) error {
+	if err := tenantClient.check(zone); err != nil {
+		return err
+	}
+
+	return tenantClient.provider.DeleteCryptokey(zone, id)
+}
@@ -0,0 +1,31 @@
+package types
+
+import (
+	"fmt"
+)
+
+// ChangeType Action applied to a resource record set in a zone PATCH.
+type ChangeType string
+
+// Changetypes supported by the PowerDNS PATCH API.
+const (
+	ChangeTypeReplace ChangeType = "REPLACE"
+	ChangeTypeDelete  ChangeType = "DELETE"
+)
+
+// Validate Returns an error unless the changetype is one PowerDNS accepts in a PATCH.
+func (changeType ChangeType) Validate() error {
+	switch changeType {
+	case ChangeTypeReplace, ChangeTypeDelete:
+		return nil
+	}
+
+	return fmt.Errorf("Unsupported changetype: %q", string(changeType))
+}
+
+// ZonedChange Record set change together with the zone it applies to, for changes
+// spanning several zones.
+type ZonedChange struct {
+	Zone      string
+	RecordSet ResourceRecordSet
+}
@@ -0,0 +1,213 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SRV Typed content of an SRV record.
+type SRV struct {
+	Priority int
+	Weight   int
+	Port     int
+	Target   string
+}
+
+// String Returns the SRV record content.
+func (srv SRV) String() string {
+	return fmt.Sprintf("%d %d %d %s", srv.Priority, srv.Weight, srv.Port, srv.Target)
+}
+
+// ParseSRV Parses SRV record content. Priority, weight and port must fit in 16 bits.
+func ParseSRV(content string) (SRV, error) {
+	fields := strings.Fields(content)
+	if len(fields) != 4 {
+		return SRV{}, fmt.Errorf("Invalid SRV content: %q", content)
+	}
+
+	var srv SRV
+	var err error
+	if srv.Priority, err = parseUint16(fields[0]); err != nil {
+		return SRV{}, fmt.Errorf("Invalid SRV priority: %q", fields[0])
+	}
+	if srv.Weight, err = parseUint16(fields[1]); err != nil {
+		return SRV{}, fmt.Errorf("Invalid SRV weight: %q", fields[1])
+	}
+	if srv.Port, err = parseUint16(fields[2]); err != nil {
+		return SRV{}, fmt.Errorf("Invalid SRV port: %q", fields[2])
+	}
+	srv.Target = fields[3]
+
+	return srv, nil
+}
+
+// CAA Typed content of a CAA record.
+type CAA struct {
+	Flag  int
+	Tag   string
+	Value string
+}
+
+// String Returns the CAA record content, with the value quoted.
+func (caa CAA) String() string {
+	return fmt.Sprintf("%d %s %s", caa.Flag, caa.Tag, quoteString(caa.Value))
+}
+
+// ParseCAA Parses CAA record content.
+func ParseCAA(content string) (CAA, error) {
+	fields := strings.SplitN(strings.TrimSpace(content), " ", 3)
+	if len(fields) != 3 {
+		return CAA{}, fmt.Errorf("Invalid CAA content: %q", content)
+	}
+
+	var caa CAA
+	flag, err := strconv.ParseUint(fields[0], 10, 8)
+	if err != nil {
+		return CAA{}, fmt.Errorf("Invalid CAA flag: %q", fields[0])
+	}
+	caa.Flag = int(flag)
+
+	caa.Tag = fields[1]
+	if caa.Tag == "" || strings.IndexFunc(caa.Tag, func(r rune) bool {
+		return !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9')
+	}) >= 0 {
+		return CAA{}, fmt.Errorf("Invalid CAA tag: %q", fields[1])
+	}
+
+	value := strings.TrimSpace(fields[2])
+	if !strings.HasPrefix(value, `"`) {
+		caa.Value = value
+		return caa, nil
+	}
+
+	values, err := parseStrings(value)
+	if err != nil || len(values) != 1 {
+		return CAA{}, fmt.Errorf("Invalid CAA value: %q", fields[2])
+	}
+	caa.Value = values[0]
+
+	return caa, nil
+}
+
+// maxCharacterString is the longest character string a TXT record can hold.
+const maxCharacterString = 255
+
+// QuoteTXT Returns s as quoted TXT record content, escaping quotes, backslashes and
+// non-printable bytes. Values longer than 255 bytes are split into several strings.
+func QuoteTXT(s string) string {
+	if len(s) <= maxCharacterString {
+		return quoteString(s)
+	}
+
+	var parts []string
+	for len(s) > maxCharacterString {
+		parts = append(parts, quoteString(s[:maxCharacterString]))
+		s = s[maxCharacterString:]
+	}
+	parts = append(parts, quoteString(s))
+
+	return strings.Join(parts, " ")
+}
+
+// UnquoteTXT Returns the value of quoted TXT record content, concatenating its strings.
+// It reverses QuoteTXT.
+func UnquoteTXT(content string) (string, error) {
+	values, err := parseStrings(content)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.Join(values, ""), nil
+}
+
+func quoteString(s string) string {
+	var buf strings.Builder
+	buf.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '"' || c == '\\':
+			buf.WriteByte('\\')
+			buf.WriteByte(c)
+		case c < 0x20 || c >= 0x7f:
+			fmt.Fprintf(&buf, "\\%03d", c)
+		default:
+			buf.WriteByte(c)
+		}
+	}
+	buf.WriteByte('"')
+
+	return buf.String()
+}
+
+// Parses a sequence of quoted character strings separated by white space
+func parseStrings(content string) ([]string, error) {
+	var values []string
+	i := 0
+	for {
+		for i < len(content) && (content[i] == ' ' || content[i] == '\t') {
+			i++
+		}
+		if i == len(content) {
+			break
+		}
+		if content[i] != '"' {
+			return nil, fmt.Errorf("Invalid quoted content: %q", content)
+		}
+
+		var buf strings.Builder
+		closed := false
+		for i++; i < len(content); i++ {
+			c := content[i]
+			if c == '"' {
+				closed = true
+				i++
+				break
+			}
+			if c != '\\' {
+				buf.WriteByte(c)
+				continue
+			}
+
+			if i+3 < len(content) && isDigits(content[i+1:i+4]) {
+				code, _ := strconv.Atoi(content[i+1 : i+4])
+				if code > 255 {
+					return nil, fmt.Errorf("Invalid escape in quoted content: %q", content)
+				}
+				buf.WriteByte(byte(code))
+				i += 3
+			} else if i+1 < len(content) {
+				buf.WriteByte(content[i+1])
+				i++
+			} else {
+				return nil, fmt.Errorf("Invalid escape in quoted content: %q", content)
+			}
+		}
+		if !closed {
+			return nil, fmt.Errorf("Unterminated quoted content: %q", content)
+		}
+
+		values = append(values, buf.String())
+	}
+
+	if len(values) == 0 {
+		return nil, fmt.Errorf("Invalid quoted content: %q", content)
+	}
+
+	return values, nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+
+	return true
+}
+
+func parseUint16(s string) (int, error) {
+	value, err := strconv.ParseUint(s, 10, 16)
+	return int(value), err
+}
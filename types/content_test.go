@@ -0,0 +1,118 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func FuzzQuoteTXT(f *testing.F) {
+	f.Add("")
+	f.Add("v=spf1 include:_spf.example.com ~all")
+	f.Add(`say "hello" \ world`)
+	f.Add("tab\there\nnewline\x00\xff")
+	f.Add(strings.Repeat("a", 600))
+
+	f.Fuzz(func(t *testing.T, s string) {
+		quoted := QuoteTXT(s)
+		got, err := UnquoteTXT(quoted)
+		if err != nil {
+			t.Fatalf("UnquoteTXT(%q): %s", quoted, err)
+		}
+		if got != s {
+			t.Fatalf("round trip of %q gave %q", s, got)
+		}
+	})
+}
+
+func FuzzUnquoteTXT(f *testing.F) {
+	f.Add(`"v=spf1 -all"`)
+	f.Add(`"part one" "part two"`)
+	f.Add(`"escaped \"quote\" and \\ backslash"`)
+	f.Add(`"decimal \065\066\067"`)
+	f.Add(`"unterminated`)
+	f.Add(`unquoted`)
+
+	f.Fuzz(func(t *testing.T, content string) {
+		value, err := UnquoteTXT(content)
+		if err != nil {
+			return
+		}
+
+		quoted := QuoteTXT(value)
+		got, err := UnquoteTXT(quoted)
+		if err != nil {
+			t.Fatalf("UnquoteTXT(%q): %s", quoted, err)
+		}
+		if got != value {
+			t.Fatalf("round trip of %q gave %q", value, got)
+		}
+	})
+}
+
+func TestSRVRoundTrip(t *testing.T) {
+	cases := []SRV{
+		{Priority: 0, Weight: 0, Port: 0, Target: "."},
+		{Priority: 10, Weight: 60, Port: 5060, Target: "sip.example.com."},
+		{Priority: 65535, Weight: 65535, Port: 65535, Target: "max.example.com."},
+	}
+
+	for _, want := range cases {
+		got, err := ParseSRV(want.String())
+		if err != nil {
+			t.Fatalf("ParseSRV(%q): %s", want.String(), err)
+		}
+		if got != want {
+			t.Errorf("ParseSRV(%q) = %+v, want %+v", want.String(), got, want)
+		}
+	}
+}
+
+func TestParseSRVInvalid(t *testing.T) {
+	for _, content := range []string{
+		"",
+		"10 60 5060",
+		"10 60 5060 sip.example.com. extra",
+		"65536 0 0 host.",
+		"-1 0 0 host.",
+		"a 0 0 host.",
+	} {
+		if _, err := ParseSRV(content); err == nil {
+			t.Errorf("ParseSRV(%q) succeeded, want error", content)
+		}
+	}
+}
+
+func TestCAARoundTrip(t *testing.T) {
+	cases := []CAA{
+		{Flag: 0, Tag: "issue", Value: "letsencrypt.org"},
+		{Flag: 128, Tag: "iodef", Value: "mailto:security@example.com"},
+		{Flag: 0, Tag: "issuewild", Value: ";"},
+		{Flag: 0, Tag: "issue", Value: `ca.example.net; account="230123"`},
+		{Flag: 255, Tag: "tbs", Value: ""},
+	}
+
+	for _, want := range cases {
+		got, err := ParseCAA(want.String())
+		if err != nil {
+			t.Fatalf("ParseCAA(%q): %s", want.String(), err)
+		}
+		if got != want {
+			t.Errorf("ParseCAA(%q) = %+v, want %+v", want.String(), got, want)
+		}
+	}
+}
+
+func TestParseCAAInvalid(t *testing.T) {
+	for _, content := range []string{
+		"",
+		"0 issue",
+		"256 issue \"ca.example.net\"",
+		"0 is-sue \"ca.example.net\"",
+		"0 issue \"unterminated",
+		"0 issue \"one\" \"two\"",
+	} {
+		if _, err := ParseCAA(content); err == nil {
+			t.Errorf("ParseCAA(%q) succeeded, want error", content)
+		}
+	}
+}
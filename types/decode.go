@@ -0,0 +1,123 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Decoding of API payloads is lenient so server upgrades do not break the client:
+// unknown fields are ignored, missing optional fields keep their zero value, and
+// serials, TTLs and timestamps are accepted as JSON numbers or strings, both of which
+// PowerDNS 3.x and 4.x have sent.
+
+// Integer sent as a number, a quoted number, null or an empty string
+type flexInt int64
+
+func (value *flexInt) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	if len(data) > 0 && data[0] == '"' {
+		var s string
+		if err := json.Unmarshal(data, &s); err != nil {
+			return err
+		}
+		if s == "" {
+			*value = 0
+			return nil
+		}
+		data = []byte(s)
+	}
+
+	if n, err := strconv.ParseInt(string(data), 10, 64); err == nil {
+		*value = flexInt(n)
+		return nil
+	}
+
+	f, err := strconv.ParseFloat(string(data), 64)
+	if err != nil || f != math.Trunc(f) || math.Abs(f) > math.MaxInt64 {
+		return fmt.Errorf("Invalid integer: %s", data)
+	}
+	*value = flexInt(f)
+
+	return nil
+}
+
+// UnmarshalJSON Decodes a zone, accepting serials and check times as numbers or strings.
+func (zone *ZoneInfo) UnmarshalJSON(data []byte) error {
+	type plain ZoneInfo
+	aux := struct {
+		*plain
+		LastCheck      flexInt `json:"last_check"`
+		Serial         flexInt `json:"serial"`
+		NotifiedSerial flexInt `json:"notified_serial"`
+		EditedSerial   flexInt `json:"edited_serial"`
+	}{plain: (*plain)(zone)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	zone.LastCheck = int64(aux.LastCheck)
+	zone.Serial = int64(aux.Serial)
+	zone.NotifiedSerial = int64(aux.NotifiedSerial)
+	zone.EditedSerial = int64(aux.EditedSerial)
+
+	return nil
+}
+
+// UnmarshalJSON Decodes a record, accepting its TTL as a number or a string.
+func (record *Record) UnmarshalJSON(data []byte) error {
+	type plain Record
+	aux := struct {
+		*plain
+		TTL flexInt `json:"ttl"`
+	}{plain: (*plain)(record)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	record.TTL = int(aux.TTL)
+
+	return nil
+}
+
+// UnmarshalJSON Decodes a record set, accepting its TTL as a number or a string.
+func (rrSet *ResourceRecordSet) UnmarshalJSON(data []byte) error {
+	type plain ResourceRecordSet
+	aux := struct {
+		*plain
+		TTL flexInt `json:"ttl"`
+	}{plain: (*plain)(rrSet)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	rrSet.TTL = int(aux.TTL)
+
+	return nil
+}
+
+// UnmarshalJSON Decodes a comment, accepting its modification time as a number or a string.
+func (comment *Comment) UnmarshalJSON(data []byte) error {
+	type plain Comment
+	aux := struct {
+		*plain
+		ModifiedAt flexInt `json:"modified_at"`
+	}{plain: (*plain)(comment)}
+
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	comment.ModifiedAt = int64(aux.ModifiedAt)
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func FuzzZoneInfoUnmarshal(f *testing.F) {
+	files, err := filepath.Glob(filepath.Join("testdata", "zoneinfo", "*.json"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			f.Fatal(err)
+		}
+		f.Add(data)
+	}
+	f.Add([]byte(`{"serial": 1.5}`))
+	f.Add([]byte(`{"serial": "", "rrsets": [{"ttl": "300"}]}`))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var zone ZoneInfo
+		if err := json.Unmarshal(data, &zone); err != nil {
+			return
+		}
+
+		// Whatever decodes must survive an encode and decode unchanged
+		encoded, err := json.Marshal(&zone)
+		if err != nil {
+			t.Fatalf("Marshal: %s", err)
+		}
+
+		var decoded ZoneInfo
+		if err := json.Unmarshal(encoded, &decoded); err != nil {
+			t.Fatalf("Unmarshal(%s): %s", encoded, err)
+		}
+
+		reencoded, err := json.Marshal(&decoded)
+		if err != nil {
+			t.Fatalf("Marshal: %s", err)
+		}
+		if !bytes.Equal(encoded, reencoded) {
+			t.Fatalf("round trip changed zone:\n%s\n%s", encoded, reencoded)
+		}
+	})
+}
+
+func TestZoneInfoUnmarshalResponses(t *testing.T) {
+	cases := map[string]struct {
+		serial  int64
+		records int
+		rrSets  int
+	}{
+		"native-v4.json":  {serial: 2024031502, rrSets: 4},
+		"slave-v4.json":   {serial: 2024031001},
+		"legacy-v3.json":  {serial: 2015010101, records: 2},
+		"list-entry.json": {serial: 2024031401},
+	}
+
+	for file, want := range cases {
+		data, err := os.ReadFile(filepath.Join("testdata", "zoneinfo", file))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var zone ZoneInfo
+		if err := json.Unmarshal(data, &zone); err != nil {
+			t.Errorf("%s: %s", file, err)
+			continue
+		}
+		if zone.Serial != want.serial || len(zone.Records) != want.records || len(zone.ResourceRecordSets) != want.rrSets {
+			t.Errorf("%s: serial %d, %d records, %d rrsets; want %d, %d, %d", file,
+				zone.Serial, len(zone.Records), len(zone.ResourceRecordSets), want.serial, want.records, want.rrSets)
+		}
+	}
+}
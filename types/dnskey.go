@@ -0,0 +1,223 @@
+package types
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"strconv"
+	"strings"
+)
+
+// DNSKEY flag bits.
+const (
+	DNSKEYFlagZone = 0x100
+	DNSKEYFlagSEP  = 0x1
+)
+
+// DS digest types.
+const (
+	DigestSHA1   = 1
+	DigestSHA256 = 2
+	DigestSHA384 = 4
+)
+
+// DNSKEY Typed content of a DNSKEY or CDNSKEY record, which share their format.
+type DNSKEY struct {
+	Flags     int
+	Protocol  int
+	Algorithm int
+	// PublicKey is the base64 encoded key.
+	PublicKey string
+}
+
+// String Returns the DNSKEY or CDNSKEY record content.
+func (key DNSKEY) String() string {
+	return fmt.Sprintf("%d %d %d %s", key.Flags, key.Protocol, key.Algorithm, key.PublicKey)
+}
+
+// ParseDNSKEY Parses DNSKEY or CDNSKEY record content; the public key may be split into
+// several fields.
+func ParseDNSKEY(content string) (DNSKEY, error) {
+	fields := strings.Fields(content)
+	if len(fields) < 4 {
+		return DNSKEY{}, fmt.Errorf("Invalid DNSKEY content: %q", content)
+	}
+
+	var key DNSKEY
+	var err error
+	if key.Flags, err = parseUint16(fields[0]); err != nil {
+		return DNSKEY{}, fmt.Errorf("Invalid DNSKEY flags: %q", fields[0])
+	}
+	if key.Protocol, err = parseUint8(fields[1]); err != nil {
+		return DNSKEY{}, fmt.Errorf("Invalid DNSKEY protocol: %q", fields[1])
+	}
+	if key.Algorithm, err = parseUint8(fields[2]); err != nil {
+		return DNSKEY{}, fmt.Errorf("Invalid DNSKEY algorithm: %q", fields[2])
+	}
+
+	key.PublicKey = strings.Join(fields[3:], "")
+	if _, err := base64.StdEncoding.DecodeString(key.PublicKey); err != nil {
+		return DNSKEY{}, fmt.Errorf("Invalid DNSKEY public key: %q", key.PublicKey)
+	}
+
+	return key, nil
+}
+
+// IsKeySigningKey Returns true when the SEP flag is set, as it is on KSKs and CSKs.
+func (key DNSKEY) IsKeySigningKey() bool {
+	return key.Flags&DNSKEYFlagSEP != 0
+}
+
+// IsDelete Returns true for the CDNSKEY "0 3 0 AA==" asking the parent to remove all DS
+// records (RFC 8078).
+func (key DNSKEY) IsDelete() bool {
+	return key.Flags == 0 && key.Protocol == 3 && key.Algorithm == 0
+}
+
+// KeyTag Returns the key tag of the key (RFC 4034, appendix B), which DS records and
+// signatures refer to.
+func (key DNSKEY) KeyTag() int {
+	rdata, err := key.rdata()
+	if err != nil {
+		return 0
+	}
+
+	var sum uint32
+	for i, b := range rdata {
+		if i&1 == 0 {
+			sum += uint32(b) << 8
+		} else {
+			sum += uint32(b)
+		}
+	}
+	sum += sum >> 16 & 0xffff
+
+	return int(sum & 0xffff)
+}
+
+// DS Returns the DS record of the key for the zone owner with the given digest type.
+func (key DNSKEY) DS(owner string, digestType int) (DS, error) {
+	var digest hash.Hash
+	switch digestType {
+	case DigestSHA1:
+		digest = sha1.New()
+	case DigestSHA256:
+		digest = sha256.New()
+	case DigestSHA384:
+		digest = sha512.New384()
+	default:
+		return DS{}, fmt.Errorf("Unsupported DS digest type: %d", digestType)
+	}
+
+	name, err := wireName(owner)
+	if err != nil {
+		return DS{}, err
+	}
+	rdata, err := key.rdata()
+	if err != nil {
+		return DS{}, err
+	}
+	digest.Write(name)
+	digest.Write(rdata)
+
+	return DS{
+		KeyTag:     key.KeyTag(),
+		Algorithm:  key.Algorithm,
+		DigestType: digestType,
+		Digest:     strings.ToUpper(hex.EncodeToString(digest.Sum(nil))),
+	}, nil
+}
+
+// Returns the wire format RDATA of the key
+func (key DNSKEY) rdata() ([]byte, error) {
+	publicKey, err := base64.StdEncoding.DecodeString(key.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid DNSKEY public key: %q", key.PublicKey)
+	}
+
+	return append([]byte{byte(key.Flags >> 8), byte(key.Flags), byte(key.Protocol), byte(key.Algorithm)}, publicKey...), nil
+}
+
+// DS Typed content of a DS or CDS record, which share their format.
+type DS struct {
+	KeyTag     int
+	Algorithm  int
+	DigestType int
+	// Digest is hex encoded.
+	Digest string
+}
+
+// String Returns the DS or CDS record content.
+func (ds DS) String() string {
+	return fmt.Sprintf("%d %d %d %s", ds.KeyTag, ds.Algorithm, ds.DigestType, ds.Digest)
+}
+
+// ParseDS Parses DS or CDS record content; the digest may be split into several fields
+// and is returned in upper case.
+func ParseDS(content string) (DS, error) {
+	fields := strings.Fields(content)
+	if len(fields) < 4 {
+		return DS{}, fmt.Errorf("Invalid DS content: %q", content)
+	}
+
+	var ds DS
+	var err error
+	if ds.KeyTag, err = parseUint16(fields[0]); err != nil {
+		return DS{}, fmt.Errorf("Invalid DS key tag: %q", fields[0])
+	}
+	if ds.Algorithm, err = parseUint8(fields[1]); err != nil {
+		return DS{}, fmt.Errorf("Invalid DS algorithm: %q", fields[1])
+	}
+	if ds.DigestType, err = parseUint8(fields[2]); err != nil {
+		return DS{}, fmt.Errorf("Invalid DS digest type: %q", fields[2])
+	}
+
+	ds.Digest = strings.ToUpper(strings.Join(fields[3:], ""))
+	if _, err := hex.DecodeString(ds.Digest); err != nil {
+		return DS{}, fmt.Errorf("Invalid DS digest: %q", ds.Digest)
+	}
+
+	return ds, nil
+}
+
+// IsDelete Returns true for the CDS "0 0 0 00" asking the parent to remove all DS
+// records (RFC 8078).
+func (ds DS) IsDelete() bool {
+	return ds.Algorithm == 0 && ds.DigestType == 0
+}
+
+// Matches Returns true when ds is the DS record of key for the zone owner.
+func (ds DS) Matches(owner string, key DNSKEY) bool {
+	if ds.KeyTag != key.KeyTag() || ds.Algorithm != key.Algorithm {
+		return false
+	}
+
+	expected, err := key.DS(owner, ds.DigestType)
+	return err == nil && expected.Digest == ds.Digest
+}
+
+// Returns name in canonical wire format: lower case labels prefixed with their length
+func wireName(name string) ([]byte, error) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	var wire []byte
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			if label == "" || len(label) > MaxLabelLength {
+				return nil, fmt.Errorf("Invalid domain name: %q", name)
+			}
+			wire = append(wire, byte(len(label)))
+			wire = append(wire, label...)
+		}
+	}
+
+	return append(wire, 0), nil
+}
+
+func parseUint8(s string) (int, error) {
+	value, err := strconv.ParseUint(s, 10, 8)
+	return int(value), err
+}
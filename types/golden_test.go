@@ -0,0 +1,111 @@
+package types
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// Compares got with testdata/golden/name.golden, rewriting the file when -update is set
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+
+	path := filepath.Join("testdata", "golden", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("%s (run go test -update to create it)", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("%s differs from golden file:\n%s\nwant:\n%s", name, got, want)
+	}
+}
+
+func marshalGolden(t *testing.T, value interface{}) []byte {
+	t.Helper()
+
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return append(data, '\n')
+}
+
+// Responses of several server versions decode to the same shape of ZoneInfo
+func TestZoneInfoResponsesGolden(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("testdata", "zoneinfo", "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var zone ZoneInfo
+			if err := json.Unmarshal(data, &zone); err != nil {
+				t.Fatal(err)
+			}
+
+			checkGolden(t, "zoneinfo-"+name, marshalGolden(t, &zone))
+		})
+	}
+}
+
+// Request bodies sent for zone creation and record set changes
+func TestRequestBodiesGolden(t *testing.T) {
+	cases := map[string]interface{}{
+		"create-zone": &ZoneInfo{
+			Name:        "example.com.",
+			Kind:        KindNative,
+			Nameservers: []string{"ns1.example.com.", "ns2.example.com."},
+			SOAEditAPI:  "DEFAULT",
+		},
+		"replace-rrset": ResourceRecordSet{
+			Name:       "www.example.com.",
+			Type:       TypeA,
+			ChangeType: ChangeTypeReplace,
+			TTL:        300,
+			Records:    []Record{{Content: "192.0.2.10"}, {Content: "192.0.2.11", Disabled: true}},
+		},
+		"delete-rrset": ResourceRecordSet{
+			Name:       "old.example.com.",
+			Type:       TypeCNAME,
+			ChangeType: ChangeTypeDelete,
+		},
+		"comment-rrset": ResourceRecordSet{
+			Name:       "db.example.com.",
+			Type:       TypeA,
+			ChangeType: ChangeTypeReplace,
+			TTL:        3600,
+			Records:    []Record{{Content: "198.51.100.7"}},
+			Comments:   []Comment{{Content: "primary database", Account: "ops"}},
+		},
+	}
+
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			checkGolden(t, "request-"+name, marshalGolden(t, body))
+		})
+	}
+}
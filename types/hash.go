@@ -0,0 +1,44 @@
+package types
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// RRsetHash Returns a stable hash of the data of rrSet: its name, compared
+// case-insensitively and with or without trailing dot, type, TTL and records in any
+// order. Comments and changetype are ignored, like EqualContents does.
+func RRsetHash(rrSet *ResourceRecordSet) string {
+	records := make([]string, len(rrSet.Records))
+	for i, record := range rrSet.Records {
+		records[i] = fmt.Sprintf("%t %s", record.Disabled, record.Content)
+	}
+	sort.Strings(records)
+
+	hash := sha256.New()
+	fmt.Fprintf(hash, "%s\n%s\n%d\n", canonicalName(rrSet.Name), rrSet.Type, rrSet.TTL)
+	for _, record := range records {
+		fmt.Fprintf(hash, "%d:%s\n", len(record), record)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// ZoneHash Returns a stable hash of rrSets in any order, so that an unchanged zone can
+// be detected without comparing it field by field. The SOA rrset is left out since
+// PowerDNS bumps its serial on its own.
+func ZoneHash(rrSets []ResourceRecordSet) string {
+	hashes := make([]string, 0, len(rrSets))
+	for i := range rrSets {
+		if rrSets[i].Type != TypeSOA {
+			hashes = append(hashes, RRsetHash(&rrSets[i]))
+		}
+	}
+	sort.Strings(hashes)
+
+	sum := sha256.Sum256([]byte(strings.Join(hashes, "\n")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,145 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Limits of RFC 1035 on names and character strings.
+const (
+	MaxLabelLength = 63
+	MaxNameLength  = 255
+)
+
+// ErrLint Matched by the errors of Lint.
+var ErrLint = errors.New("record set violates DNS limits")
+
+// LintLevel Strictness of Lint.
+type LintLevel int
+
+// Lint levels, from most lenient to strictest.
+const (
+	// LintOff disables linting.
+	LintOff LintLevel = iota
+	// LintLimits checks the hard limits of RFC 1035: label and name lengths, empty labels,
+	// character strings of TXT records and non-printable characters in names.
+	LintLimits
+	// LintStrict also checks the host name rules of RFC 952/1123 for names that are not
+	// service labels: letters, digits and inner hyphens only, a wildcard only as the whole
+	// leftmost label, and underscore labels (RFC 8552) only to the left of host labels.
+	LintStrict
+)
+
+// Record types whose content is a single domain name
+var nameContentTypes = map[RecordType]bool{
+	TypeCNAME: true, TypeDNAME: true, TypeNS: true, TypePTR: true, TypeALIAS: true,
+}
+
+// Lint Returns an error wrapping ErrLint for the first problem found in the name and
+// records of rrSet at level. Problems PowerDNS sometimes accepts, but resolvers
+// mishandle, are caught before the record set is written.
+func Lint(rrSet *ResourceRecordSet, level LintLevel) error {
+	if level == LintOff {
+		return nil
+	}
+
+	if err := LintName(rrSet.Name, level); err != nil {
+		return err
+	}
+
+	for _, record := range rrSet.Records {
+		switch {
+		case rrSet.Type == TypeTXT || rrSet.Type == TypeSPF:
+			values, err := parseStrings(record.Content)
+			if err != nil {
+				if level == LintStrict {
+					return fmt.Errorf("%s: %w", err, ErrLint)
+				}
+				continue
+			}
+			for _, value := range values {
+				if len(value) > maxCharacterString {
+					return fmt.Errorf("TXT string of %d bytes exceeds %d: %w", len(value), maxCharacterString, ErrLint)
+				}
+			}
+		case nameContentTypes[rrSet.Type]:
+			// targets are host names, so wildcards and underscores are checked in
+			// strict mode like any owner name
+			if err := LintName(record.Content, level); err != nil {
+				return fmt.Errorf("target %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// LintName Returns an error wrapping ErrLint when name violates the rules of level.
+func LintName(name string, level LintLevel) error {
+	if level == LintOff || name == "." {
+		return nil
+	}
+
+	fqdn := strings.TrimSuffix(name, ".")
+	if len(fqdn)+2 > MaxNameLength {
+		return fmt.Errorf("name %q exceeds %d bytes: %w", name, MaxNameLength, ErrLint)
+	}
+
+	labels := strings.Split(fqdn, ".")
+	hostLabels := false
+	for i, label := range labels {
+		if label == "" {
+			return fmt.Errorf("name %q has an empty label: %w", name, ErrLint)
+		}
+		if len(label) > MaxLabelLength {
+			return fmt.Errorf("label %q of %q exceeds %d bytes: %w", label, name, MaxLabelLength, ErrLint)
+		}
+		for j := 0; j < len(label); j++ {
+			if c := label[j]; c <= ' ' || c >= 0x7f {
+				return fmt.Errorf("name %q contains the illegal character %q: %w", name, c, ErrLint)
+			}
+		}
+
+		if level < LintStrict {
+			continue
+		}
+
+		switch {
+		case label == "*":
+			if i != 0 {
+				return fmt.Errorf("name %q has a wildcard that is not the leftmost label: %w", name, ErrLint)
+			}
+		case strings.HasPrefix(label, "_"):
+			if hostLabels {
+				return fmt.Errorf("name %q has the underscore label %q below a host label: %w", name, label, ErrLint)
+			}
+			if !isHostLabel(label[1:]) {
+				return fmt.Errorf("label %q of %q contains illegal characters: %w", label, name, ErrLint)
+			}
+		default:
+			if !isHostLabel(label) {
+				return fmt.Errorf("label %q of %q is not a valid host name label: %w", label, name, ErrLint)
+			}
+			hostLabels = true
+		}
+	}
+
+	return nil
+}
+
+// Letters, digits and hyphens, not starting or ending with a hyphen
+func isHostLabel(label string) bool {
+	if label == "" || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-') {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,61 @@
+package types
+
+import (
+	"strings"
+)
+
+// Names are compared the way DNS does: case-insensitively and with or without the
+// trailing dot of the fully qualified form.
+func canonicalName(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+// EqualNames Returns true when a and b are the same DNS name.
+func EqualNames(a string, b string) bool {
+	return canonicalName(a) == canonicalName(b)
+}
+
+// IsSubdomainOf Returns true when name equals zone or is below it. Every name is below
+// the root zone.
+func IsSubdomainOf(name string, zone string) bool {
+	name = canonicalName(name)
+	zone = canonicalName(zone)
+
+	return zone == "" || name == zone || strings.HasSuffix(name, "."+zone)
+}
+
+// QualifyName Returns name fully qualified against zone, with a trailing dot. "@" and an
+// empty name stand for the zone apex, names ending with a dot or already inside zone are
+// taken as absolute, and any other name as relative to zone.
+func QualifyName(name string, zone string) string {
+	zone = strings.TrimSuffix(zone, ".") + "."
+	switch {
+	case name == "" || name == "@":
+		return zone
+	case strings.HasSuffix(name, "."):
+		return name
+	case IsSubdomainOf(name, zone):
+		return name + "."
+	}
+
+	return name + "." + zone
+}
+
+// SplitRelative Returns name relative to zone, without a trailing dot, and an empty
+// string for the apex. ok is false when name is not in zone.
+func SplitRelative(name string, zone string) (relative string, ok bool) {
+	if !IsSubdomainOf(name, zone) {
+		return "", false
+	}
+
+	name = strings.TrimSuffix(name, ".")
+	zone = canonicalName(zone)
+	switch {
+	case zone == "":
+		return name, true
+	case len(name) == len(zone):
+		return "", true
+	}
+
+	return name[:len(name)-len(zone)-1], true
+}
@@ -0,0 +1,107 @@
+package types
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// RecordType DNS record type, e.g. "A" or "CNAME".
+type RecordType string
+
+// Record types supported by PowerDNS.
+const (
+	TypeA          RecordType = "A"
+	TypeAAAA       RecordType = "AAAA"
+	TypeAFSDB      RecordType = "AFSDB"
+	TypeALIAS      RecordType = "ALIAS"
+	TypeAPL        RecordType = "APL"
+	TypeCAA        RecordType = "CAA"
+	TypeCDNSKEY    RecordType = "CDNSKEY"
+	TypeCDS        RecordType = "CDS"
+	TypeCERT       RecordType = "CERT"
+	TypeCNAME      RecordType = "CNAME"
+	TypeCSYNC      RecordType = "CSYNC"
+	TypeDHCID      RecordType = "DHCID"
+	TypeDLV        RecordType = "DLV"
+	TypeDNAME      RecordType = "DNAME"
+	TypeDNSKEY     RecordType = "DNSKEY"
+	TypeDS         RecordType = "DS"
+	TypeEUI48      RecordType = "EUI48"
+	TypeEUI64      RecordType = "EUI64"
+	TypeHINFO      RecordType = "HINFO"
+	TypeHTTPS      RecordType = "HTTPS"
+	TypeIPSECKEY   RecordType = "IPSECKEY"
+	TypeKEY        RecordType = "KEY"
+	TypeKX         RecordType = "KX"
+	TypeL32        RecordType = "L32"
+	TypeL64        RecordType = "L64"
+	TypeLOC        RecordType = "LOC"
+	TypeLP         RecordType = "LP"
+	TypeLUA        RecordType = "LUA"
+	TypeMINFO      RecordType = "MINFO"
+	TypeMR         RecordType = "MR"
+	TypeMX         RecordType = "MX"
+	TypeNAPTR      RecordType = "NAPTR"
+	TypeNID        RecordType = "NID"
+	TypeNS         RecordType = "NS"
+	TypeNSEC       RecordType = "NSEC"
+	TypeNSEC3      RecordType = "NSEC3"
+	TypeNSEC3PARAM RecordType = "NSEC3PARAM"
+	TypeOPENPGPKEY RecordType = "OPENPGPKEY"
+	TypePTR        RecordType = "PTR"
+	TypeRKEY       RecordType = "RKEY"
+	TypeRP         RecordType = "RP"
+	TypeRRSIG      RecordType = "RRSIG"
+	TypeSIG        RecordType = "SIG"
+	TypeSMIMEA     RecordType = "SMIMEA"
+	TypeSOA        RecordType = "SOA"
+	TypeSPF        RecordType = "SPF"
+	TypeSRV        RecordType = "SRV"
+	TypeSSHFP      RecordType = "SSHFP"
+	TypeSVCB       RecordType = "SVCB"
+	TypeTLSA       RecordType = "TLSA"
+	TypeTXT        RecordType = "TXT"
+	TypeURI        RecordType = "URI"
+	TypeZONEMD     RecordType = "ZONEMD"
+)
+
+var knownRecordTypes = map[RecordType]bool{}
+
+// Generic RFC 3597 type syntax, e.g. TYPE65534
+var genericRecordType = regexp.MustCompile(`^TYPE[0-9]{1,5}$`)
+
+func init() {
+	for _, recordType := range []RecordType{
+		TypeA, TypeAAAA, TypeAFSDB, TypeALIAS, TypeAPL, TypeCAA, TypeCDNSKEY, TypeCDS,
+		TypeCERT, TypeCNAME, TypeCSYNC, TypeDHCID, TypeDLV, TypeDNAME, TypeDNSKEY, TypeDS,
+		TypeEUI48, TypeEUI64, TypeHINFO, TypeHTTPS, TypeIPSECKEY, TypeKEY, TypeKX, TypeL32,
+		TypeL64, TypeLOC, TypeLP, TypeLUA, TypeMINFO, TypeMR, TypeMX, TypeNAPTR, TypeNID,
+		TypeNS, TypeNSEC, TypeNSEC3, TypeNSEC3PARAM, TypeOPENPGPKEY, TypePTR, TypeRKEY, TypeRP,
+		TypeRRSIG, TypeSIG, TypeSMIMEA, TypeSOA, TypeSPF, TypeSRV, TypeSSHFP, TypeSVCB,
+		TypeTLSA, TypeTXT, TypeURI, TypeZONEMD,
+	} {
+		knownRecordTypes[recordType] = true
+	}
+}
+
+// ParseRecordType Returns the record type named by s, case-insensitively, or an error for
+// unknown types such as typos ("CNME").
+func ParseRecordType(s string) (RecordType, error) {
+	recordType := RecordType(strings.ToUpper(s))
+	if err := recordType.Validate(); err != nil {
+		return "", err
+	}
+
+	return recordType, nil
+}
+
+// Validate Returns an error unless the record type is known to PowerDNS or uses the
+// generic TYPEnnn syntax.
+func (recordType RecordType) Validate() error {
+	if knownRecordTypes[recordType] || genericRecordType.MatchString(string(recordType)) {
+		return nil
+	}
+
+	return fmt.Errorf("Unknown record type: %q", string(recordType))
+}
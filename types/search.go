@@ -0,0 +1,21 @@
+package types
+
+// Object types of search-data results.
+const (
+	SearchObjectAll     = "all"
+	SearchObjectZone    = "zone"
+	SearchObjectRecord  = "record"
+	SearchObjectComment = "comment"
+)
+
+// SearchResult Data representing a zone, record or comment matched by search-data.
+type SearchResult struct {
+	ObjectType string     `json:"object_type"`
+	Zone       string     `json:"zone"`
+	ZoneID     string     `json:"zone_id"`
+	Name       string     `json:"name"`
+	Type       RecordType `json:"type,omitempty"`
+	Content    string     `json:"content,omitempty"`
+	TTL        int        `json:"ttl,omitempty"`
+	Disabled   bool       `json:"disabled,omitempty"`
+}
@@ -0,0 +1,93 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Default SOA timers used by SOATemplate when a value is left at zero.
+const (
+	DefaultSOARefresh = 10800
+	DefaultSOARetry   = 3600
+	DefaultSOAExpire  = 604800
+	DefaultSOAMinimum = 3600
+	DefaultSOATTL     = 3600
+)
+
+// SOATemplate Parameters used to build the SOA record of a new zone.
+type SOATemplate struct {
+	PrimaryNS  string
+	Hostmaster string
+	Serial     int64
+	Refresh    int
+	Retry      int
+	Expire     int
+	Minimum    int
+	TTL        int
+}
+
+// Content Returns the SOA record content, filling unset timers with defaults.
+func (soa *SOATemplate) Content() string {
+	return fmt.Sprintf("%s %s %d %d %d %d %d",
+		soa.PrimaryNS,
+		soa.Hostmaster,
+		soa.Serial,
+		orDefault(soa.Refresh, DefaultSOARefresh),
+		orDefault(soa.Retry, DefaultSOARetry),
+		orDefault(soa.Expire, DefaultSOAExpire),
+		orDefault(soa.Minimum, DefaultSOAMinimum),
+	)
+}
+
+// RecordSet Returns the SOA resource record set for the zone apex.
+func (soa *SOATemplate) RecordSet(zone string) ResourceRecordSet {
+	return ResourceRecordSet{
+		Name: zone,
+		Type: TypeSOA,
+		TTL:  orDefault(soa.TTL, DefaultSOATTL),
+		Records: []Record{
+			{Content: soa.Content()},
+		},
+	}
+}
+
+// ParseSOA Parses SOA record content into a template.
+func ParseSOA(content string) (*SOATemplate, error) {
+	fields := strings.Fields(content)
+	if len(fields) != 7 {
+		return nil, fmt.Errorf("Invalid SOA content: %q", content)
+	}
+
+	serial, err := strconv.ParseUint(fields[2], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid SOA serial: %q", fields[2])
+	}
+
+	timers := make([]int, 4)
+	for i := range timers {
+		value, err := strconv.ParseUint(fields[3+i], 10, 31)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid SOA timer: %q", fields[3+i])
+		}
+		timers[i] = int(value)
+	}
+
+	return &SOATemplate{
+		PrimaryNS:  fields[0],
+		Hostmaster: fields[1],
+		Serial:     int64(serial),
+		Refresh:    timers[0],
+		Retry:      timers[1],
+		Expire:     timers[2],
+		Minimum:    timers[3],
+	}, nil
+}
+
+func orDefault(value int, def int) int {
+	if value == 0 {
+		return def
+	}
+
+	return value
+}
@@ -0,0 +1,66 @@
+package types
+
+import (
+	"sort"
+)
+
+// SortRecords Sorts records in canonical order: by name, type, then content.
+func SortRecords(records []Record) {
+	sort.SliceStable(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Content < b.Content
+	})
+}
+
+// Canonicalize Sorts the records and comments of the record set by content, so record
+// sets holding the same data compare equal regardless of the order the API used.
+func (rrSet *ResourceRecordSet) Canonicalize() {
+	sort.SliceStable(rrSet.Records, func(i, j int) bool {
+		return rrSet.Records[i].Content < rrSet.Records[j].Content
+	})
+	sort.SliceStable(rrSet.Comments, func(i, j int) bool {
+		return rrSet.Comments[i].Content < rrSet.Comments[j].Content
+	})
+}
+
+// SortRecordSets Canonicalizes every record set and sorts them by name and type.
+func SortRecordSets(rrSets []ResourceRecordSet) {
+	for i := range rrSets {
+		rrSets[i].Canonicalize()
+	}
+
+	sort.SliceStable(rrSets, func(i, j int) bool {
+		if rrSets[i].Name != rrSets[j].Name {
+			return rrSets[i].Name < rrSets[j].Name
+		}
+		return rrSets[i].Type < rrSets[j].Type
+	})
+}
+
+// EqualContents Returns true when both record sets have the same TTL and the same
+// records, ignoring record order.
+func EqualContents(a ResourceRecordSet, b ResourceRecordSet) bool {
+	if a.TTL != b.TTL || len(a.Records) != len(b.Records) {
+		return false
+	}
+
+	records := make(map[Record]int, len(a.Records))
+	for _, record := range a.Records {
+		records[Record{Content: record.Content, Disabled: record.Disabled}]++
+	}
+	for _, record := range b.Records {
+		key := Record{Content: record.Content, Disabled: record.Disabled}
+		if records[key] == 0 {
+			return false
+		}
+		records[key]--
+	}
+
+	return true
+}
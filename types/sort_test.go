@@ -0,0 +1,40 @@
+package types
+
+import (
+	"testing"
+)
+
+func BenchmarkSortRecords(b *testing.B) {
+	var flattened []Record
+	for _, rrSet := range largeZone(10000) {
+		flattened = append(flattened, rrSet.Flatten()...)
+	}
+	records := make([]Record, len(flattened))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		copy(records, flattened)
+		b.StartTimer()
+
+		SortRecords(records)
+	}
+}
+
+func BenchmarkSortRecordSets(b *testing.B) {
+	zone := largeZone(10000)
+	rrSets := make([]ResourceRecordSet, len(zone))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		for j := range zone {
+			rrSets[j] = *zone[j].Clone()
+		}
+		b.StartTimer()
+
+		SortRecordSets(rrSets)
+	}
+}
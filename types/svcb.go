@@ -0,0 +1,279 @@
+package types
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SvcParam keys with a typed field in SVCB, in the order of their key numbers (RFC 9460).
+const (
+	SvcParamMandatory     = "mandatory"
+	SvcParamALPN          = "alpn"
+	SvcParamNoDefaultALPN = "no-default-alpn"
+	SvcParamPort          = "port"
+	SvcParamIPv4Hint      = "ipv4hint"
+	SvcParamECH           = "ech"
+	SvcParamIPv6Hint      = "ipv6hint"
+)
+
+// SVCB Typed content of an SVCB or HTTPS record, which share their format. Priority 0
+// is alias mode, in which Target names the service and no parameters are allowed; in
+// service mode a Target of "." means the owner name itself.
+type SVCB struct {
+	Priority      int
+	Target        string
+	Mandatory     []string
+	ALPN          []string
+	NoDefaultALPN bool
+	// Port is left out of the content when 0.
+	Port     int
+	IPv4Hint []net.IP
+	// ECH is the base64 encoded ECHConfigList.
+	ECH      string
+	IPv6Hint []net.IP
+	// Params holds the values of other keys, e.g. "key65333"; keys without a value map
+	// to "".
+	Params map[string]string
+}
+
+// String Returns the SVCB or HTTPS record content, with the parameters in key order.
+func (svcb SVCB) String() string {
+	fields := []string{strconv.Itoa(svcb.Priority), svcb.Target}
+
+	if len(svcb.Mandatory) > 0 {
+		fields = append(fields, SvcParamMandatory+"="+strings.Join(svcb.Mandatory, ","))
+	}
+	if len(svcb.ALPN) > 0 {
+		ids := make([]string, len(svcb.ALPN))
+		for i, id := range svcb.ALPN {
+			ids[i] = strings.NewReplacer(`\`, `\\\\`, `,`, `\\,`).Replace(id)
+		}
+		fields = append(fields, SvcParamALPN+"="+quoteParam(strings.Join(ids, ",")))
+	}
+	if svcb.NoDefaultALPN {
+		fields = append(fields, SvcParamNoDefaultALPN)
+	}
+	if svcb.Port != 0 {
+		fields = append(fields, SvcParamPort+"="+strconv.Itoa(svcb.Port))
+	}
+	if len(svcb.IPv4Hint) > 0 {
+		fields = append(fields, SvcParamIPv4Hint+"="+joinIPs(svcb.IPv4Hint))
+	}
+	if svcb.ECH != "" {
+		fields = append(fields, SvcParamECH+"="+svcb.ECH)
+	}
+	if len(svcb.IPv6Hint) > 0 {
+		fields = append(fields, SvcParamIPv6Hint+"="+joinIPs(svcb.IPv6Hint))
+	}
+
+	keys := make([]string, 0, len(svcb.Params))
+	for key := range svcb.Params {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if value := svcb.Params[key]; value != "" {
+			fields = append(fields, key+"="+quoteParam(value))
+		} else {
+			fields = append(fields, key)
+		}
+	}
+
+	return strings.Join(fields, " ")
+}
+
+// ParseSVCB Parses SVCB or HTTPS record content, e.g.
+// `1 . alpn=h3,h2 ipv4hint=192.0.2.1 port=8443`.
+func ParseSVCB(content string) (SVCB, error) {
+	fields, err := splitParams(content)
+	if err != nil || len(fields) < 2 {
+		return SVCB{}, fmt.Errorf("Invalid SVCB content: %q", content)
+	}
+
+	var svcb SVCB
+	if svcb.Priority, err = parseUint16(fields[0]); err != nil {
+		return SVCB{}, fmt.Errorf("Invalid SVCB priority: %q", fields[0])
+	}
+	svcb.Target = fields[1]
+
+	params := fields[2:]
+	if svcb.Priority == 0 && len(params) > 0 {
+		return SVCB{}, fmt.Errorf("Invalid SVCB content: %q, alias mode takes no parameters", content)
+	}
+
+	seen := make(map[string]bool, len(params))
+	for _, param := range params {
+		key, value, hasValue := strings.Cut(param, "=")
+		key = strings.ToLower(key)
+		if seen[key] {
+			return SVCB{}, fmt.Errorf("Invalid SVCB content: %q, duplicate parameter %s", content, key)
+		}
+		seen[key] = true
+		value = unquoteParam(value)
+
+		if !hasValue && key != SvcParamNoDefaultALPN && !isGenericKey(key) {
+			return SVCB{}, fmt.Errorf("Invalid SVCB parameter: %q, missing value", param)
+		}
+
+		switch key {
+		case SvcParamMandatory:
+			svcb.Mandatory = strings.Split(value, ",")
+		case SvcParamALPN:
+			svcb.ALPN = splitALPN(value)
+		case SvcParamNoDefaultALPN:
+			if hasValue {
+				return SVCB{}, fmt.Errorf("Invalid SVCB parameter: %q, takes no value", param)
+			}
+			svcb.NoDefaultALPN = true
+		case SvcParamPort:
+			if svcb.Port, err = parseUint16(value); err != nil {
+				return SVCB{}, fmt.Errorf("Invalid SVCB port: %q", value)
+			}
+		case SvcParamIPv4Hint, SvcParamIPv6Hint:
+			ips, err := parseIPs(value, key == SvcParamIPv4Hint)
+			if err != nil {
+				return SVCB{}, err
+			}
+			if key == SvcParamIPv4Hint {
+				svcb.IPv4Hint = ips
+			} else {
+				svcb.IPv6Hint = ips
+			}
+		case SvcParamECH:
+			svcb.ECH = value
+		default:
+			if !isGenericKey(key) {
+				return SVCB{}, fmt.Errorf("Invalid SVCB parameter key: %q", key)
+			}
+			if svcb.Params == nil {
+				svcb.Params = make(map[string]string)
+			}
+			svcb.Params[key] = value
+		}
+	}
+
+	for _, key := range svcb.Mandatory {
+		if !seen[key] {
+			return SVCB{}, fmt.Errorf("Invalid SVCB content: %q, mandatory parameter %s is missing", content, key)
+		}
+	}
+
+	return svcb, nil
+}
+
+// Splits content on white space outside of double quotes
+func splitParams(content string) ([]string, error) {
+	var fields []string
+	var field strings.Builder
+	inField, quoted := false, false
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch {
+		case c == '\\' && i+1 < len(content):
+			field.WriteByte(c)
+			field.WriteByte(content[i+1])
+			i++
+			inField = true
+		case c == '"':
+			field.WriteByte(c)
+			quoted = !quoted
+			inField = true
+		case (c == ' ' || c == '\t') && !quoted:
+			if inField {
+				fields = append(fields, field.String())
+				field.Reset()
+				inField = false
+			}
+		default:
+			field.WriteByte(c)
+			inField = true
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("Unterminated quoted content: %q", content)
+	}
+	if inField {
+		fields = append(fields, field.String())
+	}
+
+	return fields, nil
+}
+
+// Returns value quoted when it holds characters that end an unquoted value
+func quoteParam(value string) string {
+	if strings.ContainsAny(value, " \t\"") {
+		return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+	}
+
+	return value
+}
+
+func unquoteParam(value string) string {
+	if len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"' {
+		return strings.ReplaceAll(value[1:len(value)-1], `\"`, `"`)
+	}
+
+	return value
+}
+
+// Splits an alpn value on commas, undoing the escaping of commas and backslashes in
+// protocol IDs
+func splitALPN(value string) []string {
+	var ids []string
+	var id strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case c == '\\':
+			for i+1 < len(value) && value[i+1] == '\\' {
+				i++
+			}
+			if i+1 < len(value) {
+				id.WriteByte(value[i+1])
+				i++
+			}
+		case c == ',':
+			ids = append(ids, id.String())
+			id.Reset()
+		default:
+			id.WriteByte(c)
+		}
+	}
+
+	return append(ids, id.String())
+}
+
+func parseIPs(value string, v4 bool) ([]net.IP, error) {
+	var ips []net.IP
+	for _, s := range strings.Split(value, ",") {
+		ip := net.ParseIP(s)
+		if ip == nil || (ip.To4() != nil) != v4 {
+			return nil, fmt.Errorf("Invalid SVCB address hint: %q", s)
+		}
+		ips = append(ips, ip)
+	}
+
+	return ips, nil
+}
+
+func joinIPs(ips []net.IP) string {
+	s := make([]string, len(ips))
+	for i, ip := range ips {
+		s[i] = ip.String()
+	}
+
+	return strings.Join(s, ",")
+}
+
+// Checks for the keyNNNNN form of parameter keys without a name
+func isGenericKey(key string) bool {
+	if !strings.HasPrefix(key, "key") {
+		return false
+	}
+
+	_, err := parseUint16(key[len("key"):])
+	return err == nil
+}
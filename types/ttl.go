@@ -0,0 +1,63 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MaxTTL Largest TTL allowed by RFC 2181, 2^31 - 1 seconds.
+const MaxTTL = 1<<31 - 1
+
+// ErrInvalidTTL Returned for TTLs that are negative, too large or not whole seconds.
+var ErrInvalidTTL = errors.New("invalid TTL")
+
+// DurationTTL Returns d as a TTL in seconds. d must be a whole number of seconds between
+// zero and MaxTTL, which catches unit mistakes such as passing milliseconds.
+func DurationTTL(d time.Duration) (int, error) {
+	if d%time.Second != 0 {
+		return 0, fmt.Errorf("%s is not a whole number of seconds: %w", d, ErrInvalidTTL)
+	}
+
+	return ttlSeconds(int64(d / time.Second))
+}
+
+// MustDurationTTL Is like DurationTTL but panics on invalid durations, for constants.
+func MustDurationTTL(d time.Duration) int {
+	ttl, err := DurationTTL(d)
+	if err != nil {
+		panic(err)
+	}
+
+	return ttl
+}
+
+// ValidateTTL Returns an error unless ttl, in seconds, is between zero and MaxTTL.
+func ValidateTTL(ttl int) error {
+	_, err := ttlSeconds(int64(ttl))
+	return err
+}
+
+// SetTTL Sets the TTL of the record set from a duration, see DurationTTL.
+func (rrSet *ResourceRecordSet) SetTTL(d time.Duration) error {
+	ttl, err := DurationTTL(d)
+	if err != nil {
+		return err
+	}
+
+	rrSet.TTL = ttl
+	return nil
+}
+
+// TTLDuration Returns the TTL of the record set as a duration.
+func (rrSet *ResourceRecordSet) TTLDuration() time.Duration {
+	return time.Duration(rrSet.TTL) * time.Second
+}
+
+func ttlSeconds(seconds int64) (int, error) {
+	if seconds < 0 || seconds > MaxTTL {
+		return 0, fmt.Errorf("%d seconds is out of range: %w", seconds, ErrInvalidTTL)
+	}
+
+	return int(seconds), nil
+}
@@ -0,0 +1,207 @@
+// Package types contains the PowerDNS API data types shared by the client and
+// higher level packages, without any HTTP dependencies.
+package types
+
+// Covered by original license.
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ZoneInfo Data representing Zone Information, mirroring the Zone object of the API.
+type ZoneInfo struct {
+	ID                 string              `json:"id,omitempty"`
+	Name               string              `json:"name"`
+	Type               string              `json:"type,omitempty"`
+	Account            string              `json:"account"`
+	URL                string              `json:"url"`
+	LastCheck          int64               `json:"last_check"`
+	Kind               ZoneKind            `json:"kind"`
+	DNSSec             bool                `json:"dnssec"`
+	Serial             int64               `json:"serial"`
+	NotifiedSerial     int64               `json:"notified_serial"`
+	EditedSerial       int64               `json:"edited_serial,omitempty"`
+	Masters            []string            `json:"masters"`
+	MasterTSIGKeyIDs   []string            `json:"master_tsig_key_ids,omitempty"`
+	SlaveTSIGKeyIDs    []string            `json:"slave_tsig_key_ids,omitempty"`
+	Nameservers        []string            `json:"nameservers,omitempty"`
+	Presigned          bool                `json:"presigned,omitempty"`
+	SOAEdit            string              `json:"soa_edit,omitempty"`
+	SOAEditAPI         string              `json:"soa_edit_api,omitempty"`
+	APIRectify         bool                `json:"api_rectify,omitempty"`
+	NSEC3Param         string              `json:"nsec3param,omitempty"`
+	NSEC3Narrow        bool                `json:"nsec3narrow,omitempty"`
+	Catalog            string              `json:"catalog,omitempty"`
+	Zone               string              `json:"zone,omitempty"` // Zone file to import on creation
+	Records            []Record            `json:"records,omitempty"`
+	ResourceRecordSets []ResourceRecordSet `json:"rrsets,omitempty"`
+}
+
+// Record Data representing Record Information.
+type Record struct {
+	Name     string     `json:"name"`
+	Type     RecordType `json:"type"`
+	Content  string     `json:"content"`
+	TTL      int        `json:"ttl"` // For API v0
+	Disabled bool       `json:"disabled"`
+}
+
+// RecordContent Content of a record with its disabled flag, e.g. to create records
+// disabled ahead of a staged cutover.
+type RecordContent struct {
+	Content  string
+	Disabled bool
+}
+
+// ResourceRecordSet Data representing Resource Record Set Information.
+type ResourceRecordSet struct {
+	Name       string     `json:"name"`
+	Type       RecordType `json:"type"`
+	ChangeType ChangeType `json:"changetype,omitempty"`
+	TTL        int        `json:"ttl"` // For API v1
+	Records    []Record   `json:"records,omitempty"`
+	Comments   []Comment  `json:"comments,omitempty"`
+}
+
+// Comment Data representing a comment attached to a Resource Record Set.
+type Comment struct {
+	Content    string `json:"content"`
+	Account    string `json:"account"`
+	ModifiedAt int64  `json:"modified_at,omitempty"`
+}
+
+// Metadata Data representing a zone metadata kind and its values.
+type Metadata struct {
+	Kind     string   `json:"kind"`
+	Metadata []string `json:"metadata"`
+}
+
+// Cryptokey Data representing a DNSSEC key of a zone.
+type Cryptokey struct {
+	ID         int      `json:"id,omitempty"`
+	Type       string   `json:"type,omitempty"`
+	KeyType    string   `json:"keytype"`
+	Active     bool     `json:"active"`
+	Published  bool     `json:"published"`
+	DNSKey     string   `json:"dnskey,omitempty"`
+	Flags      int      `json:"flags,omitempty"`
+	DS         []string `json:"ds,omitempty"`
+	CDS        []string `json:"cds,omitempty"`
+	PrivateKey string   `json:"privatekey,omitempty"`
+	Algorithm  string   `json:"algorithm,omitempty"`
+	Bits       int      `json:"bits,omitempty"`
+}
+
+// IsKeySigningKey Returns true for KSK and CSK keys, whose DS records belong in the parent zone.
+func (key *Cryptokey) IsKeySigningKey() bool {
+	return key.KeyType == "ksk" || key.KeyType == "csk"
+}
+
+// APIVersion Data representing an API version advertised by the discovery endpoint.
+type APIVersion struct {
+	URL     string `json:"url"`
+	Version int    `json:"version"`
+}
+
+// IDSeparator separator for record identifier.
+const IDSeparator string = ":::"
+
+// ID Returns the record identifier.
+func (record *Record) ID() string {
+	return record.Name + IDSeparator + string(record.Type)
+}
+
+// ID Returns the resource record identifier.
+func (rrSet *ResourceRecordSet) ID() string {
+	return rrSet.Name + IDSeparator + string(rrSet.Type)
+}
+
+// Empty Returns true when the record set holds no records. PowerDNS returns such record
+// sets when only comments are set on a name and type.
+func (rrSet *ResourceRecordSet) Empty() bool {
+	return len(rrSet.Records) == 0
+}
+
+// ModifiedTime Returns the time the comment was last modified.
+func (comment *Comment) ModifiedTime() time.Time {
+	return time.Unix(comment.ModifiedAt, 0)
+}
+
+// LastModified Returns the most recent modification time of the comments of the record
+// set, the only modification time PowerDNS records, or the zero time without comments.
+func (rrSet *ResourceRecordSet) LastModified() time.Time {
+	var last time.Time
+	for i := range rrSet.Comments {
+		if modified := rrSet.Comments[i].ModifiedTime(); rrSet.Comments[i].ModifiedAt > 0 && modified.After(last) {
+			last = modified
+		}
+	}
+
+	return last
+}
+
+// NewRecordSet Returns the record set of name and type holding contents, each enabled
+// or disabled as given.
+func NewRecordSet(name string, tpe RecordType, ttl int, contents []RecordContent) ResourceRecordSet {
+	rrSet := ResourceRecordSet{Name: name, Type: tpe, TTL: ttl}
+	for _, content := range contents {
+		rrSet.Records = append(rrSet.Records, Record{Content: content.Content, Disabled: content.Disabled})
+	}
+
+	return rrSet
+}
+
+// Flatten Returns every record of the record set in the v0 record structure,
+// carrying the set name, type and TTL alongside each content and disabled flag.
+func (rrSet *ResourceRecordSet) Flatten() []Record {
+	records := make([]Record, 0, len(rrSet.Records))
+	for _, record := range rrSet.Records {
+		records = append(records, Record{
+			Name:     rrSet.Name,
+			Type:     rrSet.Type,
+			Content:  record.Content,
+			TTL:      rrSet.TTL,
+			Disabled: record.Disabled,
+		})
+	}
+
+	return records
+}
+
+// Clone Returns a deep copy of the zone, so that callers sharing a result can modify it.
+func (zone *ZoneInfo) Clone() *ZoneInfo {
+	clone := *zone
+	clone.Masters = append([]string(nil), zone.Masters...)
+	clone.MasterTSIGKeyIDs = append([]string(nil), zone.MasterTSIGKeyIDs...)
+	clone.SlaveTSIGKeyIDs = append([]string(nil), zone.SlaveTSIGKeyIDs...)
+	clone.Nameservers = append([]string(nil), zone.Nameservers...)
+	clone.Records = append([]Record(nil), zone.Records...)
+	clone.ResourceRecordSets = nil
+	for _, rrSet := range zone.ResourceRecordSets {
+		clone.ResourceRecordSets = append(clone.ResourceRecordSets, *rrSet.Clone())
+	}
+
+	return &clone
+}
+
+// Clone Returns a deep copy of the record set.
+func (rrSet *ResourceRecordSet) Clone() *ResourceRecordSet {
+	clone := *rrSet
+	clone.Records = append([]Record(nil), rrSet.Records...)
+	clone.Comments = append([]Comment(nil), rrSet.Comments...)
+
+	return &clone
+}
+
+// ParseID Returns name and type of record or record set based on it's ID
+func ParseID(recID string) (string, string, error) {
+	s := strings.Split(recID, IDSeparator)
+
+	if len(s) == 2 {
+		return s[0], s[1], nil
+	}
+
+	return "", "", fmt.Errorf("Unknown record ID format")
+}
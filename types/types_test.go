@@ -0,0 +1,59 @@
+package types
+
+import (
+	"fmt"
+	"testing"
+)
+
+// Returns n record sets of a zone with several A records each, in reverse order
+func largeZone(n int) []ResourceRecordSet {
+	rrSets := make([]ResourceRecordSet, 0, n)
+	for i := n; i > 0; i-- {
+		rrSet := ResourceRecordSet{Name: fmt.Sprintf("host%06d.example.com.", i), Type: TypeA, TTL: 300}
+		for j := 4; j > 0; j-- {
+			rrSet.Records = append(rrSet.Records, Record{Content: fmt.Sprintf("10.%d.%d.%d", i>>8&0xff, i&0xff, j)})
+		}
+		rrSets = append(rrSets, rrSet)
+	}
+
+	return rrSets
+}
+
+func BenchmarkFlatten(b *testing.B) {
+	rrSets := largeZone(10000)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		records := make([]Record, 0, 4*len(rrSets))
+		for j := range rrSets {
+			records = append(records, rrSets[j].Flatten()...)
+		}
+	}
+}
+
+func TestZoneInfoCloneIsDeep(t *testing.T) {
+	zone := &ZoneInfo{
+		Name:               "example.com.",
+		Masters:            []string{"192.0.2.53"},
+		MasterTSIGKeyIDs:   []string{"primary-key."},
+		SlaveTSIGKeyIDs:    []string{"secondary-key."},
+		Nameservers:        []string{"ns1.example.com."},
+		Records:            []Record{{Content: "192.0.2.1"}},
+		ResourceRecordSets: []ResourceRecordSet{{Name: "www.example.com.", Records: []Record{{Content: "192.0.2.1"}}}},
+	}
+
+	clone := zone.Clone()
+	clone.Masters[0] = "changed"
+	clone.MasterTSIGKeyIDs[0] = "changed"
+	clone.SlaveTSIGKeyIDs[0] = "changed"
+	clone.Nameservers[0] = "changed"
+	clone.Records[0].Content = "changed"
+	clone.ResourceRecordSets[0].Records[0].Content = "changed"
+
+	if zone.Masters[0] == "changed" || zone.MasterTSIGKeyIDs[0] == "changed" || zone.SlaveTSIGKeyIDs[0] == "changed" ||
+		zone.Nameservers[0] == "changed" || zone.Records[0].Content == "changed" ||
+		zone.ResourceRecordSets[0].Records[0].Content == "changed" {
+		t.Errorf("modifying the clone changed the zone: %+v", zone)
+	}
+}
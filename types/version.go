@@ -0,0 +1,65 @@
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ServerInfo Data representing a PowerDNS server.
+type ServerInfo struct {
+	Type       string `json:"type"`
+	ID         string `json:"id"`
+	DaemonType string `json:"daemon_type"`
+	Version    string `json:"version"`
+	URL        string `json:"url"`
+	ConfigURL  string `json:"config_url"`
+	ZonesURL   string `json:"zones_url"`
+}
+
+// Version PowerDNS server version.
+type Version struct {
+	Major int
+	Minor int
+	Patch int
+}
+
+// ParseVersion Parses versions like "4.8.3", "4.9.0-alpha1" or "4.1.x"; missing or
+// non numeric minor and patch parts are read as zero.
+func ParseVersion(s string) (Version, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "v")
+	if i := strings.IndexAny(s, "-+ "); i >= 0 {
+		s = s[:i]
+	}
+
+	parts := strings.SplitN(s, ".", 3)
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			if i == 0 {
+				return Version{}, fmt.Errorf("Invalid version: %q", s)
+			}
+			break
+		}
+		numbers[i] = n
+	}
+
+	return Version{Major: numbers[0], Minor: numbers[1], Patch: numbers[2]}, nil
+}
+
+// String Returns the version as major.minor.patch.
+func (version Version) String() string {
+	return fmt.Sprintf("%d.%d.%d", version.Major, version.Minor, version.Patch)
+}
+
+// AtLeast Returns true when version is the same as or newer than other.
+func (version Version) AtLeast(other Version) bool {
+	if version.Major != other.Major {
+		return version.Major > other.Major
+	}
+	if version.Minor != other.Minor {
+		return version.Minor > other.Minor
+	}
+	return version.Patch >= other.Patch
+}
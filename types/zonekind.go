@@ -0,0 +1,71 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ZoneKind Kind of a zone, e.g. "Native" or "Primary".
+type ZoneKind string
+
+// Zone kinds of every PowerDNS version. PowerDNS 4.5 renamed Master and Slave to Primary
+// and Secondary, and 4.7 added the catalog zone kinds Producer and Consumer.
+const (
+	KindNative    ZoneKind = "Native"
+	KindPrimary   ZoneKind = "Primary"
+	KindSecondary ZoneKind = "Secondary"
+	KindMaster    ZoneKind = "Master"
+	KindSlave     ZoneKind = "Slave"
+	KindProducer  ZoneKind = "Producer"
+	KindConsumer  ZoneKind = "Consumer"
+)
+
+var zoneKinds = []ZoneKind{KindNative, KindPrimary, KindSecondary, KindMaster, KindSlave, KindProducer, KindConsumer}
+
+// ParseZoneKind Returns the zone kind named by s, case-insensitively, or an error for
+// unknown kinds.
+func ParseZoneKind(s string) (ZoneKind, error) {
+	for _, kind := range zoneKinds {
+		if strings.EqualFold(s, string(kind)) {
+			return kind, nil
+		}
+	}
+
+	return "", fmt.Errorf("Unknown zone kind: %q", s)
+}
+
+// Normalize Returns the kind under its current name: Master becomes Primary and Slave
+// Secondary. Other kinds are returned unchanged.
+func (kind ZoneKind) Normalize() ZoneKind {
+	switch kind {
+	case KindMaster:
+		return KindPrimary
+	case KindSlave:
+		return KindSecondary
+	}
+
+	return kind
+}
+
+// Legacy Returns the kind under the name used before PowerDNS 4.5: Primary becomes Master
+// and Secondary Slave. Other kinds are returned unchanged.
+func (kind ZoneKind) Legacy() ZoneKind {
+	switch kind {
+	case KindPrimary:
+		return KindMaster
+	case KindSecondary:
+		return KindSlave
+	}
+
+	return kind
+}
+
+// IsPrimary Returns true for Primary and Master zones.
+func (kind ZoneKind) IsPrimary() bool {
+	return kind.Normalize() == KindPrimary
+}
+
+// IsSecondary Returns true for Secondary and Slave zones.
+func (kind ZoneKind) IsSecondary() bool {
+	return kind.Normalize() == KindSecondary
+}
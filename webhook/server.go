@@ -0,0 +1,164 @@
+// Package webhook exposes a small REST endpoint applying rrset changes through the
+// client, so teams can self-serve DNS changes without holding the PowerDNS API key.
+//
+// Changes are sent as PATCH /zones/<zone> with a JSON body {"rrsets": [...]} in the
+// format of the PowerDNS API, authenticated with "Authorization: Bearer <token>".
+package webhook
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/dmportella/powerdns/client"
+	"github.com/dmportella/powerdns/types"
+)
+
+// MaxBodySize limits the size of change requests.
+const MaxBodySize = 1 << 20
+
+// Rule Allows changes to the rrsets of Zone whose name matches NamePattern, a path.Match
+// pattern over the fully qualified name such as "*.dev.example.com.", and whose type is
+// one of Types. An empty pattern matches every name, empty Types every type.
+type Rule struct {
+	Zone        string
+	NamePattern string
+	Types       []types.RecordType
+}
+
+// Server HTTP handler applying the rrset changes allowed by its rules.
+type Server struct {
+	Client client.RecordAPI
+	// Token clients must present as a bearer token.
+	Token string
+	Rules []Rule
+	// Logger receives a line per applied, rejected or failed request; nil disables logging.
+	Logger *log.Logger
+}
+
+type changeRequest struct {
+	RecordSets []types.ResourceRecordSet `json:"rrsets"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// ServeHTTP Handles a change request.
+func (server *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	// correlate the request with the API calls it makes and the log lines it produces
+	id := req.Header.Get(client.RequestIDHeader)
+	if id == "" {
+		id = client.NewRequestID()
+	}
+	req = req.WithContext(client.ContextWithRequestID(req.Context(), id))
+	w.Header().Set(client.RequestIDHeader, id)
+
+	if !server.authorized(req) {
+		server.fail(w, req, http.StatusUnauthorized, errors.New("invalid or missing token"))
+		return
+	}
+
+	zone := strings.TrimPrefix(req.URL.Path, "/zones/")
+	if zone == req.URL.Path || zone == "" || strings.Contains(zone, "/") {
+		server.fail(w, req, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	if req.Method != "PATCH" {
+		w.Header().Set("Allow", "PATCH")
+		server.fail(w, req, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		return
+	}
+
+	changes := new(changeRequest)
+	if err := json.NewDecoder(http.MaxBytesReader(w, req.Body, MaxBodySize)).Decode(changes); err != nil {
+		server.fail(w, req, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	if len(changes.RecordSets) == 0 {
+		server.fail(w, req, http.StatusBadRequest, errors.New("no rrsets to change"))
+		return
+	}
+
+	for _, rrSet := range changes.RecordSets {
+		if err := rrSet.ChangeType.Validate(); err != nil {
+			server.fail(w, req, http.StatusBadRequest, fmt.Errorf("record set: %s, %w", rrSet.ID(), err))
+			return
+		}
+		if !server.allowed(zone, &rrSet) {
+			server.fail(w, req, http.StatusForbidden, fmt.Errorf("record set: %s is not allowed in zone %s", rrSet.ID(), zone))
+			return
+		}
+	}
+
+	if err := client.RecordAPIWithContext(req.Context(), server.Client).PatchRecordSets(zone, changes.RecordSets); err != nil {
+		status := http.StatusBadGateway
+		var apiErr *client.APIError
+		if errors.As(err, &apiErr) && apiErr.StatusCode >= 400 && apiErr.StatusCode < 500 {
+			status = http.StatusUnprocessableEntity
+		}
+		server.fail(w, req, status, err)
+		return
+	}
+
+	server.logf("%s %s [%s]: applied %d rrset changes", req.Method, req.URL.Path, id, len(changes.RecordSets))
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Requires the Bearer scheme and compares the token in constant time
+func (server *Server) authorized(req *http.Request) bool {
+	token, ok := strings.CutPrefix(req.Header.Get("Authorization"), "Bearer ")
+	if !ok || server.Token == "" || token == "" {
+		return false
+	}
+
+	return subtle.ConstantTimeCompare([]byte(token), []byte(server.Token)) == 1
+}
+
+// Checks rrSet against the rules of zone
+func (server *Server) allowed(zone string, rrSet *types.ResourceRecordSet) bool {
+	if !types.IsSubdomainOf(rrSet.Name, zone) {
+		return false
+	}
+
+	name := strings.ToLower(rrSet.Name)
+	for _, rule := range server.Rules {
+		if !types.EqualNames(rule.Zone, zone) {
+			continue
+		}
+		if rule.NamePattern != "" {
+			if matched, err := path.Match(strings.ToLower(rule.NamePattern), name); err != nil || !matched {
+				continue
+			}
+		}
+		if len(rule.Types) == 0 {
+			return true
+		}
+		for _, tpe := range rule.Types {
+			if tpe == rrSet.Type {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (server *Server) fail(w http.ResponseWriter, req *http.Request, status int, err error) {
+	server.logf("%s %s [%s]: %d %s", req.Method, req.URL.Path, client.RequestIDFromContext(req.Context()), status, err)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: err.Error()})
+}
+
+func (server *Server) logf(format string, args ...interface{}) {
+	if server.Logger != nil {
+		server.Logger.Printf(format, args...)
+	}
+}
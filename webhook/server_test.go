@@ -0,0 +1,43 @@
+package webhook
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dmportella/powerdns/memory"
+	"github.com/dmportella/powerdns/types"
+)
+
+func TestServeHTTPRequiresBearerToken(t *testing.T) {
+	provider := memory.New()
+	if _, err := provider.CreateZone(types.ZoneInfo{Name: "example.com."}, nil); err != nil {
+		t.Fatal(err)
+	}
+	server := &Server{Client: provider, Token: "secret", Rules: []Rule{{Zone: "example.com."}}}
+
+	cases := map[string]int{
+		"":              http.StatusUnauthorized,
+		"secret":        http.StatusUnauthorized,
+		"bearer secret": http.StatusUnauthorized,
+		"Basic secret":  http.StatusUnauthorized,
+		"Bearer wrong":  http.StatusUnauthorized,
+		"Bearer ":       http.StatusUnauthorized,
+		"Bearer secret": http.StatusNoContent,
+	}
+
+	for authorization, want := range cases {
+		body := `{"rrsets": [{"name": "www.example.com.", "type": "A", "ttl": 300, "changetype": "REPLACE", "records": [{"content": "192.0.2.1"}]}]}`
+		req := httptest.NewRequest("PATCH", "/zones/example.com.", strings.NewReader(body))
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+
+		recorder := httptest.NewRecorder()
+		server.ServeHTTP(recorder, req)
+		if recorder.Code != want {
+			t.Errorf("Authorization %q: status %d, want %d", authorization, recorder.Code, want)
+		}
+	}
+}